@@ -17,11 +17,24 @@ limitations under the License.
 package main
 
 import (
+	"os"
+	"strconv"
+
 	"knative.dev/eventing/pkg/adapter/v2"
 
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/sharedmain"
 	"github.com/triggermesh/knative-sources/pkg/adapter/slacksource"
 )
 
+// envMultiTenant is set by the reconciler on adapters which serve all the
+// SlackSources of a namespace.
+const envMultiTenant = "SLACK_MULTI_TENANT"
+
 func main() {
+	if mt, _ := strconv.ParseBool(os.Getenv(envMultiTenant)); mt {
+		sharedmain.MainWithController(slacksource.NewEnvConfig, slacksource.NewController, slacksource.NewMTAdapter)
+		return
+	}
+
 	adapter.Main("slack", slacksource.EnvAccessor, slacksource.NewAdapter)
 }
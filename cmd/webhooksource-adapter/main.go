@@ -17,11 +17,24 @@ limitations under the License.
 package main
 
 import (
+	"os"
+	"strconv"
+
 	"knative.dev/eventing/pkg/adapter/v2"
 
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/sharedmain"
 	"github.com/triggermesh/knative-sources/pkg/adapter/webhooksource"
 )
 
+// envMultiTenant is set by the reconciler on adapters which serve all the
+// WebhookSources of a namespace.
+const envMultiTenant = "WEBHOOK_MULTI_TENANT"
+
 func main() {
+	if mt, _ := strconv.ParseBool(os.Getenv(envMultiTenant)); mt {
+		sharedmain.MainWithController(webhooksource.NewEnvConfig, webhooksource.NewController, webhooksource.NewMTAdapter)
+		return
+	}
+
 	adapter.Main("webhook", webhooksource.EnvAccessor, webhooksource.NewAdapter)
 }
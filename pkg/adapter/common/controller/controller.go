@@ -18,7 +18,18 @@ limitations under the License.
 // source adapters.
 package controller
 
-import "knative.dev/pkg/controller"
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"k8s.io/client-go/tools/cache"
+
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
 
 // Opts returns a callback function that sets the controller's agent name and
 // configures the reconciler to skip status updates.
@@ -30,3 +41,31 @@ func Opts(component string) controller.OptionsFn {
 		}
 	}
 }
+
+// DeregisterFunc deregisters the HTTP handler of a source from a multi-tenant
+// adapter.
+type DeregisterFunc func(context.Context, v1alpha1.EventSource) error
+
+// DeregisterHandlerOf returns an informer event handler which deregisters the
+// HTTP handler of deleted sources using the given function.
+// Sources served by multi-tenant adapters don't carry any finalizer, so
+// deletions can not be observed by reconcilers, which only ever see objects
+// that still exist.
+func DeregisterHandlerOf(ctx context.Context, deregister DeregisterFunc) func(interface{}) {
+	logger := logging.FromContext(ctx)
+
+	return func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		src, ok := obj.(v1alpha1.EventSource)
+		if !ok {
+			return
+		}
+
+		if err := deregister(ctx, src); err != nil {
+			logger.Errorw("Failed to deregister HTTP handler", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"knative.dev/pkg/logging"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestDeregisterHandlerOf(t *testing.T) {
+	src := &v1alpha1.WebhookSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testns",
+			Name:      "test",
+		},
+	}
+
+	testCases := map[string]struct {
+		deletedObj interface{}
+		expectSrc  v1alpha1.EventSource
+	}{
+		"Deleted object": {
+			deletedObj: src,
+			expectSrc:  src,
+		},
+		"Tombstone": {
+			deletedObj: cache.DeletedFinalStateUnknown{Key: "testns/test", Obj: src},
+			expectSrc:  src,
+		},
+		"Not a source": {
+			deletedObj: &metav1.PartialObjectMetadata{},
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			var deregistered v1alpha1.EventSource
+			deregister := func(_ context.Context, src v1alpha1.EventSource) error {
+				deregistered = src
+				return nil
+			}
+
+			ctx := logging.WithLogger(context.Background(), logtesting.TestLogger(t))
+			DeregisterHandlerOf(ctx, deregister)(tc.deletedObj)
+
+			assert.Equal(t, tc.expectSrc, deregistered)
+		})
+	}
+}
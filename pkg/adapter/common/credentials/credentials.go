@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentials allows authenticating requests with any of several valid
+// credentials, so that secrets can be rotated without downtime.
+package credentials
+
+import (
+	"context"
+	"crypto/subtle"
+	"strconv"
+
+	"go.opencensus.io/tag"
+)
+
+// IDs of credentials, as reported in metrics.
+const (
+	primaryID          = "primary"
+	additionalIDPrefix = "additional-"
+)
+
+// Set is an ordered set of valid credentials.
+type Set struct {
+	creds []credential
+
+	// identify the owner of the credentials in metrics
+	ownerTags []tag.Mutator
+}
+
+// credential is a single credential value along with an identifier which
+// doesn't disclose that value.
+type credential struct {
+	id    string
+	value string
+}
+
+// New returns a Set containing the given primary credential, followed by the
+// given additional credentials. Empty values are ignored but still count
+// towards the numbering of additional credentials, so that the ID of a
+// credential always matches its position in the source's spec.
+func New(primary string, additional ...string) *Set {
+	s := &Set{}
+
+	if primary != "" {
+		s.creds = append(s.creds, credential{
+			id:    primaryID,
+			value: primary,
+		})
+	}
+
+	for i, v := range additional {
+		if v == "" {
+			continue
+		}
+		s.creds = append(s.creds, credential{
+			id:    additionalIDPrefix + strconv.Itoa(i),
+			value: v,
+		})
+	}
+
+	return s
+}
+
+// WithOwner returns a copy of the Set which reports matches in metrics along
+// with the namespace and name of the source that owns its credentials, and
+// the ID of the application they belong to, if not empty.
+func (s *Set) WithOwner(namespace, name, appID string) *Set {
+	if s == nil {
+		return nil
+	}
+
+	owned := *s
+	owned.ownerTags = []tag.Mutator{
+		tag.Upsert(namespaceKey, namespace),
+		tag.Upsert(nameKey, name),
+	}
+	if appID != "" {
+		owned.ownerTags = append(owned.ownerTags, tag.Upsert(appIDKey, appID))
+	}
+
+	return &owned
+}
+
+// Empty returns whether the Set contains no credential.
+func (s *Set) Empty() bool {
+	return s == nil || len(s.creds) == 0
+}
+
+// Match returns whether any of the credentials in the Set satisfies the given
+// predicate. The first matching credential is recorded in metrics.
+func (s *Set) Match(ctx context.Context, matches func(value string) bool) bool {
+	if s == nil {
+		return false
+	}
+
+	for _, c := range s.creds {
+		if matches(c.value) {
+			reportMatch(ctx, c.id, s.ownerTags)
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchValue returns whether the given value is equal to any of the
+// credentials in the Set. Values are compared in constant time.
+func (s *Set) MatchValue(ctx context.Context, val string) bool {
+	return s.Match(ctx, func(c string) bool {
+		return subtle.ConstantTimeCompare([]byte(c), []byte(val)) == 1
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	testCases := map[string]struct {
+		primary     string
+		additional  []string
+		expectCreds []credential
+	}{
+		"No credential": {
+			expectCreds: nil,
+		},
+		"Primary only": {
+			primary: "p",
+			expectCreds: []credential{
+				{id: "primary", value: "p"},
+			},
+		},
+		"Primary and additional": {
+			primary:    "p",
+			additional: []string{"a0", "a1"},
+			expectCreds: []credential{
+				{id: "primary", value: "p"},
+				{id: "additional-0", value: "a0"},
+				{id: "additional-1", value: "a1"},
+			},
+		},
+		"Empty values are skipped": {
+			primary:    "",
+			additional: []string{"", "a1"},
+			expectCreds: []credential{
+				{id: "additional-1", value: "a1"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			s := New(tc.primary, tc.additional...)
+			assert.Equal(t, tc.expectCreds, s.creds)
+			assert.Equal(t, len(tc.expectCreds) == 0, s.Empty())
+		})
+	}
+}
+
+func TestMatchValue(t *testing.T) {
+	s := New("current", "previous")
+
+	ctx := context.Background()
+
+	assert.True(t, s.MatchValue(ctx, "current"))
+	assert.True(t, s.MatchValue(ctx, "previous"))
+	assert.False(t, s.MatchValue(ctx, "other"))
+	assert.False(t, s.MatchValue(ctx, ""))
+
+	var nilSet *Set
+	assert.False(t, nilSet.MatchValue(ctx, "current"))
+	assert.True(t, nilSet.Empty())
+}
+
+func TestWithOwner(t *testing.T) {
+	s := New("current", "previous")
+
+	owned := s.WithOwner("ns", "name", "A0123")
+	assert.Equal(t, s.creds, owned.creds)
+	assert.Len(t, owned.ownerTags, 3)
+	assert.Empty(t, s.ownerTags, "The original Set should be left unchanged")
+
+	assert.Len(t, s.WithOwner("ns", "name", "").ownerTags, 2)
+	assert.True(t, owned.MatchValue(context.Background(), "previous"))
+
+	var nilSet *Set
+	assert.Nil(t, nilSet.WithOwner("ns", "name", ""))
+}
+
+func TestMatch(t *testing.T) {
+	s := New("current", "previous")
+
+	var tried []string
+	matched := s.Match(context.Background(), func(v string) bool {
+		tried = append(tried, v)
+		return v == "current"
+	})
+
+	assert.True(t, matched)
+	assert.Equal(t, []string{"current"}, tried, "Matching should stop at the first match")
+}
+
+func TestFromEnv(t *testing.T) {
+	const prefix = "TEST_CREDENTIALS"
+
+	for k, v := range map[string]string{
+		prefix + "_0": "a",
+		prefix + "_1": "b",
+		prefix + "_3": "d", // not sequential, ignored
+	} {
+		k := k
+		os.Setenv(k, v)
+		t.Cleanup(func() { os.Unsetenv(k) })
+	}
+
+	assert.Equal(t, []string{"a", "b"}, FromEnv(prefix))
+	assert.Nil(t, FromEnv("TEST_CREDENTIALS_UNSET"))
+}
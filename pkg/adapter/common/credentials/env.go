@@ -0,0 +1,41 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"os"
+	"strconv"
+)
+
+// FromEnv returns the values of the environment variables which names consist
+// of the given prefix followed by an underscore and a sequential index starting
+// at 0 (e.g. PREFIX_0, PREFIX_1, ...). The enumeration stops at the first
+// missing index.
+//
+// Such variables are used for propagating lists of secrets, because each
+// variable can only reference a single Kubernetes Secret key.
+func FromEnv(prefix string) []string {
+	var vals []string
+
+	for i := 0; ; i++ {
+		val, ok := os.LookupEnv(prefix + "_" + strconv.Itoa(i))
+		if !ok {
+			return vals
+		}
+		vals = append(vals, val)
+	}
+}
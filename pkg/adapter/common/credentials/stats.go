@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"log"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/metrics/metricskey"
+)
+
+// matchCountM is a counter which records the number of requests authenticated
+// by each credential.
+var matchCountM = stats.Int64(
+	"credential_match_count",
+	"Number of requests authenticated by a given credential",
+	stats.UnitDimensionless,
+)
+
+var (
+	// credentialKey is the tag which identifies the credential that
+	// authenticated a request, e.g. "primary" or "additional-0".
+	credentialKey = tag.MustNewKey("credential")
+
+	// namespaceKey and nameKey are the tags which identify the source that
+	// owns the credential, as in the event metrics of Knative sources.
+	namespaceKey = tag.MustNewKey(metricskey.LabelNamespaceName)
+	nameKey      = tag.MustNewKey(metricskey.LabelName)
+
+	// appIDKey is the tag which identifies the application the credential
+	// belongs to, for sources which accept requests from several
+	// applications (e.g. Slack apps).
+	appIDKey = tag.MustNewKey("app_id")
+)
+
+func init() {
+	register()
+}
+
+func register() {
+	err := metrics.RegisterResourceView(
+		&view.View{
+			Description: matchCountM.Description(),
+			Measure:     matchCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{credentialKey, namespaceKey, nameKey, appIDKey},
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %s", err)
+	}
+}
+
+// reportMatch captures a request authenticated by the credential with the
+// given ID, owned by the entity identified by the given tags.
+func reportMatch(ctx context.Context, id string, ownerTags []tag.Mutator) {
+	metrics.Record(ctx, matchCountM.M(1),
+		stats.WithTags(append([]tag.Mutator{tag.Upsert(credentialKey, id)}, ownerTags...)...))
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dedup allows receive adapters to suppress duplicate deliveries of
+// the same occurrence, typically caused by providers retrying requests, by
+// remembering the idempotency keys of recently processed requests.
+package dedup
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the default duration for which keys are remembered.
+const DefaultTTL = time.Hour
+
+// Cache remembers keys for a limited duration.
+type Cache struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	// key -> expiration time
+	keys map[string]time.Time
+	// time of the last removal of expired keys
+	purgedAt time.Time
+
+	// allows mocking the current time in tests
+	now func() time.Time
+}
+
+// New returns a Cache which remembers keys for the given duration.
+// A duration lower than or equal to zero is replaced by DefaultTTL.
+func New(ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Cache{
+		ttl:      ttl,
+		keys:     make(map[string]time.Time),
+		purgedAt: time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Reserve remembers the given key for the duration of the cache's TTL, unless
+// it was already added to the cache less than a TTL ago. It returns whether
+// the key was reserved.
+//
+// Keys are reserved before the corresponding request is processed, so that
+// concurrent duplicates are suppressed. Callers should release a reserved key
+// if the request couldn't be processed, so that retries are not suppressed.
+func (c *Cache) Reserve(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	if exp, ok := c.keys[key]; ok && now.Before(exp) {
+		return false
+	}
+
+	c.keys[key] = now.Add(c.ttl)
+
+	// expired keys are removed lazily, at most once per TTL, to bound the
+	// size of the cache without requiring a background routine
+	if now.Sub(c.purgedAt) >= c.ttl {
+		for k, exp := range c.keys {
+			if !now.Before(exp) {
+				delete(c.keys, k)
+			}
+		}
+		c.purgedAt = now
+	}
+
+	return true
+}
+
+// Release forgets the given key.
+func (c *Cache) Release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.keys, key)
+}
+
+// Len returns the number of keys currently held by the cache, including
+// expired keys which were not removed yet.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.keys)
+}
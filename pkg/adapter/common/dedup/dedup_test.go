@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	c := New(time.Minute)
+	c.now = func() time.Time { return now }
+	c.purgedAt = now
+
+	assert.True(t, c.Reserve("k1"), "Unknown key was not reserved")
+	assert.False(t, c.Reserve("k1"), "Key was reserved twice")
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, c.Reserve("k2"), "Unknown key was not reserved")
+	assert.False(t, c.Reserve("k1"), "Key expired before its TTL")
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, c.Reserve("k1"), "Key did not expire after its TTL")
+	assert.False(t, c.Reserve("k2"), "Key expired before its TTL")
+	assert.Equal(t, 2, c.Len())
+
+	// expired keys are removed upon the next reservation, at most once per TTL
+	now = now.Add(time.Minute)
+	assert.True(t, c.Reserve("k3"))
+	assert.Equal(t, 1, c.Len(), "Expired keys were not removed")
+
+	c.Release("k3")
+	assert.Equal(t, 0, c.Len())
+	assert.True(t, c.Reserve("k3"), "Released key was not reserved")
+}
+
+func TestReserveConcurrent(t *testing.T) {
+	c := New(time.Minute)
+
+	const callers = 10
+
+	var reserved int32
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if c.Reserve("k") {
+				atomic.AddInt32(&reserved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 1, reserved, "Key should have been reserved exactly once")
+}
+
+func TestNewDefaultTTL(t *testing.T) {
+	c := New(0)
+	assert.Equal(t, DefaultTTL, c.ttl)
+}
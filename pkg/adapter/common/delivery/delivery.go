@@ -0,0 +1,245 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package delivery allows receive adapters to acknowledge incoming requests
+// before the corresponding events are delivered to the sink, by buffering
+// those events in a bounded in-memory queue.
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrQueueFull is returned by Enqueue when the queue can not accept any more
+// events. Callers are expected to apply backpressure to their own clients.
+var ErrQueueFull = errors.New("delivery queue is full")
+
+// ErrQueueClosed is returned by Enqueue after the queue started draining.
+var ErrQueueClosed = errors.New("delivery queue is closed")
+
+// Default values for the queue's configuration.
+const (
+	DefaultSize         = 100
+	DefaultWorkers      = 1
+	DefaultRetries      = 3
+	DefaultBackoffDelay = time.Second
+)
+
+// Config contains the configuration of a Queue.
+type Config struct {
+	// Maximum number of events buffered in the queue.
+	Size int
+	// Number of events delivered concurrently.
+	Workers int
+	// Number of retries upon failure to deliver an event.
+	Retries int
+	// Delay before the first retry, doubled after every attempt.
+	BackoffDelay time.Duration
+	// Optional target of the events which could not be delivered after all
+	// retries. Such events are dropped when unset.
+	DeadLetterSink string
+	// Optional name identifying the queue in metrics, for processes which
+	// run multiple queues.
+	Name string
+}
+
+// Queue delivers CloudEvents asynchronously.
+type Queue struct {
+	logger *zap.SugaredLogger
+
+	ceClient cloudevents.Client
+	cfg      Config
+
+	// guards against sends on a closed channel
+	mu     sync.RWMutex
+	closed bool
+
+	events chan queuedEvent
+	wg     sync.WaitGroup
+}
+
+// queuedEvent is an event waiting to be delivered, along with the target it
+// should be delivered to, if different from the client's default target.
+type queuedEvent struct {
+	event  cloudevents.Event
+	target string
+}
+
+// New returns a Queue that delivers events using the given CloudEvents
+// client. Unset configuration values are replaced by their default.
+func New(ceClient cloudevents.Client, cfg Config, logger *zap.SugaredLogger) *Queue {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultSize
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = DefaultRetries
+	}
+	if cfg.BackoffDelay <= 0 {
+		cfg.BackoffDelay = DefaultBackoffDelay
+	}
+
+	return &Queue{
+		logger:   logger,
+		ceClient: ceClient,
+		cfg:      cfg,
+		events:   make(chan queuedEvent, cfg.Size),
+	}
+}
+
+// Start runs the queue's workers. It returns immediately.
+func (q *Queue) Start() {
+	q.wg.Add(q.cfg.Workers)
+
+	for i := 0; i < q.cfg.Workers; i++ {
+		go func() {
+			defer q.wg.Done()
+
+			for e := range q.events {
+				q.reportDepth()
+				q.deliver(e)
+			}
+		}()
+	}
+}
+
+// Enqueue schedules the given event for delivery. The target attached to ctx
+// (see cloudevents.ContextWithTarget), if any, is preserved.
+// It never blocks, and returns ErrQueueFull when the queue is at capacity.
+func (q *Queue) Enqueue(ctx context.Context, event cloudevents.Event) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	// Assign the ID now rather than let the client assign a new one on
+	// every attempt, so that the sink sees retries as the same event.
+	if event.ID() == "" {
+		event.SetID(uuid.New().String())
+	}
+
+	e := queuedEvent{
+		event: event,
+	}
+	if t := cloudevents.TargetFromContext(ctx); t != nil {
+		e.target = t.String()
+	}
+
+	select {
+	case q.events <- e:
+		q.reportDepth()
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Len returns the number of events currently waiting to be delivered.
+func (q *Queue) Len() int {
+	return len(q.events)
+}
+
+// Drain stops accepting new events and blocks until all buffered events have
+// been processed, or until ctx is done.
+func (q *Queue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.events)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d event(s) not delivered: %w", q.Len(), ctx.Err())
+	}
+}
+
+// deliver sends the given event, retrying with an exponential backoff in
+// case of failure.
+func (q *Queue) deliver(e queuedEvent) {
+	ctx := context.Background()
+	if e.target != "" {
+		ctx = cloudevents.ContextWithTarget(ctx, e.target)
+	}
+
+	backoff := q.cfg.BackoffDelay
+
+	var result error
+	for attempt := 0; attempt <= q.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		if result = q.ceClient.Send(ctx, e.event); cloudevents.IsACK(result) {
+			return
+		}
+	}
+
+	q.logger.Errorw("Failed to deliver event after retries",
+		zap.String("id", e.event.ID()),
+		zap.Int("attempts", q.cfg.Retries+1),
+		zap.Error(result))
+
+	q.deadLetter(e.event)
+}
+
+// deadLetter sends the given undeliverable event to the dead-letter sink, if
+// one is configured. Otherwise the event is dropped.
+func (q *Queue) deadLetter(event cloudevents.Event) {
+	if q.cfg.DeadLetterSink == "" {
+		reportUndelivered(q.cfg.Name, false)
+		return
+	}
+
+	ctx := cloudevents.ContextWithTarget(context.Background(), q.cfg.DeadLetterSink)
+
+	if result := q.ceClient.Send(ctx, event); !cloudevents.IsACK(result) {
+		q.logger.Errorw("Failed to send event to the dead-letter sink",
+			zap.String("id", event.ID()),
+			zap.Error(result))
+		reportUndelivered(q.cfg.Name, false)
+		return
+	}
+
+	reportUndelivered(q.cfg.Name, true)
+}
+
+// reportDepth captures the current number of events waiting in the queue.
+func (q *Queue) reportDepth() {
+	reportQueueDepth(q.cfg.Name, len(q.events))
+}
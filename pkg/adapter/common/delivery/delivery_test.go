@@ -0,0 +1,156 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestQueue(t *testing.T) {
+	t.Run("events are delivered", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		q := New(ceClient, Config{}, logtesting.TestLogger(t))
+		q.Start()
+
+		ctx := cloudevents.ContextWithTarget(context.Background(), "http://sink.example.com")
+
+		require.NoError(t, q.Enqueue(ctx, newEvent("1")))
+		require.NoError(t, q.Enqueue(ctx, newEvent("2")))
+
+		require.NoError(t, q.Drain(context.Background()))
+
+		sent := ceClient.Sent()
+		require.Len(t, sent, 2)
+		assert.Equal(t, "1", sent[0].ID())
+		assert.Equal(t, "2", sent[1].ID())
+	})
+
+	t.Run("failed deliveries are retried", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+		ceClient.Send_AppendResult(cehttp.NewResult(500, "sink error"))
+		ceClient.Send_AppendResult(cehttp.NewResult(503, "sink error"))
+
+		q := New(ceClient, Config{Retries: 2, BackoffDelay: time.Millisecond}, logtesting.TestLogger(t))
+		q.Start()
+
+		require.NoError(t, q.Enqueue(context.Background(), newEvent("1")))
+		require.NoError(t, q.Drain(context.Background()))
+
+		assert.Len(t, ceClient.Sent(), 3)
+	})
+
+	t.Run("retries carry the same event ID", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+		ceClient.Send_AppendResult(cehttp.NewResult(500, "sink error"))
+		ceClient.Send_AppendResult(cehttp.NewResult(503, "sink error"))
+
+		q := New(ceClient, Config{Retries: 2, BackoffDelay: time.Millisecond}, logtesting.TestLogger(t))
+		q.Start()
+
+		require.NoError(t, q.Enqueue(context.Background(), newEvent("")))
+		require.NoError(t, q.Drain(context.Background()))
+
+		sent := ceClient.Sent()
+		require.Len(t, sent, 3)
+		assert.NotEmpty(t, sent[0].ID())
+		for _, e := range sent[1:] {
+			assert.Equal(t, sent[0].ID(), e.ID())
+		}
+	})
+
+	t.Run("undelivered events are sent to the dead-letter sink", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+		ceClient.Send_AppendResult(cehttp.NewResult(500, "sink error"))
+
+		q := New(ceClient, Config{DeadLetterSink: "http://dls.example.com"}, logtesting.TestLogger(t))
+		q.Start()
+
+		require.NoError(t, q.Enqueue(context.Background(), newEvent("1")))
+		require.NoError(t, q.Drain(context.Background()))
+
+		sent := ceClient.Sent()
+		require.Len(t, sent, 2)
+		assert.Equal(t, "1", sent[1].ID())
+	})
+
+	t.Run("undelivered events are dropped without dead-letter sink", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+		ceClient.Send_AppendResult(cehttp.NewResult(500, "sink error"))
+
+		q := New(ceClient, Config{}, logtesting.TestLogger(t))
+		q.Start()
+
+		require.NoError(t, q.Enqueue(context.Background(), newEvent("1")))
+		require.NoError(t, q.Drain(context.Background()))
+
+		assert.Len(t, ceClient.Sent(), 1)
+	})
+
+	t.Run("full queue rejects events", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		// workers are not started, so events accumulate in the queue
+		q := New(ceClient, Config{Size: 1}, logtesting.TestLogger(t))
+
+		require.NoError(t, q.Enqueue(context.Background(), newEvent("1")))
+		assert.Equal(t, ErrQueueFull, q.Enqueue(context.Background(), newEvent("2")))
+		assert.Equal(t, 1, q.Len())
+	})
+
+	t.Run("closed queue rejects events", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		q := New(ceClient, Config{}, logtesting.TestLogger(t))
+		q.Start()
+
+		require.NoError(t, q.Drain(context.Background()))
+		assert.Equal(t, ErrQueueClosed, q.Enqueue(context.Background(), newEvent("1")))
+	})
+
+	t.Run("drain times out", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClientWithDelay(time.Second)
+
+		q := New(ceClient, Config{}, logtesting.TestLogger(t))
+		q.Start()
+
+		require.NoError(t, q.Enqueue(context.Background(), newEvent("1")))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.Error(t, q.Drain(ctx))
+	})
+}
+
+func newEvent(id string) cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID(id)
+	e.SetType("test.type")
+	e.SetSource("test.source")
+	return e
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delivery
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	// queueDepthM is a gauge which records the number of events waiting in
+	// a delivery queue.
+	queueDepthM = stats.Int64(
+		"delivery_queue_depth",
+		"Number of events waiting to be delivered",
+		stats.UnitDimensionless,
+	)
+
+	// undeliveredCountM is a counter which records the number of events
+	// which could not be delivered to the sink after all retries.
+	undeliveredCountM = stats.Int64(
+		"delivery_undelivered_count",
+		"Number of events which could not be delivered after all retries",
+		stats.UnitDimensionless,
+	)
+)
+
+var (
+	// queueKey is the tag which identifies a delivery queue.
+	queueKey = tag.MustNewKey("queue")
+	// deadLetteredKey is the tag which indicates whether an undelivered
+	// event was sent to the dead-letter sink.
+	deadLetteredKey = tag.MustNewKey("dead_lettered")
+)
+
+func init() {
+	register()
+}
+
+func register() {
+	err := metrics.RegisterResourceView(
+		&view.View{
+			Description: queueDepthM.Description(),
+			Measure:     queueDepthM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{queueKey},
+		},
+		&view.View{
+			Description: undeliveredCountM.Description(),
+			Measure:     undeliveredCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{queueKey, deadLetteredKey},
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %s", err)
+	}
+}
+
+// reportQueueDepth captures the number of events waiting in the given queue.
+func reportQueueDepth(queue string, depth int) {
+	metrics.Record(context.Background(), queueDepthM.M(int64(depth)),
+		stats.WithTags(tag.Upsert(queueKey, queue)))
+}
+
+// reportUndelivered captures an event which could not be delivered by the
+// given queue, and whether it was sent to the dead-letter sink.
+func reportUndelivered(queue string, deadLettered bool) {
+	metrics.Record(context.Background(), undeliveredCountM.M(1),
+		stats.WithTags(
+			tag.Upsert(queueKey, queue),
+			tag.Upsert(deadLetteredKey, strconv.FormatBool(deadLettered)),
+		))
+}
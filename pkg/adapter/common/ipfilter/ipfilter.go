@@ -0,0 +1,206 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ipfilter restricts the IP addresses HTTP requests are accepted from.
+package ipfilter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"go.opencensus.io/tag"
+)
+
+const (
+	headerForwarded     = "Forwarded"
+	headerXForwardedFor = "X-Forwarded-For"
+)
+
+// Filter accepts or rejects HTTP requests based on the IP address of the
+// client that sent them.
+type Filter struct {
+	allowed []*net.IPNet
+	// proxies which are trusted to report the address of the original
+	// client in forwarding headers
+	trustedProxies []*net.IPNet
+
+	// identify the owner of the filter in metrics
+	ownerTags []tag.Mutator
+}
+
+// New returns a Filter which accepts requests from the given allowed IP
+// ranges. Forwarding headers are honored only for requests received from the
+// given trusted proxies. IP ranges are expressed in CIDR notation.
+func New(allowedCIDRs, trustedProxyCIDRs []string) (*Filter, error) {
+	allowed, err := parseCIDRs(allowedCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("parsing allowed IP ranges: %w", err)
+	}
+
+	trusted, err := parseCIDRs(trustedProxyCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("parsing trusted proxies IP ranges: %w", err)
+	}
+
+	return &Filter{
+		allowed:        allowed,
+		trustedProxies: trusted,
+	}, nil
+}
+
+// WithOwner returns a copy of the Filter which reports rejected requests in
+// metrics along with the namespace and name of the source that owns it.
+func (f *Filter) WithOwner(namespace, name string) *Filter {
+	owned := *f
+	owned.ownerTags = []tag.Mutator{
+		tag.Upsert(namespaceKey, namespace),
+		tag.Upsert(nameKey, name),
+	}
+
+	return &owned
+}
+
+// Handler returns a HTTP handler which rejects requests from clients that are
+// not allowed with the status code 403 (Forbidden), and passes other requests
+// to h.
+func (f *Filter) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.Allowed(r) {
+			reportRejectedRequest(f.ownerTags)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Allowed returns whether the client that sent the given request is allowed.
+func (f *Filter) Allowed(r *http.Request) bool {
+	ip := f.ClientIP(r)
+	if ip == nil {
+		return false
+	}
+
+	return contains(f.allowed, ip)
+}
+
+// FromTrustedProxy returns whether the given request was received from a
+// trusted proxy, which can be relied on to set forwarding headers.
+func (f *Filter) FromTrustedProxy(r *http.Request) bool {
+	peer := parseIP(r.RemoteAddr)
+	return peer != nil && contains(f.trustedProxies, peer)
+}
+
+// ClientIP returns the IP address of the client that sent the given request,
+// or nil if this address can not be determined.
+//
+// When the request was received from a trusted proxy, the chain of forwarding
+// proxies is walked from the closest to the farthest, and the first address
+// which doesn't belong to a trusted proxy is returned.
+func (f *Filter) ClientIP(r *http.Request) net.IP {
+	peer := parseIP(r.RemoteAddr)
+	if peer == nil || !contains(f.trustedProxies, peer) {
+		return peer
+	}
+
+	chain := forwardedFor(r.Header)
+
+	ip := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		if ip = parseIP(chain[i]); ip == nil {
+			return nil
+		}
+		if !contains(f.trustedProxies, ip) {
+			return ip
+		}
+	}
+
+	return ip
+}
+
+// forwardedFor returns the chain of client addresses contained in the
+// forwarding headers of a request, from the farthest to the closest.
+// The standard Forwarded header (RFC 7239) takes precedence over the
+// X-Forwarded-For header.
+func forwardedFor(h http.Header) []string {
+	var chain []string
+
+	if fwd := h.Values(headerForwarded); len(fwd) > 0 {
+		for _, elems := range fwd {
+			for _, elem := range strings.Split(elems, ",") {
+				for _, pair := range strings.Split(elem, ";") {
+					kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+					if len(kv) == 2 && strings.EqualFold(kv[0], "for") {
+						chain = append(chain, strings.Trim(kv[1], `"`))
+					}
+				}
+			}
+		}
+		return chain
+	}
+
+	for _, xff := range h.Values(headerXForwardedFor) {
+		for _, addr := range strings.Split(xff, ",") {
+			chain = append(chain, strings.TrimSpace(addr))
+		}
+	}
+
+	return chain
+}
+
+// parseIP parses an IP address which may be suffixed by a port number, such
+// as "192.0.2.1", "192.0.2.1:8080", "2001:db8::1" or "[2001:db8::1]:8080".
+func parseIP(addr string) net.IP {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		// IPv6 address enclosed in brackets without port
+		host = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+	}
+
+	return net.ParseIP(host)
+}
+
+// parseCIDRs parses a list of IP ranges in CIDR notation.
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+// contains returns whether ip belongs to any of the given IP ranges.
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
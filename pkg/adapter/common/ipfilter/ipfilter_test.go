@@ -0,0 +1,200 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipfilter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New([]string{"192.0.2.0/24", "2001:db8::/32"}, []string{"127.0.0.1/32"})
+	assert.NoError(t, err)
+
+	_, err = New([]string{"192.0.2.0"}, nil)
+	assert.EqualError(t, err, "parsing allowed IP ranges: invalid CIDR address: 192.0.2.0")
+
+	_, err = New([]string{"192.0.2.0/24"}, []string{"localhost"})
+	assert.EqualError(t, err, "parsing trusted proxies IP ranges: invalid CIDR address: localhost")
+}
+
+func TestClientIP(t *testing.T) {
+	tc := map[string]struct {
+		remoteAddr string
+		headers    map[string]string
+
+		expectIP string
+	}{
+		"direct client": {
+			remoteAddr: "192.0.2.10:51234",
+			expectIP:   "192.0.2.10",
+		},
+		"direct IPv6 client": {
+			remoteAddr: "[2001:db8::10]:51234",
+			expectIP:   "2001:db8::10",
+		},
+		"headers ignored from untrusted peer": {
+			remoteAddr: "192.0.2.10:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expectIP:   "192.0.2.10",
+		},
+		"X-Forwarded-For from trusted peer": {
+			remoteAddr: "127.0.0.1:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 10.0.0.5"},
+			expectIP:   "198.51.100.1",
+		},
+		"spoofed X-Forwarded-For entry": {
+			remoteAddr: "127.0.0.1:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 10.0.0.5"},
+			expectIP:   "203.0.113.7",
+		},
+		"Forwarded from trusted peer": {
+			remoteAddr: "127.0.0.1:51234",
+			headers: map[string]string{
+				"Forwarded":       `for=198.51.100.1;proto=https, for="[2001:db8::20]:4711";by=10.0.0.5`,
+				"X-Forwarded-For": "203.0.113.7",
+			},
+			expectIP: "2001:db8::20",
+		},
+		"only trusted proxies": {
+			remoteAddr: "127.0.0.1:51234",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.6, 10.0.0.5"},
+			expectIP:   "10.0.0.6",
+		},
+		"no forwarding header from trusted peer": {
+			remoteAddr: "127.0.0.1:51234",
+			expectIP:   "127.0.0.1",
+		},
+		"malformed forwarded address": {
+			remoteAddr: "127.0.0.1:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, unknown"},
+		},
+	}
+
+	f, err := New(nil, []string{"127.0.0.1/32", "10.0.0.0/8"})
+	require.NoError(t, err)
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = c.remoteAddr
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
+
+			ip := f.ClientIP(req)
+
+			if c.expectIP == "" {
+				assert.Nil(t, ip)
+				return
+			}
+			assert.Equal(t, c.expectIP, ip.String())
+		})
+	}
+}
+
+func TestFromTrustedProxy(t *testing.T) {
+	f, err := New(nil, []string{"10.0.0.0/8", "2001:db8::/32"})
+	require.NoError(t, err)
+
+	tc := map[string]bool{
+		"10.1.2.3:51234":       true,
+		"[2001:db8::1]:51234":  true,
+		"192.0.2.10:51234":     false,
+		"[2001:db9::1]:51234":  false,
+		"not-an-address:51234": false,
+	}
+
+	for remoteAddr, expect := range tc {
+		//nolint:scopelint
+		t.Run(remoteAddr, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remoteAddr
+
+			assert.Equal(t, expect, f.FromTrustedProxy(req))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	f, err := New([]string{"192.0.2.0/24"}, []string{"127.0.0.1/32"})
+	require.NoError(t, err)
+
+	h := f.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tc := map[string]struct {
+		remoteAddr string
+		xff        string
+
+		expectCode int
+	}{
+		"allowed client": {
+			remoteAddr: "192.0.2.10:51234",
+			expectCode: http.StatusOK,
+		},
+		"allowed client behind proxy": {
+			remoteAddr: "127.0.0.1:51234",
+			xff:        "192.0.2.10",
+			expectCode: http.StatusOK,
+		},
+		"rejected client": {
+			remoteAddr: "198.51.100.1:51234",
+			expectCode: http.StatusForbidden,
+		},
+		"rejected client behind proxy": {
+			remoteAddr: "127.0.0.1:51234",
+			xff:        "198.51.100.1",
+			expectCode: http.StatusForbidden,
+		},
+		"rejected client spoofing forwarding header": {
+			remoteAddr: "198.51.100.1:51234",
+			xff:        "192.0.2.10",
+			expectCode: http.StatusForbidden,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = c.remoteAddr
+			if c.xff != "" {
+				req.Header.Set("X-Forwarded-For", c.xff)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectCode, rec.Code)
+		})
+	}
+}
+
+func TestWithOwner(t *testing.T) {
+	f, err := New([]string{"192.0.2.0/24"}, nil)
+	require.NoError(t, err)
+
+	owned := f.WithOwner("ns", "name")
+	assert.Equal(t, f.allowed, owned.allowed)
+	assert.Len(t, owned.ownerTags, 2)
+	assert.Empty(t, f.ownerTags, "The original Filter should be left unchanged")
+}
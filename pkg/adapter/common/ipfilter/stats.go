@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipfilter
+
+import (
+	"context"
+	"log"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/metrics/metricskey"
+)
+
+// rejectedRequestCountM is a counter which records the number of requests
+// rejected because of the IP address of their sender.
+var rejectedRequestCountM = stats.Int64(
+	"ipfilter_rejected_request_count",
+	"Number of requests rejected because of the IP address of their sender",
+	stats.UnitDimensionless,
+)
+
+// namespaceKey and nameKey are the tags which identify the source that
+// rejected a request, as in the event metrics of Knative sources.
+var (
+	namespaceKey = tag.MustNewKey(metricskey.LabelNamespaceName)
+	nameKey      = tag.MustNewKey(metricskey.LabelName)
+)
+
+func init() {
+	register()
+}
+
+func register() {
+	err := metrics.RegisterResourceView(
+		&view.View{
+			Description: rejectedRequestCountM.Description(),
+			Measure:     rejectedRequestCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{namespaceKey, nameKey},
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %s", err)
+	}
+}
+
+// reportRejectedRequest captures a request rejected by the filter owned by
+// the entity identified by the given tags.
+func reportRejectedRequest(ownerTags []tag.Mutator) {
+	metrics.Record(context.Background(), rejectedRequestCountM.M(1), stats.WithTags(ownerTags...))
+}
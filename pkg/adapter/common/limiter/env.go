@@ -0,0 +1,43 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limiter
+
+import "github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+
+// EnvLimits contains the request limits propagated to single-tenant receive
+// adapters via the environment. It is meant to be embedded in the adapter's
+// own envconfig accessor.
+type EnvLimits struct {
+	MaxBodySize           int64    `envconfig:"REQUEST_MAX_BODY_SIZE"`
+	MaxConcurrentRequests int      `envconfig:"REQUEST_MAX_CONCURRENT"`
+	RequestsPerSecond     float64  `envconfig:"REQUEST_RATE_LIMIT"`
+	Burst                 int      `envconfig:"REQUEST_RATE_BURST"`
+	Key                   string   `envconfig:"REQUEST_RATE_LIMIT_KEY"`
+	TrustedProxyCIDRs     []string `envconfig:"REQUEST_TRUSTED_PROXY_CIDRS"`
+}
+
+// Config returns the Config corresponding to the environment.
+func (e *EnvLimits) Config() Config {
+	return Config{
+		MaxBodySize:           e.MaxBodySize,
+		MaxConcurrentRequests: e.MaxConcurrentRequests,
+		RequestsPerSecond:     e.RequestsPerSecond,
+		Burst:                 e.Burst,
+		Key:                   v1alpha1.RateLimitKey(e.Key),
+		TrustedProxyCIDRs:     e.TrustedProxyCIDRs,
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package limiter restricts the size, concurrency and rate of HTTP requests
+// accepted by receive adapters.
+package limiter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+const (
+	// delay suggested to clients rejected because of the concurrency limit
+	retryAfterSeconds = 1
+
+	// duration after which the rate limiter of an inactive client is
+	// discarded
+	clientIdleTimeout = 10 * time.Minute
+)
+
+// Config contains the configuration of a Limiter. Zero values disable the
+// corresponding limit.
+type Config struct {
+	// Maximum size of request bodies, in bytes.
+	MaxBodySize int64
+	// Maximum number of requests processed concurrently.
+	MaxConcurrentRequests int
+	// Sustained number of requests accepted per second.
+	RequestsPerSecond float64
+	// Maximum number of requests accepted in a single burst.
+	Burst int
+	// Entity the rate limit applies to.
+	Key v1alpha1.RateLimitKey
+	// Proxies trusted to report the address of the original client, in CIDR
+	// notation.
+	TrustedProxyCIDRs []string
+}
+
+// ConfigFromSpec returns the Config corresponding to the given API object,
+// which may be nil.
+func ConfigFromSpec(l *v1alpha1.RequestLimits) Config {
+	var cfg Config
+
+	if l == nil {
+		return cfg
+	}
+
+	if l.MaxBodySize != nil {
+		cfg.MaxBodySize = l.MaxBodySize.Value()
+	}
+	if l.MaxConcurrentRequests != nil {
+		cfg.MaxConcurrentRequests = int(*l.MaxConcurrentRequests)
+	}
+
+	if rl := l.RateLimit; rl != nil {
+		cfg.RequestsPerSecond = float64(rl.RequestsPerSecond)
+		if rl.Burst != nil {
+			cfg.Burst = int(*rl.Burst)
+		}
+		if rl.Key != nil {
+			cfg.Key = *rl.Key
+		}
+		cfg.TrustedProxyCIDRs = rl.TrustedProxyCIDRs
+	}
+
+	return cfg
+}
+
+// Enabled returns whether at least one limit is set.
+func (c Config) Enabled() bool {
+	return c.MaxBodySize > 0 || c.MaxConcurrentRequests > 0 || c.RequestsPerSecond > 0
+}
+
+// Limiter rejects HTTP requests which exceed the configured limits.
+type Limiter struct {
+	maxBodySize int64
+
+	// nil if the concurrency is not limited
+	inFlight chan struct{}
+
+	// rate limiter shared by all requests, nil if the rate is not limited
+	// or if it is limited per client
+	shared *rate.Limiter
+	// rate limiters per client, nil if the rate is not limited per client
+	clients *clientLimiters
+
+	// allows mocking the current time in tests
+	now func() time.Time
+}
+
+// New returns a Limiter for the given configuration.
+func New(cfg Config) (*Limiter, error) {
+	l := &Limiter{
+		maxBodySize: cfg.MaxBodySize,
+		now:         time.Now,
+	}
+
+	if cfg.MaxConcurrentRequests > 0 {
+		l.inFlight = make(chan struct{}, cfg.MaxConcurrentRequests)
+	}
+
+	if cfg.RequestsPerSecond <= 0 {
+		return l, nil
+	}
+
+	limit := rate.Limit(cfg.RequestsPerSecond)
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(cfg.RequestsPerSecond))
+	}
+
+	switch cfg.Key {
+	case "", v1alpha1.RateLimitKeySource:
+		l.shared = rate.NewLimiter(limit, burst)
+
+	case v1alpha1.RateLimitKeyClientIP:
+		ipr, err := ipfilter.New(nil, cfg.TrustedProxyCIDRs)
+		if err != nil {
+			return nil, err
+		}
+
+		l.clients = &clientLimiters{
+			limit:    limit,
+			burst:    burst,
+			clientIP: ipr.ClientIP,
+			entries:  make(map[string]*clientLimiter),
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported rate limit key %q", cfg.Key)
+	}
+
+	return l, nil
+}
+
+// Handler returns a HTTP handler which rejects requests exceeding the limits,
+// and passes other requests to h.
+//
+// Request bodies are buffered in memory in order to enforce the maximum body
+// size, so that h doesn't need to bound its own reads.
+func (l *Limiter) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay, ok := l.allow(r); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		if l.inFlight != nil {
+			select {
+			case l.inFlight <- struct{}{}:
+				defer func() { <-l.inFlight }()
+			default:
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		if l.maxBodySize > 0 {
+			if err := limitBody(r, l.maxBodySize); err != nil {
+				code := http.StatusBadRequest
+				if err == errBodyTooLarge {
+					code = http.StatusRequestEntityTooLarge
+				}
+				http.Error(w, err.Error(), code)
+				return
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// allow returns whether the given request is within the rate limit. If it
+// isn't, the returned duration indicates how long the client should wait
+// before retrying.
+func (l *Limiter) allow(r *http.Request) (time.Duration, bool) {
+	var lim *rate.Limiter
+
+	switch {
+	case l.shared != nil:
+		lim = l.shared
+	case l.clients != nil:
+		lim = l.clients.get(r, l.now())
+	default:
+		return 0, true
+	}
+
+	now := l.now()
+
+	res := lim.ReserveN(now, 1)
+	if !res.OK() {
+		return time.Second, false
+	}
+
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return delay, false
+	}
+
+	return 0, true
+}
+
+// errBodyTooLarge indicates that a request body exceeds the maximum size.
+var errBodyTooLarge = errors.New(http.StatusText(http.StatusRequestEntityTooLarge))
+
+// limitBody buffers the body of the given request, provided that its size
+// doesn't exceed max.
+func limitBody(r *http.Request, max int64) error {
+	if r.ContentLength > max {
+		return errBodyTooLarge
+	}
+
+	if r.Body == nil {
+		return nil
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, max+1))
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+	if int64(len(body)) > max {
+		return errBodyTooLarge
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return nil
+}
+
+// clientLimiters holds a rate limiter per client IP address.
+type clientLimiters struct {
+	limit rate.Limit
+	burst int
+
+	clientIP func(*http.Request) net.IP
+
+	mu      sync.Mutex
+	entries map[string]*clientLimiter
+	// time of the last removal of idle entries
+	purgedAt time.Time
+}
+
+// clientLimiter is the rate limiter of a single client.
+type clientLimiter struct {
+	lim      *rate.Limiter
+	lastSeen time.Time
+}
+
+// get returns the rate limiter of the client which sent the given request.
+// Requests from clients which IP address can not be determined share the same
+// rate limiter.
+func (c *clientLimiters) get(r *http.Request, now time.Time) *rate.Limiter {
+	var key string
+	if ip := c.clientIP(r); ip != nil {
+		key = ip.String()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.purgedAt) >= clientIdleTimeout {
+		for k, e := range c.entries {
+			if now.Sub(e.lastSeen) >= clientIdleTimeout {
+				delete(c.entries, k)
+			}
+		}
+		c.purgedAt = now
+	}
+
+	e, ok := c.entries[key]
+	if !ok {
+		e = &clientLimiter{lim: rate.NewLimiter(c.limit, c.burst)}
+		c.entries[key] = e
+	}
+	e.lastSeen = now
+
+	return e.lim
+}
@@ -0,0 +1,197 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limiter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/pkg/ptr"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	l, err := New(Config{MaxBodySize: 5})
+	require.NoError(t, err)
+
+	var received string
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		received = string(b)
+	}))
+
+	tc := map[string]struct {
+		body string
+		// simulates a request with chunked transfer encoding
+		unknownLength bool
+
+		expectCode int
+	}{
+		"within limit": {
+			body:       "12345",
+			expectCode: http.StatusOK,
+		},
+		"exceeds limit": {
+			body:       "123456",
+			expectCode: http.StatusRequestEntityTooLarge,
+		},
+		"exceeds limit with unknown length": {
+			body:          "123456",
+			unknownLength: true,
+			expectCode:    http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			received = ""
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.body))
+			if c.unknownLength {
+				req.ContentLength = -1
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			assert.Equal(t, c.expectCode, rr.Code)
+			if c.expectCode == http.StatusOK {
+				assert.Equal(t, c.body, received, "Body was not passed to the handler")
+			}
+		})
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	l, err := New(Config{MaxConcurrentRequests: 1})
+	require.NoError(t, err)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	h := l.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		entered <- struct{}{}
+		<-release
+	}))
+
+	done := make(chan int)
+	go func() {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+		done <- rr.Code
+	}()
+
+	<-entered
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	tc := map[string]struct {
+		key v1alpha1.RateLimitKey
+		// remote address of each consecutive request
+		remoteAddrs []string
+
+		expectCodes []int
+	}{
+		"per source": {
+			key:         v1alpha1.RateLimitKeySource,
+			remoteAddrs: []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.2:1234"},
+			expectCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		"per client IP": {
+			key:         v1alpha1.RateLimitKeyClientIP,
+			remoteAddrs: []string{"192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.1:1234", "192.0.2.2:1234"},
+			expectCodes: []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			l, err := New(Config{RequestsPerSecond: 0.5, Burst: 2, Key: c.key})
+			require.NoError(t, err)
+			l.now = func() time.Time { return now }
+
+			h := l.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+			for i, addr := range c.remoteAddrs {
+				req := httptest.NewRequest(http.MethodPost, "/", nil)
+				req.RemoteAddr = addr
+
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, req)
+
+				assert.Equal(t, c.expectCodes[i], rr.Code, "Unexpected status code for request %d", i)
+				if rr.Code == http.StatusTooManyRequests {
+					assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(Config{RequestsPerSecond: 1, Key: "User"})
+	assert.EqualError(t, err, `unsupported rate limit key "User"`)
+
+	_, err = New(Config{RequestsPerSecond: 1, Key: v1alpha1.RateLimitKeyClientIP, TrustedProxyCIDRs: []string{"nope"}})
+	assert.Error(t, err)
+}
+
+func TestConfigFromSpec(t *testing.T) {
+	assert.False(t, ConfigFromSpec(nil).Enabled())
+
+	maxBodySize := resource.MustParse("1Mi")
+	key := v1alpha1.RateLimitKeyClientIP
+
+	cfg := ConfigFromSpec(&v1alpha1.RequestLimits{
+		MaxBodySize:           &maxBodySize,
+		MaxConcurrentRequests: ptr.Int32(10),
+		RateLimit: &v1alpha1.RateLimit{
+			RequestsPerSecond: 5,
+			Burst:             ptr.Int32(20),
+			Key:               &key,
+			TrustedProxyCIDRs: []string{"127.0.0.1/32"},
+		},
+	})
+
+	assert.Equal(t, Config{
+		MaxBodySize:           1 << 20,
+		MaxConcurrentRequests: 10,
+		RequestsPerSecond:     5,
+		Burst:                 20,
+		Key:                   v1alpha1.RateLimitKeyClientIP,
+		TrustedProxyCIDRs:     []string{"127.0.0.1/32"},
+	}, cfg)
+}
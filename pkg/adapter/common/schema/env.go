@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+// EnvSchema contains the schema validation settings propagated to
+// single-tenant receive adapters via the environment. It is meant to be
+// embedded in the adapter's own envconfig accessor.
+type EnvSchema struct {
+	// Enables the validation against the adapter's built-in schema.
+	SchemaValidation bool `envconfig:"SCHEMA_VALIDATION"`
+	// User-provided schema, which takes precedence over the built-in one.
+	SchemaDocument  string `envconfig:"SCHEMA_DOCUMENT"`
+	SchemaURI       string `envconfig:"SCHEMA_URI"`
+	SchemaErrorSink string `envconfig:"SCHEMA_ERROR_SINK"`
+}
+
+// Config returns the Config corresponding to the environment, using the given
+// built-in schema when no schema was provided by the user. The returned
+// boolean is false when the validation is disabled.
+func (e *EnvSchema) Config(builtinSchema, builtinURI string) (Config, bool) {
+	cfg := Config{
+		Schema:    e.SchemaDocument,
+		URI:       e.SchemaURI,
+		ErrorSink: e.SchemaErrorSink,
+	}
+
+	switch {
+	case cfg.Schema != "":
+		return cfg, true
+	case e.SchemaValidation && builtinSchema != "":
+		cfg.Schema = builtinSchema
+		cfg.URI = builtinURI
+		return cfg, true
+	}
+
+	return cfg, false
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema validates the data of events against a JSON Schema before
+// receive adapters send them to their sink.
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ExtensionValidationError is the name of the CloudEvents extension which
+// describes why the data of an event sent to the error sink is invalid.
+const ExtensionValidationError = "validationerror"
+
+// Config contains the configuration of a Validator.
+type Config struct {
+	// JSON Schema document.
+	Schema string
+	// URI of the schema, set as the dataschema attribute of valid events.
+	URI string
+	// URI of the sink events with invalid data are sent to. When empty,
+	// invalid events are rejected.
+	ErrorSink string
+}
+
+// Validator validates the data of events against a JSON Schema.
+type Validator struct {
+	schema *gojsonschema.Schema
+	uri    string
+
+	errorSink string
+	ceClient  cloudevents.Client
+}
+
+// New returns a Validator for the given configuration. The CloudEvents client
+// is used to send invalid events to the error sink, if any.
+func New(cfg Config, ceClient cloudevents.Client) (*Validator, error) {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(cfg.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("loading JSON Schema: %w", err)
+	}
+
+	return &Validator{
+		schema:    s,
+		uri:       cfg.URI,
+		errorSink: cfg.ErrorSink,
+		ceClient:  ceClient,
+	}, nil
+}
+
+// ValidationError indicates that a document doesn't conform to the schema.
+type ValidationError struct {
+	// Description of each violation of the schema.
+	Violations []string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return "invalid event data: " + strings.Join(e.Violations, "; ")
+}
+
+// Validate validates the given JSON document against the schema. Documents
+// which don't conform to the schema yield a *ValidationError.
+func (v *Validator) Validate(doc []byte) error {
+	res, err := v.schema.Validate(gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		// the document could not be parsed
+		return &ValidationError{Violations: []string{err.Error()}}
+	}
+
+	if res.Valid() {
+		return nil
+	}
+
+	violations := make([]string, len(res.Errors()))
+	for i, re := range res.Errors() {
+		violations[i] = re.String()
+	}
+
+	return &ValidationError{Violations: violations}
+}
+
+// Check validates the data of the given event. See CheckDocument.
+func (v *Validator) Check(ctx context.Context, event *cloudevents.Event) (bool, error) {
+	return v.CheckDocument(ctx, event.Data(), event)
+}
+
+// CheckDocument validates the given document, from which the given event was
+// created, and returns whether the event should be sent to the sink.
+//
+// Valid events have their dataschema attribute set. Invalid events are sent
+// to the error sink when one is configured, otherwise the returned error is a
+// *ValidationError which callers should report to the client.
+func (v *Validator) CheckDocument(ctx context.Context, doc []byte, event *cloudevents.Event) (bool, error) {
+	err := v.Validate(doc)
+	if err == nil {
+		if v.uri != "" {
+			event.SetDataSchema(v.uri)
+		}
+		return true, nil
+	}
+
+	reportInvalidEvent(ctx)
+
+	if v.errorSink == "" {
+		return false, err
+	}
+
+	invalid := event.Clone()
+	invalid.SetExtension(ExtensionValidationError, err.Error())
+
+	if res := v.ceClient.Send(cloudevents.ContextWithTarget(ctx, v.errorSink), invalid); !cloudevents.IsACK(res) {
+		return false, fmt.Errorf("sending invalid event to the error sink: %w", res)
+	}
+
+	return false, nil
+}
+
+// IsValidationError returns whether the given error is a *ValidationError.
+func IsValidationError(err error) bool {
+	var verr *ValidationError
+	return errors.As(err, &verr)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+)
+
+const (
+	tSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["id"],
+  "properties": {
+    "id": {"type": "integer"}
+  }
+}`
+
+	tSchemaURI = "https://example.com/schema.json"
+	tErrorSink = "http://error-sink.example.com"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Schema: tSchema}, nil)
+	assert.NoError(t, err)
+
+	_, err = New(Config{Schema: "{ not a JSON }"}, nil)
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	v, err := New(Config{Schema: tSchema}, nil)
+	require.NoError(t, err)
+
+	tc := map[string]struct {
+		doc         string
+		expectValid bool
+	}{
+		"valid": {
+			doc:         `{"id": 42}`,
+			expectValid: true,
+		},
+		"missing required property": {
+			doc: `{"name": "foo"}`,
+		},
+		"wrong property type": {
+			doc: `{"id": "42"}`,
+		},
+		"not a JSON": {
+			doc: `<id>42</id>`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			err := v.Validate([]byte(c.doc))
+			if c.expectValid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, IsValidationError(err), "Expected a ValidationError, got %v", err)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tc := map[string]struct {
+		data      string
+		errorSink string
+		sinkFails bool
+
+		expectValid           bool
+		expectValidationError bool
+		expectErrorSinkEvent  bool
+	}{
+		"valid event": {
+			data:        `{"id": 42}`,
+			errorSink:   tErrorSink,
+			expectValid: true,
+		},
+		"invalid event is rejected": {
+			data:                  `{"id": "42"}`,
+			expectValidationError: true,
+		},
+		"invalid event is sent to the error sink": {
+			data:                 `{"id": "42"}`,
+			errorSink:            tErrorSink,
+			expectErrorSinkEvent: true,
+		},
+		"error sink fails": {
+			data:                 `{"id": "42"}`,
+			errorSink:            tErrorSink,
+			sinkFails:            true,
+			expectErrorSinkEvent: true,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+			if c.sinkFails {
+				ceClient.Send_AppendResult(cehttp.NewResult(500, ""))
+			}
+
+			v, err := New(Config{Schema: tSchema, URI: tSchemaURI, ErrorSink: c.errorSink}, ceClient)
+			require.NoError(t, err)
+
+			event := cloudevents.NewEvent(cloudevents.VersionV1)
+			require.NoError(t, event.SetData(cloudevents.ApplicationJSON, []byte(c.data)))
+
+			valid, err := v.Check(context.Background(), &event)
+
+			assert.Equal(t, c.expectValid, valid)
+
+			switch {
+			case c.expectValidationError:
+				assert.True(t, IsValidationError(err), "Expected a ValidationError, got %v", err)
+			case c.sinkFails:
+				assert.Error(t, err)
+				assert.False(t, IsValidationError(err), "Unexpected ValidationError")
+			default:
+				assert.NoError(t, err)
+			}
+
+			if c.expectValid {
+				assert.Equal(t, tSchemaURI, event.DataSchema())
+			} else {
+				assert.Empty(t, event.DataSchema())
+			}
+
+			sent := ceClient.Sent()
+			if !c.expectErrorSinkEvent {
+				assert.Empty(t, sent, "Unexpected event sent to the error sink")
+				return
+			}
+			require.Len(t, sent, 1)
+			assert.Contains(t, sent[0].Extensions(), ExtensionValidationError)
+		})
+	}
+}
+
+func TestEnvSchemaConfig(t *testing.T) {
+	const builtin = `{"type": "object"}`
+	const builtinURI = "https://example.com/builtin.json"
+
+	tc := map[string]struct {
+		env EnvSchema
+
+		expectEnabled bool
+		expectConfig  Config
+	}{
+		"disabled": {
+			env: EnvSchema{},
+		},
+		"built-in schema": {
+			env:           EnvSchema{SchemaValidation: true, SchemaErrorSink: tErrorSink},
+			expectEnabled: true,
+			expectConfig:  Config{Schema: builtin, URI: builtinURI, ErrorSink: tErrorSink},
+		},
+		"user-provided schema": {
+			env:           EnvSchema{SchemaDocument: tSchema, SchemaURI: tSchemaURI},
+			expectEnabled: true,
+			expectConfig:  Config{Schema: tSchema, URI: tSchemaURI},
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			cfg, enabled := c.env.Config(builtin, builtinURI)
+			assert.Equal(t, c.expectEnabled, enabled)
+			if enabled {
+				assert.Equal(t, c.expectConfig, cfg)
+			}
+		})
+	}
+}
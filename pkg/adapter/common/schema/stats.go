@@ -0,0 +1,57 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"context"
+	"log"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+
+	"knative.dev/pkg/metrics"
+)
+
+// invalidEventCountM is a counter which records the number of events which
+// data failed schema validation.
+var invalidEventCountM = stats.Int64(
+	"schema_invalid_event_count",
+	"Number of events which data failed schema validation",
+	stats.UnitDimensionless,
+)
+
+func init() {
+	register()
+}
+
+func register() {
+	err := metrics.RegisterResourceView(
+		&view.View{
+			Description: invalidEventCountM.Description(),
+			Measure:     invalidEventCountM,
+			Aggregation: view.Count(),
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %s", err)
+	}
+}
+
+// reportInvalidEvent captures an event with invalid data.
+func reportInvalidEvent(ctx context.Context) {
+	metrics.Record(ctx, invalidEventCountM.M(1))
+}
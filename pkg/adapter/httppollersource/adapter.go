@@ -27,6 +27,8 @@ import (
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
 // NewAdapter implementation
@@ -64,6 +66,13 @@ func NewAdapter(ctx context.Context, aEnv adapter.EnvConfigAccessor, ceClient cl
 		httpRequest.SetBasicAuth(env.BasicAuthUsername, env.BasicAuthPassword)
 	}
 
+	var validator *schema.Validator
+	if cfg, ok := env.EnvSchema.Config("", ""); ok {
+		if validator, err = schema.New(cfg, ceClient); err != nil {
+			logger.Panicw("Invalid schema validation settings", zap.Error(err))
+		}
+	}
+
 	return &httpPoller{
 		eventType:   env.EventType,
 		eventSource: env.EventSource,
@@ -72,7 +81,8 @@ func NewAdapter(ctx context.Context, aEnv adapter.EnvConfigAccessor, ceClient cl
 		httpClient:  httpClient,
 		httpRequest: httpRequest,
 
-		ceClient: ceClient,
-		logger:   logging.FromContext(ctx),
+		ceClient:  ceClient,
+		validator: validator,
+		logger:    logging.FromContext(ctx),
 	}
 }
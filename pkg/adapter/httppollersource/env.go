@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"knative.dev/eventing/pkg/adapter/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
 // EnvAccessor for configuration parameters
@@ -29,6 +31,7 @@ func EnvAccessor() adapter.EnvConfigAccessor {
 
 type envAccessor struct {
 	adapter.EnvConfig
+	schema.EnvSchema
 
 	EventType         string            `envconfig:"HTTPPOLLER_EVENT_TYPE" required:"true"`
 	EventSource       string            `envconfig:"HTTPPOLLER_EVENT_SOURCE" required:"true"`
@@ -28,6 +28,8 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
 	"knative.dev/eventing/pkg/adapter/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
 type httpPoller struct {
@@ -36,6 +38,8 @@ type httpPoller struct {
 	interval    time.Duration
 
 	ceClient cloudevents.Client
+	// optional, validates the data of events against a JSON Schema
+	validator *schema.Validator
 
 	httpClient  *http.Client
 	httpRequest *http.Request
@@ -102,6 +106,18 @@ func (h *httpPoller) dispatch() {
 		return
 	}
 
+	if h.validator != nil {
+		valid, err := h.validator.Check(context.Background(), &event)
+		if err != nil {
+			h.logger.Errorw("Discarding event with invalid data", zap.Error(err))
+			return
+		}
+		if !valid {
+			h.logger.Debug("Event with invalid data was sent to the error sink")
+			return
+		}
+	}
+
 	if result := h.ceClient.Send(context.Background(), event); !cloudevents.IsACK(result) {
 		h.logger.Errorw("Could not send Cloud Event", zap.Error(result))
 	}
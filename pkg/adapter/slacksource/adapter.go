@@ -18,12 +18,22 @@ package slacksource
 
 import (
 	"context"
+	"fmt"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/schemas"
 )
 
 const defaultListenPort = 8080
@@ -33,12 +43,121 @@ func NewAdapter(ctx context.Context, aEnv adapter.EnvConfigAccessor, ceClient cl
 	env := aEnv.(*envAccessor)
 	logger := logging.FromContext(ctx)
 
+	env.AdditionalSigningSecrets = credentials.FromEnv(envAdditionalSigningSecrets)
+	env.Apps = appsFromEnv()
+
+	h, err := newHandler(env, ceClient, logger.Named("handler"))
+	if err != nil {
+		logger.Panicw("Invalid Slack settings", zap.Error(err))
+	}
+	h.port = defaultListenPort
+
 	return &slackAdapter{
-		handler: NewSlackEventAPIHandler(ceClient, defaultListenPort, env.SigningSecret, env.AppID, standardTime{}, logger.Named("handler")),
+		handler: h,
 		logger:  logger,
 	}
 }
 
+// newHandler returns a slackEventAPIHandler configured from the given
+// environment.
+func newHandler(env *envAccessor, ceClient cloudevents.Client, logger *zap.SugaredLogger) (*slackEventAPIHandler, error) {
+	signingSecrets := credentials.New(env.SigningSecret, env.AdditionalSigningSecrets...).
+		WithOwner(env.Namespace, env.Name, env.AppID)
+
+	var opts []HandlerOption
+
+	if len(env.Apps) > 0 {
+		// copies, the environment of tenants is compared across reconciliations
+		apps := make([]*SlackApp, len(env.Apps))
+		for i, app := range env.Apps {
+			ownedApp := *app
+			ownedApp.SigningSecrets = app.SigningSecrets.WithOwner(env.Namespace, env.Name, app.ID)
+			apps[i] = &ownedApp
+		}
+		opts = append(opts, WithApps(apps...))
+	}
+
+	if env.AppToken != "" {
+		opts = append(opts, WithSocketMode(env.AppToken))
+	}
+
+	if len(env.AllowedCIDRs) > 0 {
+		f, err := ipfilter.New(env.AllowedCIDRs, env.TrustedProxyCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP allowlist: %w", err)
+		}
+		opts = append(opts, WithIPFilter(f.WithOwner(env.Namespace, env.Name)))
+	}
+
+	if cfg := env.EnvLimits.Config(); cfg.Enabled() {
+		l, err := limiter.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request limits: %w", err)
+		}
+		opts = append(opts, WithLimiter(l))
+	}
+
+	switch v1alpha1.SlackEventTypeMode(env.EventTypeMode) {
+	case v1alpha1.SlackEventTypeGeneric:
+		// default behaviour of the handler
+	case v1alpha1.SlackEventTypeSpecific:
+		opts = append(opts, WithSpecificEventTypes())
+	default:
+		return nil, fmt.Errorf("unsupported event type mode %q", env.EventTypeMode)
+	}
+
+	switch v1alpha1.SlackResponseMode(env.ResponseMode) {
+	case v1alpha1.SlackResponseAck:
+		// default behaviour of the handler
+	case v1alpha1.SlackResponseReply:
+		opts = append(opts, WithInteractionReplies())
+	default:
+		return nil, fmt.Errorf("unsupported response mode %q", env.ResponseMode)
+	}
+
+	filter := &EventFilter{
+		EventTypes:       env.FilterEventTypes,
+		ExcludedSubtypes: env.FilterExcludedSubtypes,
+		Channels:         env.FilterChannels,
+		Users:            env.FilterUsers,
+		ExcludeBots:      env.FilterExcludeBots,
+		ExcludeSelf:      env.FilterExcludeSelf,
+	}
+	if filter.Enabled() {
+		opts = append(opts, WithEventFilter(filter))
+	}
+
+	if env.BotToken != "" {
+		opts = append(opts, WithEnrichment(env.BotToken, env.EnrichmentAPIURL, env.EnrichmentCacheTTL))
+	}
+
+	if env.Deduplication {
+		opts = append(opts, WithDeduplication(dedup.New(env.DeduplicationTTL)))
+	}
+
+	if env.AsyncDelivery {
+		opts = append(opts, WithAsyncDelivery(delivery.Config{
+			Size:           env.AsyncQueueSize,
+			Workers:        env.AsyncWorkers,
+			Retries:        env.AsyncRetries,
+			BackoffDelay:   env.AsyncBackoffDelay,
+			DeadLetterSink: env.AsyncDeadLetterSink,
+			Name:           env.AsyncQueueName,
+		}))
+	}
+
+	if cfg, ok := env.EnvSchema.Config(schemas.SlackEvents, schemas.SlackEventsURI); ok {
+		v, err := schema.New(cfg, ceClient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema validation settings: %w", err)
+		}
+		opts = append(opts, WithValidator(v))
+	}
+
+	return NewSlackEventAPIHandler(ceClient, 0, signingSecrets, env.AppID, standardTime{},
+		logger, opts...).(*slackEventAPIHandler), nil
+}
+
 var _ adapter.Adapter = (*slackAdapter)(nil)
 
 type slackAdapter struct {
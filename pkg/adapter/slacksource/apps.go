@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+// Environment variables which describe the Slack apps accepted by the
+// adapter. Each variable name consists of envAppsPrefix, the index of the app
+// and one of the suffixes below, e.g. SLACK_APPS_0_ID.
+const (
+	envAppsPrefix                 = "SLACK_APPS_"
+	envAppSuffixID                = "_ID"
+	envAppSuffixSigningSecret     = "_SIGNING_SECRET"
+	envAppSuffixAddSigningSecrets = "_ADDITIONAL_SIGNING_SECRET"
+	envAppSuffixEventTypePrefix   = "_EVENT_TYPE_PREFIX"
+)
+
+// SlackApp is a Slack app which events are accepted by the handler.
+type SlackApp struct {
+	ID             string
+	SigningSecrets *credentials.Set
+	// replaces the root of the types of events originating from the app,
+	// if not empty
+	EventTypePrefix string
+}
+
+// appsFromEnv returns the Slack apps described by the environment, in the
+// format documented for envAppsPrefix. The enumeration stops at the first
+// missing index.
+func appsFromEnv() []*SlackApp {
+	var apps []*SlackApp
+
+	for i := 0; ; i++ {
+		prefix := envAppsPrefix + strconv.Itoa(i)
+
+		id, ok := os.LookupEnv(prefix + envAppSuffixID)
+		if !ok {
+			return apps
+		}
+
+		apps = append(apps, &SlackApp{
+			ID: id,
+			SigningSecrets: credentials.New(os.Getenv(prefix+envAppSuffixSigningSecret),
+				credentials.FromEnv(prefix+envAppSuffixAddSigningSecrets)...),
+			EventTypePrefix: os.Getenv(prefix + envAppSuffixEventTypePrefix),
+		})
+	}
+}
+
+// acceptsApp returns whether the handler accepts events originating from the
+// Slack app with the given ID.
+func (h *slackEventAPIHandler) acceptsApp(appID string) bool {
+	if len(h.apps) > 0 {
+		_, ok := h.apps[appID]
+		return ok
+	}
+	return h.appID == "" || appID == h.appID
+}
+
+// setAppEventType applies the event type prefix of the Slack app with the
+// given ID to the given event.
+func (h *slackEventAPIHandler) setAppEventType(event *cloudevents.Event, appID string) {
+	if app, ok := h.apps[appID]; ok && app.EventTypePrefix != "" {
+		event.SetType(v1alpha1.PrefixSlackEventType(app.EventTypePrefix, event.Type()))
+	}
+}
+
+// signingSecretsFor returns the signing secrets of the Slack apps the given
+// request may originate from. In multi-app mode, this is the app which ID is
+// contained in the request, or all apps for requests which don't contain an
+// app ID, such as URL verification challenges. A nil slice means that
+// requests aren't authenticated.
+func (h *slackEventAPIHandler) signingSecretsFor(r *http.Request, body []byte) []*credentials.Set {
+	if len(h.apps) == 0 {
+		if h.signingSecrets.Empty() {
+			return nil
+		}
+		return []*credentials.Set{h.signingSecrets}
+	}
+
+	// the app ID isn't trusted until the signature is verified, it only
+	// selects the secrets the signature is verified with
+	if appID := claimedAppID(r, body); appID != "" {
+		app, ok := h.apps[appID]
+		if !ok {
+			// requests from unknown apps can't be authenticated
+			return []*credentials.Set{}
+		}
+		return []*credentials.Set{app.SigningSecrets}
+	}
+
+	secrets := make([]*credentials.Set, 0, len(h.apps))
+	for _, app := range h.apps {
+		secrets = append(secrets, app.SigningSecrets)
+	}
+	return secrets
+}
+
+// claimedAppID returns the ID of the Slack app contained in the given request,
+// if any.
+func claimedAppID(r *http.Request, body []byte) string {
+	payload := body
+
+	if isForm(r) {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return ""
+		}
+		if form.Get(formFieldPayload) == "" {
+			// slash command
+			return form.Get(formFieldAPIAppID)
+		}
+		payload = []byte(form.Get(formFieldPayload))
+	}
+
+	var p struct {
+		APIAppID string `json:"api_app_id"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return ""
+	}
+	return p.APIAppID
+}
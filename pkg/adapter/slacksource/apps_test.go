@@ -0,0 +1,169 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+)
+
+const (
+	tHelpdeskAppID  = "AHELPDESK"
+	tHelpdeskSecret = "0000000000000000000000000000000a"
+	tDeployAppID    = "ADEPLOY"
+	tDeploySecret   = "0000000000000000000000000000000b"
+)
+
+func TestSlackMultipleApps(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	now := time.Unix(1593192795, 0)
+
+	callback := func(appID string) string {
+		return `{"team_id":"TXXXXXXXX","api_app_id":"` + appID + `","event":{"type":"app_mention"},` +
+			`"type":"event_callback","event_id":"Ev08MFMKH6","event_time":1593192794}`
+	}
+
+	tc := map[string]struct {
+		body   string
+		form   bool
+		secret string
+
+		expectedCode     int
+		expectedContains string
+		expectedType     string
+	}{
+		"event from app with prefix": {
+			body:         callback(tHelpdeskAppID),
+			secret:       tHelpdeskSecret,
+			expectedCode: http.StatusOK,
+			expectedType: "com.example.helpdesk.events",
+		},
+		"event from app without prefix": {
+			body:         callback(tDeployAppID),
+			secret:       tDeploySecret,
+			expectedCode: http.StatusOK,
+			expectedType: "com.slack.events",
+		},
+		"event signed with the secret of another app": {
+			body:             callback(tDeployAppID),
+			secret:           tHelpdeskSecret,
+			expectedCode:     http.StatusUnauthorized,
+			expectedContains: "received wrong signature signing hash",
+		},
+		"event from unknown app": {
+			body:             callback("AUNKNOWN"),
+			secret:           tHelpdeskSecret,
+			expectedCode:     http.StatusUnauthorized,
+			expectedContains: "received wrong signature signing hash",
+		},
+		"slash command from app with prefix": {
+			body: url.Values{
+				"command":    {"/ticket"},
+				"team_id":    {"TXXXXXXXX"},
+				"api_app_id": {tHelpdeskAppID},
+			}.Encode(),
+			form:         true,
+			secret:       tHelpdeskSecret,
+			expectedCode: http.StatusOK,
+			expectedType: "com.example.helpdesk.commands",
+		},
+		"interaction from app with prefix": {
+			body: url.Values{
+				formFieldPayload: {`{"type":"shortcut","api_app_id":"` + tHelpdeskAppID + `","team":{"id":"TXXXXXXXX"}}`},
+			}.Encode(),
+			form:         true,
+			secret:       tHelpdeskSecret,
+			expectedCode: http.StatusOK,
+			expectedType: "com.example.helpdesk.interactions.shortcut",
+		},
+		"URL verification signed by any app": {
+			body:             `{"token":"XXYYZZ","challenge":"3eZbrw1aBm2r","type":"url_verification"}`,
+			secret:           tDeploySecret,
+			expectedCode:     http.StatusOK,
+			expectedContains: `{"challenge":"3eZbrw1aBm2r"}`,
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", &mockedTime{now}, logger,
+				WithApps(
+					&SlackApp{
+						ID:              tHelpdeskAppID,
+						SigningSecrets:  credentials.New(tHelpdeskSecret),
+						EventTypePrefix: "com.example.helpdesk",
+					},
+					&SlackApp{
+						ID:             tDeployAppID,
+						SigningSecrets: credentials.New(tDeploySecret),
+					},
+				),
+			).(*slackEventAPIHandler)
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(c.body))
+			if c.form {
+				req.Header.Set("Content-Type", contentTypeForm)
+			}
+			signRequest(req, c.secret, now, c.body)
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, c.expectedCode, rr.Code, "unexpected response code")
+			assert.Contains(t, rr.Body.String(), c.expectedContains, "could not find expected response")
+
+			sent := ceClient.Sent()
+			if c.expectedType == "" {
+				assert.Empty(t, sent, "unexpected event was sent")
+				return
+			}
+
+			require.Len(t, sent, 1)
+			assert.Equal(t, c.expectedType, sent[0].Type())
+		})
+	}
+}
+
+// signRequest signs the given request the way Slack does.
+func signRequest(req *http.Request, secret string, ts time.Time, body string) {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+
+	hm := hmac.New(sha256.New, []byte(secret))
+	hm.Write([]byte("v0:" + timestamp + ":" + body)) //nolint:errcheck // hash.Hash never returns an error
+
+	req.Header.Set(signatureHeader, "v0="+hex.EncodeToString(hm.Sum(nil)))
+	req.Header.Set(signatureTimestampHeader, timestamp)
+}
@@ -0,0 +1,264 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// CloudEvents extensions set on enriched events
+	ceExtUserName     = "comslackusername"
+	ceExtUserRealName = "comslackuserrealname"
+	ceExtUserEmail    = "comslackuseremail"
+	ceExtChannelName  = "comslackchannelname"
+
+	// default duration for which resolved users and channels are cached
+	defaultEnrichmentCacheTTL = time.Hour
+
+	// maximum duration of a single Web API call. Lookups happen before
+	// Slack is acknowledged, which must happen within 3 seconds.
+	enrichmentTimeout = time.Second
+)
+
+// slackUser contains the attributes of a Slack user used to enrich events.
+// See: https://api.slack.com/types/user
+type slackUser struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Profile struct {
+		RealName string `json:"real_name"`
+		Email    string `json:"email"`
+	} `json:"profile"`
+}
+
+// slackChannel contains the attributes of a Slack conversation used to
+// enrich events.
+// See: https://api.slack.com/types/conversation
+type slackChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// webAPIResponse is the envelope of all responses of the Slack Web API.
+// See: https://api.slack.com/web#responses
+type webAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// enricher resolves the users and channels referenced by Slack events using
+// the Slack Web API, and caches the results.
+type enricher struct {
+	botToken string
+	apiURL   string
+
+	httpClient *http.Client
+
+	users    *ttlCache
+	channels *ttlCache
+
+	logger *zap.SugaredLogger
+}
+
+// newEnricher returns an enricher which authenticates using the given bot
+// token against the Web API at the given base URL. An empty URL is replaced
+// by the public Slack Web API, a TTL lower than or equal to zero by
+// defaultEnrichmentCacheTTL.
+func newEnricher(botToken, apiURL string, cacheTTL time.Duration, logger *zap.SugaredLogger) *enricher {
+	if apiURL == "" {
+		apiURL = defaultSlackAPIURL
+	}
+	if cacheTTL <= 0 {
+		cacheTTL = defaultEnrichmentCacheTTL
+	}
+
+	return &enricher{
+		botToken: botToken,
+		apiURL:   apiURL,
+
+		httpClient: &http.Client{Timeout: enrichmentTimeout},
+
+		users:    newTTLCache(cacheTTL),
+		channels: newTTLCache(cacheTTL),
+
+		logger: logger,
+	}
+}
+
+// enrich sets the names of the user and channel referenced by the given
+// Slack event as extensions of the given CloudEvent. Failed lookups are
+// logged and don't prevent the event from being sent.
+func (e *enricher) enrich(ctx context.Context, event *cloudevents.Event, slackEvent SlackEvent) {
+	if userID := slackEvent.stringField("user"); userID != "" {
+		u, err := e.user(ctx, userID)
+		if err != nil {
+			e.logger.Warnw("Failed to resolve user", zap.Error(err), zap.String("user", userID))
+		} else {
+			setExtensionIfNotEmpty(event, ceExtUserName, u.Name)
+			setExtensionIfNotEmpty(event, ceExtUserRealName, u.Profile.RealName)
+			setExtensionIfNotEmpty(event, ceExtUserEmail, u.Profile.Email)
+		}
+	}
+
+	if channelID := slackEvent.stringField("channel"); channelID != "" {
+		c, err := e.channel(ctx, channelID)
+		if err != nil {
+			e.logger.Warnw("Failed to resolve channel", zap.Error(err), zap.String("channel", channelID))
+		} else {
+			// direct messages have no name
+			setExtensionIfNotEmpty(event, ceExtChannelName, c.Name)
+		}
+	}
+}
+
+// user returns the Slack user with the given ID.
+// See: https://api.slack.com/methods/users.info
+func (e *enricher) user(ctx context.Context, id string) (*slackUser, error) {
+	if u, ok := e.users.get(id); ok {
+		return u.(*slackUser), nil
+	}
+
+	resp := &struct {
+		webAPIResponse
+		User *slackUser `json:"user"`
+	}{}
+	if err := e.call(ctx, "users.info", url.Values{"user": {id}}, resp); err != nil {
+		return nil, err
+	}
+	if !resp.OK || resp.User == nil {
+		return nil, fmt.Errorf("slack API error: %s", resp.Error)
+	}
+
+	e.users.set(id, resp.User)
+	return resp.User, nil
+}
+
+// channel returns the Slack conversation with the given ID.
+// See: https://api.slack.com/methods/conversations.info
+func (e *enricher) channel(ctx context.Context, id string) (*slackChannel, error) {
+	if c, ok := e.channels.get(id); ok {
+		return c.(*slackChannel), nil
+	}
+
+	resp := &struct {
+		webAPIResponse
+		Channel *slackChannel `json:"channel"`
+	}{}
+	if err := e.call(ctx, "conversations.info", url.Values{"channel": {id}}, resp); err != nil {
+		return nil, err
+	}
+	if !resp.OK || resp.Channel == nil {
+		return nil, fmt.Errorf("slack API error: %s", resp.Error)
+	}
+
+	e.channels.set(id, resp.Channel)
+	return resp.Channel, nil
+}
+
+// call invokes the given Web API method with the given arguments and decodes
+// the response into out.
+func (e *enricher) call(ctx context.Context, method string, args url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.apiURL+method+"?"+args.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.botToken)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// setExtensionIfNotEmpty sets the given extension on the given event, unless
+// its value is empty.
+func setExtensionIfNotEmpty(event *cloudevents.Event, name, val string) {
+	if val != "" {
+		event.SetExtension(name, val)
+	}
+}
+
+// ttlCache holds values for a limited duration.
+type ttlCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]ttlCacheEntry
+
+	// allows mocking the current time in tests
+	now func() time.Time
+}
+
+type ttlCacheEntry struct {
+	val interface{}
+	exp time.Time
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[string]ttlCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the value stored for the given key, unless it expired.
+func (c *ttlCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.exp) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.val, true
+}
+
+// set stores the given value for the duration of the cache's TTL.
+func (c *ttlCache) set(key string, val interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = ttlCacheEntry{
+		val: val,
+		exp: c.now().Add(c.ttl),
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+)
+
+const tBotToken = "xoxb-test"
+
+// fakeWebAPI is a local stand-in for the Slack Web API.
+type fakeWebAPI struct {
+	*httptest.Server
+	// number of API calls received
+	calls int32
+}
+
+func newFakeWebAPI(t *testing.T) *fakeWebAPI {
+	api := &fakeWebAPI{}
+
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&api.calls, 1)
+
+		if r.Header.Get("Authorization") != "Bearer "+tBotToken {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_auth"}`))
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/users.info" && r.URL.Query().Get("user") == "U2147483697":
+			_, _ = w.Write([]byte(`{"ok":true,"user":{"id":"U2147483697","name":"spengler",` +
+				`"profile":{"real_name":"Egon Spengler","email":"spengler@ghostbusters.example.com"}}}`))
+		case r.URL.Path == "/conversations.info" && r.URL.Query().Get("channel") == "C2147483705":
+			_, _ = w.Write([]byte(`{"ok":true,"channel":{"id":"C2147483705","name":"general"}}`))
+		case r.URL.Path == "/users.info":
+			_, _ = w.Write([]byte(`{"ok":false,"error":"user_not_found"}`))
+		case r.URL.Path == "/conversations.info":
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func TestSlackEventEnrichment(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	callback := func(user, channel string) string {
+		return `{"team_id":"TXXXXXXXX","api_app_id":"AXXXXXXXXX","type":"event_callback","event_id":"Ev08MFMKH6",` +
+			`"event":{"type":"message","user":"` + user + `","channel":"` + channel + `","text":"Hello"}}`
+	}
+
+	tc := map[string]struct {
+		body string
+
+		expectedExtensions map[string]interface{}
+	}{
+		"known user and channel": {
+			body: callback("U2147483697", "C2147483705"),
+			expectedExtensions: map[string]interface{}{
+				ceExtUserName:     "spengler",
+				ceExtUserRealName: "Egon Spengler",
+				ceExtUserEmail:    "spengler@ghostbusters.example.com",
+				ceExtChannelName:  "general",
+			},
+		},
+		"unknown user": {
+			body: callback("U061F7AUR", "C2147483705"),
+			expectedExtensions: map[string]interface{}{
+				ceExtChannelName: "general",
+			},
+		},
+		"unknown channel": {
+			body: callback("U2147483697", "C061EG9SL"),
+			expectedExtensions: map[string]interface{}{
+				ceExtUserName:     "spengler",
+				ceExtUserRealName: "Egon Spengler",
+				ceExtUserEmail:    "spengler@ghostbusters.example.com",
+			},
+		},
+		"event without user and channel": {
+			body: `{"team_id":"TXXXXXXXX","api_app_id":"AXXXXXXXXX","type":"event_callback",` +
+				`"event_id":"Ev08MFMKH6","event":{"type":"team_join"}}`,
+			expectedExtensions: map[string]interface{}{},
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			api := newFakeWebAPI(t)
+			ceClient := adaptertest.NewTestClient()
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+				WithEnrichment(tBotToken, api.URL+"/", 0),
+			).(*slackEventAPIHandler)
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(c.body))
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+
+			sent := ceClient.Sent()
+			require.Len(t, sent, 1, "enriched event was not sent")
+
+			for ext, val := range c.expectedExtensions {
+				assert.Equal(t, val, sent[0].Extensions()[ext], "unexpected value of extension %q", ext)
+			}
+			for _, ext := range []string{ceExtUserName, ceExtUserRealName, ceExtUserEmail, ceExtChannelName} {
+				if _, expected := c.expectedExtensions[ext]; !expected {
+					assert.NotContains(t, sent[0].Extensions(), ext, "unexpected extension")
+				}
+			}
+		})
+	}
+}
+
+func TestEnrichmentCache(t *testing.T) {
+	api := newFakeWebAPI(t)
+
+	e := newEnricher(tBotToken, api.URL+"/", time.Minute, zapt.NewLogger(t).Sugar())
+
+	now := time.Unix(1593192795, 0)
+	e.users.now = func() time.Time { return now }
+
+	_, err := e.user(context.Background(), "U2147483697")
+	require.NoError(t, err)
+	_, err = e.user(context.Background(), "U2147483697")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&api.calls), "cached user was looked up again")
+
+	now = now.Add(time.Minute)
+
+	_, err = e.user(context.Background(), "U2147483697")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&api.calls), "expired user was not looked up again")
+
+	// failed lookups are not cached
+	_, err = e.user(context.Background(), "U061F7AUR")
+	assert.EqualError(t, err, "slack API error: user_not_found")
+	_, err = e.user(context.Background(), "U061F7AUR")
+	assert.Error(t, err)
+	assert.EqualValues(t, 4, atomic.LoadInt32(&api.calls), "failed lookup was cached")
+}
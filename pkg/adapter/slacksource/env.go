@@ -17,9 +17,18 @@ limitations under the License.
 package slacksource
 
 import (
+	"time"
+
 	"knative.dev/eventing/pkg/adapter/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
+// envAdditionalSigningSecrets is the prefix of the indexed environment
+// variables which contain additional signing secrets.
+const envAdditionalSigningSecrets = "SLACK_ADDITIONAL_SIGNING_SECRET"
+
 // EnvAccessor for configuration parameters
 func EnvAccessor() adapter.EnvConfigAccessor {
 	return &envAccessor{}
@@ -27,6 +36,44 @@ func EnvAccessor() adapter.EnvConfigAccessor {
 
 type envAccessor struct {
 	adapter.EnvConfig
+	limiter.EnvLimits
+	schema.EnvSchema
+
 	AppID         string `envconfig:"SLACK_APP_ID"`
 	SigningSecret string `envconfig:"SLACK_SIGNING_SECRET"`
+	// Populated from indexed variables, see credentials.FromEnv.
+	AdditionalSigningSecrets []string `ignored:"true"`
+	// Populated from indexed variables, see appsFromEnv.
+	Apps []*SlackApp `ignored:"true"`
+
+	AppToken string `envconfig:"SLACK_APP_TOKEN"`
+
+	AllowedCIDRs      []string `envconfig:"SLACK_ALLOWED_CIDRS"`
+	TrustedProxyCIDRs []string `envconfig:"SLACK_TRUSTED_PROXY_CIDRS"`
+
+	EventTypeMode string `envconfig:"SLACK_EVENT_TYPE_MODE" default:"Generic"`
+	ResponseMode  string `envconfig:"SLACK_RESPONSE_MODE" default:"Ack"`
+
+	FilterEventTypes       []string `envconfig:"SLACK_FILTER_EVENT_TYPES"`
+	FilterExcludedSubtypes []string `envconfig:"SLACK_FILTER_EXCLUDED_SUBTYPES"`
+	FilterChannels         []string `envconfig:"SLACK_FILTER_CHANNELS"`
+	FilterUsers            []string `envconfig:"SLACK_FILTER_USERS"`
+	FilterExcludeBots      bool     `envconfig:"SLACK_FILTER_EXCLUDE_BOTS"`
+	FilterExcludeSelf      bool     `envconfig:"SLACK_FILTER_EXCLUDE_SELF"`
+
+	BotToken           string        `envconfig:"SLACK_BOT_TOKEN"`
+	EnrichmentAPIURL   string        `envconfig:"SLACK_ENRICHMENT_API_URL"`
+	EnrichmentCacheTTL time.Duration `envconfig:"SLACK_ENRICHMENT_CACHE_TTL" default:"1h"`
+
+	Deduplication    bool          `envconfig:"SLACK_DEDUPLICATION"`
+	DeduplicationTTL time.Duration `envconfig:"SLACK_DEDUPLICATION_TTL" default:"1h"`
+
+	AsyncDelivery       bool          `envconfig:"SLACK_ASYNC_DELIVERY"`
+	AsyncQueueSize      int           `envconfig:"SLACK_ASYNC_QUEUE_SIZE" default:"100"`
+	AsyncWorkers        int           `envconfig:"SLACK_ASYNC_WORKERS" default:"1"`
+	AsyncRetries        int           `envconfig:"SLACK_ASYNC_RETRIES" default:"3"`
+	AsyncBackoffDelay   time.Duration `envconfig:"SLACK_ASYNC_BACKOFF_DELAY" default:"1s"`
+	AsyncDeadLetterSink string        `envconfig:"SLACK_ASYNC_DEAD_LETTER_SINK"`
+	// Set in multi-tenant mode, see tenantEnv.
+	AsyncQueueName string `ignored:"true"`
 }
@@ -28,6 +28,32 @@ func (e SlackEvent) Type() string {
 	return s.(string)
 }
 
+// Subtype of the event, if any.
+func (e SlackEvent) Subtype() string {
+	return e.stringField("subtype")
+}
+
+// appID returns the ID of the Slack app which generated the event, if any,
+// such as the app which posted a message.
+func (e SlackEvent) appID() string {
+	if id := e.stringField("app_id"); id != "" {
+		return id
+	}
+
+	if profile, ok := e["bot_profile"].(map[string]interface{}); ok {
+		id, _ := profile["app_id"].(string)
+		return id
+	}
+	return ""
+}
+
+// stringField returns the value of the given top-level field of the event,
+// or an empty string if the field isn't a string.
+func (e SlackEvent) stringField(name string) string {
+	s, _ := e[name].(string)
+	return s
+}
+
 // SlackEventWrapper contains a common wrapper for all events.
 // See https://api.slack.com/types/event for reference.
 type SlackEventWrapper struct {
@@ -56,3 +82,56 @@ type SlackChallenge struct {
 type SlackChallengeResponse struct {
 	Challenge string `json:"challenge"`
 }
+
+// SlackAppRateLimited is the notification sent when an app exceeds the rate
+// limit of the Events API.
+// See https://api.slack.com/apis/connections/events-api#rate-limiting
+type SlackAppRateLimited struct {
+	Type              string `json:"type"`
+	TeamID            string `json:"team_id"`
+	APIAppID          string `json:"api_app_id"`
+	MinuteRateLimited int64  `json:"minute_rate_limited"`
+}
+
+// SlackInteraction contains the attributes of an interaction with an
+// interactive component which are used to build CloudEvents.
+// See https://api.slack.com/reference/interaction-payloads for reference.
+type SlackInteraction struct {
+	Type       string             `json:"type"`
+	APIAppID   string             `json:"api_app_id"`
+	TriggerID  string             `json:"trigger_id"`
+	CallbackID string             `json:"callback_id"`
+	Team       SlackTeam          `json:"team"`
+	View       *SlackView         `json:"view"`
+	Actions    []SlackBlockAction `json:"actions"`
+}
+
+// Subject returns the identifier of the component the interaction originates
+// from: the callback ID of a shortcut or view, or the ID of the first action
+// performed on a block element.
+func (i *SlackInteraction) Subject() string {
+	switch {
+	case i.CallbackID != "":
+		return i.CallbackID
+	case i.View != nil && i.View.CallbackID != "":
+		return i.View.CallbackID
+	case len(i.Actions) > 0:
+		return i.Actions[0].ActionID
+	}
+	return ""
+}
+
+// SlackTeam identifies a Slack workspace.
+type SlackTeam struct {
+	ID string `json:"id"`
+}
+
+// SlackView identifies a modal or Home tab view.
+type SlackView struct {
+	CallbackID string `json:"callback_id"`
+}
+
+// SlackBlockAction identifies an action performed on a block element.
+type SlackBlockAction struct {
+	ActionID string `json:"action_id"`
+}
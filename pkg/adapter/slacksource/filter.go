@@ -0,0 +1,86 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+// subtype of messages posted by bots which don't carry a bot_id
+const subtypeBotMessage = "bot_message"
+
+// EventFilter determines which events received from the Events API are sent
+// to the sink. Empty lists don't filter anything.
+type EventFilter struct {
+	EventTypes       []string
+	ExcludedSubtypes []string
+	Channels         []string
+	Users            []string
+
+	ExcludeBots bool
+	ExcludeSelf bool
+}
+
+// Enabled returns whether the filter excludes any event.
+func (f *EventFilter) Enabled() bool {
+	return len(f.EventTypes) > 0 || len(f.ExcludedSubtypes) > 0 ||
+		len(f.Channels) > 0 || len(f.Users) > 0 ||
+		f.ExcludeBots || f.ExcludeSelf
+}
+
+// Match returns whether the event contained in the given callback passes the
+// filter.
+func (f *EventFilter) Match(wrapper *SlackEventWrapper) bool {
+	e := wrapper.Event
+
+	if len(f.EventTypes) > 0 && !contains(f.EventTypes, e.Type()) {
+		return false
+	}
+
+	if subtype := e.Subtype(); subtype != "" && contains(f.ExcludedSubtypes, subtype) {
+		return false
+	}
+
+	if len(f.Channels) > 0 && !contains(f.Channels, e.stringField("channel")) {
+		return false
+	}
+
+	if len(f.Users) > 0 && !contains(f.Users, e.stringField("user")) {
+		return false
+	}
+
+	if f.ExcludeBots && (e.stringField("bot_id") != "" || e.Subtype() == subtypeBotMessage) {
+		return false
+	}
+
+	if f.ExcludeSelf && wrapper.APIAppID != "" && e.appID() == wrapper.APIAppID {
+		return false
+	}
+
+	return true
+}
+
+// contains returns whether the given list contains the given non-empty
+// value.
+func contains(list []string, val string) bool {
+	if val == "" {
+		return false
+	}
+
+	for _, v := range list {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
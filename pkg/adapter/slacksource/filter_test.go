@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+)
+
+const tAPIAppID = "AXXXXXXXXX"
+
+func TestEventFilterMatch(t *testing.T) {
+	tc := map[string]struct {
+		filter EventFilter
+		event  SlackEvent
+
+		expectMatch bool
+	}{
+		"no filter": {
+			event:       SlackEvent{"type": "message"},
+			expectMatch: true,
+		},
+		"allowed event type": {
+			filter:      EventFilter{EventTypes: []string{"app_mention", "message"}},
+			event:       SlackEvent{"type": "message"},
+			expectMatch: true,
+		},
+		"disallowed event type": {
+			filter:      EventFilter{EventTypes: []string{"app_mention"}},
+			event:       SlackEvent{"type": "message"},
+			expectMatch: false,
+		},
+		"excluded subtype": {
+			filter:      EventFilter{ExcludedSubtypes: []string{"message_changed"}},
+			event:       SlackEvent{"type": "message", "subtype": "message_changed"},
+			expectMatch: false,
+		},
+		"event without subtype": {
+			filter:      EventFilter{ExcludedSubtypes: []string{"message_changed"}},
+			event:       SlackEvent{"type": "message"},
+			expectMatch: true,
+		},
+		"allowed channel": {
+			filter:      EventFilter{Channels: []string{"C0LAN2Q65"}},
+			event:       SlackEvent{"type": "message", "channel": "C0LAN2Q65"},
+			expectMatch: true,
+		},
+		"disallowed channel": {
+			filter:      EventFilter{Channels: []string{"C0LAN2Q65"}},
+			event:       SlackEvent{"type": "message", "channel": "C2147483705"},
+			expectMatch: false,
+		},
+		"event without channel": {
+			filter:      EventFilter{Channels: []string{"C0LAN2Q65"}},
+			event:       SlackEvent{"type": "team_join"},
+			expectMatch: false,
+		},
+		"allowed user": {
+			filter:      EventFilter{Users: []string{"U2147483697"}},
+			event:       SlackEvent{"type": "message", "user": "U2147483697"},
+			expectMatch: true,
+		},
+		"disallowed user": {
+			filter:      EventFilter{Users: []string{"U2147483697"}},
+			event:       SlackEvent{"type": "message", "user": "U061F7AUR"},
+			expectMatch: false,
+		},
+		"message from bot": {
+			filter:      EventFilter{ExcludeBots: true},
+			event:       SlackEvent{"type": "message", "bot_id": "B01"},
+			expectMatch: false,
+		},
+		"bot message without bot ID": {
+			filter:      EventFilter{ExcludeBots: true},
+			event:       SlackEvent{"type": "message", "subtype": "bot_message"},
+			expectMatch: false,
+		},
+		"message from user with bots excluded": {
+			filter:      EventFilter{ExcludeBots: true},
+			event:       SlackEvent{"type": "message", "user": "U2147483697"},
+			expectMatch: true,
+		},
+		"message from own app": {
+			filter: EventFilter{ExcludeSelf: true},
+			event: SlackEvent{"type": "message", "bot_id": "B01",
+				"bot_profile": map[string]interface{}{"app_id": tAPIAppID}},
+			expectMatch: false,
+		},
+		"event from own app": {
+			filter:      EventFilter{ExcludeSelf: true},
+			event:       SlackEvent{"type": "message", "app_id": tAPIAppID},
+			expectMatch: false,
+		},
+		"message from other app": {
+			filter: EventFilter{ExcludeSelf: true},
+			event: SlackEvent{"type": "message", "bot_id": "B02",
+				"bot_profile": map[string]interface{}{"app_id": "AYYYYYYYYY"}},
+			expectMatch: true,
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			wrapper := &SlackEventWrapper{
+				APIAppID: tAPIAppID,
+				Event:    c.event,
+			}
+
+			assert.Equal(t, c.expectMatch, c.filter.Match(wrapper))
+		})
+	}
+}
+
+func TestSlackEventFiltering(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	ceClient := adaptertest.NewTestClient()
+
+	handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+		WithEventFilter(&EventFilter{ExcludeBots: true}),
+	).(*slackEventAPIHandler)
+
+	const body = `{
+		"team_id": "TXXXXXXXX",
+		"api_app_id": "AXXXXXXXXX",
+		"event": {"type": "message", "bot_id": "B01", "text": "beep"},
+		"type": "event_callback",
+		"event_id": "Ev08MFMKH6"
+	}`
+
+	req, _ := http.NewRequest(http.MethodPost, "/", read(body))
+	rr := httptest.NewRecorder()
+	handler.handleAll(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+	assert.Empty(t, ceClient.Sent(), "filtered event was sent")
+}
@@ -0,0 +1,252 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+const (
+	contentTypeForm = "application/x-www-form-urlencoded"
+
+	// form field containing the JSON payload of an interaction
+	formFieldPayload = "payload"
+	// form fields sent with slash commands
+	formFieldCommand   = "command"
+	formFieldTeamID    = "team_id"
+	formFieldAPIAppID  = "api_app_id"
+	formFieldTriggerID = "trigger_id"
+	// deprecated in favor of request signing, never propagated
+	fieldToken = "token"
+
+	// maximum duration the sink is given to reply to an interaction, Slack
+	// expects a response within 3 seconds
+	interactionReplyTimeout = 2500 * time.Millisecond
+)
+
+// isForm returns whether the given request contains a form-encoded body, as
+// sent by Slack upon interactions with interactive components and slash
+// commands.
+func isForm(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == contentTypeForm
+}
+
+// isJSONContentType returns whether the given data content type denotes JSON.
+// An empty content type implies JSON, as per the CloudEvents spec.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == cloudevents.ApplicationJSON || strings.HasSuffix(mediaType, "+json"))
+}
+
+// handleForm handles the form-encoded requests sent by Slack upon
+// interactions with interactive components and slash commands.
+// See: https://api.slack.com/interactivity/handling
+func (h *slackEventAPIHandler) handleForm(body []byte, w http.ResponseWriter) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		h.handleError(fmt.Errorf("could not parse form request: %w", err), http.StatusBadRequest, w)
+		return
+	}
+
+	var event *cloudevents.Event
+	var appID string
+
+	switch {
+	case form.Get(formFieldPayload) != "":
+		event, appID, err = cloudEventFromInteraction([]byte(form.Get(formFieldPayload)))
+	case form.Get(formFieldCommand) != "":
+		event, appID, err = cloudEventFromCommand(form)
+	default:
+		err = errors.New("form contains neither an interaction payload nor a slash command")
+	}
+	if err != nil {
+		h.handleError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	reply, code, err := h.processInteraction(event, appID)
+	if err != nil {
+		h.handleError(err, code, w)
+		return
+	}
+	if reply == nil {
+		return
+	}
+
+	// an empty data content type implies JSON, as per the CloudEvents spec
+	contentType := reply.DataContentType()
+	if contentType == "" {
+		contentType = cloudevents.ApplicationJSON
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(reply.Data()); err != nil {
+		h.logger.Errorw("Failed to write response", zap.Error(err))
+	}
+}
+
+// processInteraction sends the given event, unless it is intended for another
+// app, and returns the event the sink replied with, if it has data. The
+// returned status code describes the error, if any.
+func (h *slackEventAPIHandler) processInteraction(event *cloudevents.Event,
+	appID string) (*cloudevents.Event, int, error) {
+
+	if !h.acceptsApp(appID) {
+		return nil, http.StatusOK, nil
+	}
+
+	h.setAppEventType(event, appID)
+
+	h.logger.Debugw("Interaction received", zap.String("type", event.Type()))
+
+	if !h.interactionReplies {
+		code, err := h.dispatch(h.sendContext(), event)
+		return nil, code, err
+	}
+
+	// the event may be sent again if the sink doesn't reply in time, its
+	// ID must be stable for the sink to detect duplicates
+	if event.ID() == "" {
+		event.SetID(uuid.New().String())
+	}
+
+	ctx, cancel := context.WithTimeout(h.sendContext(), interactionReplyTimeout)
+	defer cancel()
+
+	reply, result := h.ceClient.Request(ctx, *event)
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		// the interaction is acknowledged anyway, otherwise Slack
+		// displays an error to the user
+		h.logger.Warnw("The sink did not reply in time, sending interaction asynchronously",
+			zap.String("type", event.Type()), zap.String("id", event.ID()))
+		code, err := h.sendLateInteraction(event)
+		return nil, code, err
+	case !cloudevents.IsACK(result):
+		return nil, http.StatusInternalServerError, fmt.Errorf("could not send Cloud Event: %w", result)
+	case reply == nil || len(reply.Data()) == 0:
+		return nil, http.StatusOK, nil
+	}
+
+	return reply, http.StatusOK, nil
+}
+
+// sendLateInteraction sends an interaction the sink failed to reply to in
+// time, without waiting for the sink. The event is buffered in the delivery
+// queue if there is one, otherwise it is sent in the background.
+func (h *slackEventAPIHandler) sendLateInteraction(event *cloudevents.Event) (int, error) {
+	if h.queue != nil {
+		return h.dispatch(h.sendContext(), event)
+	}
+
+	h.lateInteractions.Add(1)
+	go func() {
+		defer h.lateInteractions.Done()
+
+		if result := h.ceClient.Send(h.sendContext(), *event); !cloudevents.IsACK(result) {
+			h.logger.Errorw("Failed to send interaction", zap.Error(result),
+				zap.String("type", event.Type()), zap.String("id", event.ID()))
+		}
+	}()
+
+	return http.StatusOK, nil
+}
+
+// cloudEventFromInteraction returns a CloudEvent for the given interaction
+// payload, along with the ID of the Slack app the interaction is intended for.
+func cloudEventFromInteraction(payload []byte) (*cloudevents.Event, string, error) {
+	i := &SlackInteraction{}
+	if err := json.Unmarshal(payload, i); err != nil {
+		return nil, "", fmt.Errorf("could not unmarshall interaction payload: %w", err)
+	}
+	if i.Type == "" {
+		return nil, "", errors.New("interaction payload has no type")
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, "", fmt.Errorf("could not unmarshall interaction payload: %w", err)
+	}
+	delete(data, fieldToken)
+
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+
+	// trigger IDs are unique per interaction, events which don't have
+	// one are assigned a generated ID by the CloudEvents client
+	if i.TriggerID != "" {
+		event.SetID(i.TriggerID)
+	}
+	event.SetType(v1alpha1.SlackInteractionEventTypePrefix + i.Type)
+	event.SetSource(i.Team.ID)
+	event.SetExtension(apiAppIdCeExtension, i.APIAppID)
+	if subject := i.Subject(); subject != "" {
+		event.SetSubject(subject)
+	}
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, "", err
+	}
+
+	return &event, i.APIAppID, nil
+}
+
+// cloudEventFromCommand returns a CloudEvent for the given slash command
+// form, along with the ID of the Slack app the command is intended for.
+// See https://api.slack.com/interactivity/slash-commands#app_command_handling
+func cloudEventFromCommand(form url.Values) (*cloudevents.Event, string, error) {
+	data := make(map[string]string, len(form))
+	for k := range form {
+		if k == fieldToken {
+			continue
+		}
+		data[k] = form.Get(k)
+	}
+
+	appID := form.Get(formFieldAPIAppID)
+
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+
+	if triggerID := form.Get(formFieldTriggerID); triggerID != "" {
+		event.SetID(triggerID)
+	}
+	event.SetType(v1alpha1.SlackCommandEventType)
+	event.SetSource(form.Get(formFieldTeamID))
+	event.SetExtension(apiAppIdCeExtension, appID)
+	event.SetSubject(form.Get(formFieldCommand))
+	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return nil, "", err
+	}
+
+	return &event, appID, nil
+}
@@ -0,0 +1,298 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestSlackInteraction(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	tc := map[string]struct {
+		form  url.Values
+		appID string
+
+		expectedCode    int
+		expectedType    string
+		expectedID      string
+		expectedSource  string
+		expectedSubject string
+		expectedData    string
+	}{
+		"block actions": {
+			form: url.Values{formFieldPayload: {`{
+				"type": "block_actions",
+				"token": "XXYYZZ",
+				"api_app_id": "AXXXXXXXXX",
+				"trigger_id": "1337.42.abcd",
+				"team": {"id": "TXXXXXXXX"},
+				"actions": [{"action_id": "approve"}]
+			}`}},
+
+			expectedCode:    http.StatusOK,
+			expectedType:    v1alpha1.SlackBlockActionsEventType,
+			expectedID:      "1337.42.abcd",
+			expectedSource:  "TXXXXXXXX",
+			expectedSubject: "approve",
+			expectedData: `{"actions":[{"action_id":"approve"}],"api_app_id":"AXXXXXXXXX",` +
+				`"team":{"id":"TXXXXXXXX"},"trigger_id":"1337.42.abcd","type":"block_actions"}`,
+		},
+		"view submission": {
+			form: url.Values{formFieldPayload: {`{
+				"type": "view_submission",
+				"api_app_id": "AXXXXXXXXX",
+				"trigger_id": "1337.42.abcd",
+				"team": {"id": "TXXXXXXXX"},
+				"view": {"callback_id": "deploy_form"}
+			}`}},
+
+			expectedCode:    http.StatusOK,
+			expectedType:    v1alpha1.SlackViewSubmissionEventType,
+			expectedID:      "1337.42.abcd",
+			expectedSource:  "TXXXXXXXX",
+			expectedSubject: "deploy_form",
+		},
+		"slash command": {
+			form: url.Values{
+				"token":            {"XXYYZZ"},
+				"command":          {"/deploy"},
+				"text":             {"production"},
+				"team_id":          {"TXXXXXXXX"},
+				"api_app_id":       {"AXXXXXXXXX"},
+				"trigger_id":       {"1337.42.abcd"},
+				"response_url":     {"https://hooks.slack.com/commands/1234/5678"},
+				"user_id":          {"UXXXXXXXX"},
+				"channel_id":       {"CXXXXXXXX"},
+				"is_enterprise_id": {"false"},
+			},
+
+			expectedCode:    http.StatusOK,
+			expectedType:    v1alpha1.SlackCommandEventType,
+			expectedID:      "1337.42.abcd",
+			expectedSource:  "TXXXXXXXX",
+			expectedSubject: "/deploy",
+			expectedData: `{"api_app_id":"AXXXXXXXXX","channel_id":"CXXXXXXXX","command":"/deploy",` +
+				`"is_enterprise_id":"false","response_url":"https://hooks.slack.com/commands/1234/5678",` +
+				`"team_id":"TXXXXXXXX","text":"production","trigger_id":"1337.42.abcd","user_id":"UXXXXXXXX"}`,
+		},
+		"interaction for another app": {
+			form: url.Values{formFieldPayload: {`{
+				"type": "shortcut",
+				"api_app_id": "AXXXXXXXXX",
+				"team": {"id": "TXXXXXXXX"}
+			}`}},
+			appID: "AYYYYYYYYY",
+
+			expectedCode: http.StatusOK,
+		},
+		"interaction without type": {
+			form: url.Values{formFieldPayload: {`{"api_app_id": "AXXXXXXXXX"}`}},
+
+			expectedCode: http.StatusBadRequest,
+		},
+		"malformed interaction payload": {
+			form: url.Values{formFieldPayload: {`{not JSON}`}},
+
+			expectedCode: http.StatusBadRequest,
+		},
+		"unknown form": {
+			form: url.Values{"foo": {"bar"}},
+
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, c.appID, standardTime{}, logger).(*slackEventAPIHandler)
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, newFormRequest(c.form))
+
+			assert.Equal(t, c.expectedCode, rr.Code, "unexpected response code")
+			if c.expectedCode == http.StatusOK {
+				assert.Empty(t, rr.Body.String(), "unexpected response body")
+			}
+
+			sent := ceClient.Sent()
+			if c.expectedType == "" {
+				assert.Empty(t, sent, "unexpected event was sent")
+				return
+			}
+
+			require.Len(t, sent, 1)
+			event := sent[0]
+			assert.Equal(t, c.expectedType, event.Type())
+			assert.Equal(t, c.expectedID, event.ID())
+			assert.Equal(t, c.expectedSource, event.Source())
+			assert.Equal(t, c.expectedSubject, event.Subject())
+			assert.Equal(t, "AXXXXXXXXX", event.Extensions()[apiAppIdCeExtension])
+			if c.expectedData != "" {
+				assert.JSONEq(t, c.expectedData, string(event.Data()))
+			}
+		})
+	}
+}
+
+func TestSlackInteractionReplies(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const payload = `{
+		"type": "view_submission",
+		"api_app_id": "AXXXXXXXXX",
+		"team": {"id": "TXXXXXXXX"},
+		"view": {"callback_id": "deploy_form"}
+	}`
+
+	reply := cloudevents.NewEvent()
+	reply.SetID("reply")
+	reply.SetType("io.triggermesh.slack.reply")
+	reply.SetSource("sink")
+	require.NoError(t, reply.SetData(cloudevents.ApplicationJSON, map[string]string{"response_action": "clear"}))
+
+	textReply := reply.Clone()
+	require.NoError(t, textReply.SetData(cloudevents.TextPlain, "Deployment started"))
+
+	tc := map[string]struct {
+		reply  *cloudevents.Event
+		result protocol.Result
+		delay  time.Duration
+
+		expectedCode        int
+		expectedBody        string
+		expectedContentType string
+		expectLateSend      bool
+	}{
+		"sink replies": {
+			reply:               &reply,
+			result:              protocol.ResultACK,
+			expectedCode:        http.StatusOK,
+			expectedBody:        `{"response_action":"clear"}`,
+			expectedContentType: cloudevents.ApplicationJSON,
+		},
+		"sink replies with text": {
+			reply:               &textReply,
+			result:              protocol.ResultACK,
+			expectedCode:        http.StatusOK,
+			expectedBody:        "Deployment started",
+			expectedContentType: cloudevents.TextPlain,
+		},
+		"sink does not reply": {
+			result:       protocol.ResultACK,
+			expectedCode: http.StatusOK,
+		},
+		"sink replies too late": {
+			reply:          &reply,
+			result:         protocol.ResultACK,
+			delay:          interactionReplyTimeout + time.Second,
+			expectedCode:   http.StatusOK,
+			expectLateSend: true,
+		},
+		"sink fails": {
+			result:       protocol.NewReceipt(false, "sink unavailable"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ceClient := &replyingClient{
+				TestCloudEventsClient: adaptertest.NewTestClient(),
+				reply:                 c.reply,
+				result:                c.result,
+				delay:                 c.delay,
+			}
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+				WithInteractionReplies(),
+			).(*slackEventAPIHandler)
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, newFormRequest(url.Values{formFieldPayload: {payload}}))
+
+			assert.Equal(t, c.expectedCode, rr.Code, "unexpected response code")
+			if c.expectedBody != "" {
+				assert.Equal(t, c.expectedBody, rr.Body.String(), "unexpected response body")
+				assert.Equal(t, c.expectedContentType, rr.Header().Get("Content-Type"))
+			} else if c.expectedCode == http.StatusOK {
+				assert.Empty(t, rr.Body.String(), "unexpected response body")
+			}
+
+			handler.drain(context.Background())
+
+			sent := ceClient.Sent()
+			if !c.expectLateSend {
+				assert.Empty(t, sent, "unexpected event was sent")
+				return
+			}
+			require.Len(t, sent, 1, "the interaction should be sent after the reply timeout")
+			assert.NotEmpty(t, sent[0].ID())
+			assert.Equal(t, ceClient.requested.ID(), sent[0].ID(), "the interaction should keep its ID")
+		})
+	}
+}
+
+// replyingClient is a CloudEvents client which replies to requests with a
+// given event, after an optional delay.
+type replyingClient struct {
+	*adaptertest.TestCloudEventsClient
+
+	reply  *cloudevents.Event
+	result protocol.Result
+	delay  time.Duration
+
+	// last event passed to Request
+	requested cloudevents.Event
+}
+
+// Request implements cloudevents.Client.
+func (c *replyingClient) Request(ctx context.Context, e cloudevents.Event) (*cloudevents.Event, protocol.Result) {
+	c.requested = e
+
+	select {
+	case <-time.After(c.delay):
+		return c.reply, c.result
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newFormRequest(form url.Values) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/", read(form.Encode()))
+	req.Header.Set("Content-Type", contentTypeForm)
+	return req
+}
@@ -0,0 +1,269 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
+	k8sclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/env"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/router"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/pkg/routing"
+	"github.com/triggermesh/knative-sources/pkg/secret"
+)
+
+const serverShutdownGracePeriod = time.Second * 10
+
+// mtAdapter is the multi-tenant flavour of the source's adapter. It serves
+// all SlackSources of a namespace, each at its own URL path.
+type mtAdapter struct {
+	logger *zap.SugaredLogger
+
+	ceClient   cloudevents.Client
+	secrGetter secret.Getter
+
+	// fields accessed during object reconciliation
+	router *router.Router
+
+	// tenants currently registered, indexed by URL path
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+// tenant is the handler serving a single SlackSource in the multi-tenant
+// adapter.
+type tenant struct {
+	handler *slackEventAPIHandler
+
+	// configuration the handler was created from
+	env  *envAccessor
+	sink string
+
+	// closes the Socket Mode connection of the handler, if any
+	stop context.CancelFunc
+}
+
+// Check the interfaces mtAdapter should implement.
+var (
+	_ pkgadapter.Adapter = (*mtAdapter)(nil)
+	_ MTAdapter          = (*mtAdapter)(nil)
+	_ http.Handler       = (*mtAdapter)(nil)
+)
+
+// NewEnvConfig satisfies env.ConfigConstructor.
+// Returns an accessor for the multi-tenant adapter envConfig.
+func NewEnvConfig() env.ConfigAccessor {
+	return &env.Config{}
+}
+
+// NewMTAdapter returns a constructor for the source's multi-tenant adapter.
+func NewMTAdapter(component string) pkgadapter.AdapterConstructor {
+	return func(ctx context.Context, _ pkgadapter.EnvConfigAccessor,
+		ceClient cloudevents.Client) pkgadapter.Adapter {
+
+		ns := injection.GetNamespaceScope(ctx)
+
+		return &mtAdapter{
+			logger: logging.FromContext(ctx),
+
+			ceClient:   ceClient,
+			secrGetter: secret.NewGetter(k8sclient.Get(ctx).CoreV1().Secrets(ns)),
+
+			router:  &router.Router{},
+			tenants: make(map[string]*tenant),
+		}
+	}
+}
+
+// Start implements adapter.Adapter.
+func (a *mtAdapter) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    fmt.Sprint(":", defaultListenPort),
+		Handler: a,
+	}
+
+	err := runServer(ctx, server)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// all queues are drained concurrently, within a single grace period
+	drainCtx, cancel := context.WithTimeout(context.Background(), serverShutdownGracePeriod)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for path, t := range a.tenants {
+		t.stop()
+		wg.Add(1)
+		go func(h *slackEventAPIHandler) {
+			defer wg.Done()
+			a.drainQueue(drainCtx, h)
+		}(t.handler)
+		delete(a.tenants, path)
+	}
+	wg.Wait()
+
+	return err
+}
+
+// runServer runs the given HTTP server until ctx gets cancelled.
+func runServer(ctx context.Context, s *http.Server) error {
+	logging.FromContext(ctx).Info("Starting Slack event handler")
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- s.ListenAndServe()
+	}()
+
+	handleServerError := func(err error) error {
+		if err != http.ErrServerClosed {
+			return fmt.Errorf("during server runtime: %w", err)
+		}
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		logging.FromContext(ctx).Info("Slack event handler is shutting down")
+
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownGracePeriod)
+		defer cancel()
+
+		if err := s.Shutdown(ctx); err != nil {
+			return fmt.Errorf("during server shutdown: %w", err)
+		}
+		return handleServerError(<-errCh)
+
+	case err := <-errCh:
+		return handleServerError(err)
+	}
+}
+
+// ServeHTTP implements http.Handler.
+// Delegates incoming requests to the underlying router.
+func (a *mtAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.router.ServeHTTP(w, r)
+}
+
+// RegisterHandlerFor implements MTAdapter.
+// The handler of a source is only replaced when its configuration changed,
+// so that the state it accumulates, such as the IDs of processed events or
+// its Socket Mode connection, survives periodic reconciliations.
+func (a *mtAdapter) RegisterHandlerFor(ctx context.Context, src *v1alpha1.SlackSource) error {
+	env, err := tenantEnv(src, a.secrGetter)
+	if err != nil {
+		return err
+	}
+	sink := src.Status.SinkURI.String()
+
+	urlPath := routing.URLPath(src)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prev, hasPrev := a.tenants[urlPath]
+	if hasPrev && prev.sink == sink && reflect.DeepEqual(prev.env, env) {
+		return nil
+	}
+
+	logger := a.logger.With(zap.String("source", src.Namespace+"/"+src.Name))
+
+	h, err := newHandler(env, a.ceClient, logger)
+	if err != nil {
+		return fmt.Errorf("configuring Slack handler: %w", err)
+	}
+	h.sink = sink
+
+	if h.queue != nil {
+		h.queue.Start()
+	}
+
+	t := &tenant{
+		handler: h,
+		env:     env,
+		sink:    sink,
+	}
+
+	var socketCtx context.Context
+	socketCtx, t.stop = context.WithCancel(context.Background())
+
+	if h.socketMode != nil {
+		// events are received over the Socket Mode connection, not at
+		// the URL path of the source
+		a.router.DeregisterPath(urlPath)
+		go h.socketMode.run(socketCtx)
+	} else {
+		a.router.RegisterPath(urlPath, h.httpHandler())
+	}
+
+	if hasPrev {
+		prev.stop()
+		a.drainQueueInBackground(prev.handler)
+	}
+	a.tenants[urlPath] = t
+
+	return nil
+}
+
+// DeregisterHandlerFor implements MTAdapter.
+func (a *mtAdapter) DeregisterHandlerFor(ctx context.Context, src v1alpha1.EventSource) error {
+	urlPath := routing.URLPath(src)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.router.DeregisterPath(urlPath)
+
+	if t, ok := a.tenants[urlPath]; ok {
+		t.stop()
+		a.drainQueueInBackground(t.handler)
+		delete(a.tenants, urlPath)
+	}
+
+	return nil
+}
+
+// drainQueueInBackground drains the delivery queue of the given handler
+// without blocking, within the server's shutdown grace period.
+func (a *mtAdapter) drainQueueInBackground(h *slackEventAPIHandler) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownGracePeriod)
+		defer cancel()
+
+		a.drainQueue(ctx, h)
+	}()
+}
+
+// drainQueue delivers the events buffered in the delivery queue of the given
+// handler, if any, until ctx is done.
+func (a *mtAdapter) drainQueue(ctx context.Context, h *slackEventAPIHandler) {
+	h.drain(ctx)
+}
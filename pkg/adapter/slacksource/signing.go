@@ -17,6 +17,7 @@ limitations under the License.
 package slacksource
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -25,6 +26,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
 )
 
 const (
@@ -46,9 +49,12 @@ func (standardTime) Now() time.Time {
 
 var _ timeWrap = (*standardTime)(nil)
 
-// verifySigning using signature headers and request body hash.
+// verifySigning using signature headers and request body hash. The request is
+// authenticated if it was signed with any of the given secrets.
 // see: https://api.slack.com/authentication/verifying-requests-from-slack
-func (h *slackEventAPIHandler) verifySigning(header http.Header, body []byte) error {
+func (h *slackEventAPIHandler) verifySigning(ctx context.Context, header http.Header, body []byte,
+	secrets ...*credentials.Set) error {
+
 	signature := header.Get(signatureHeader)
 	if signature == "" {
 		return errors.New("empty signature header")
@@ -73,19 +79,27 @@ func (h *slackEventAPIHandler) verifySigning(header http.Header, body []byte) er
 		return errors.New("signing timestamp expired")
 	}
 
-	signString := "v0:" + timestamp + ":" + string(body)
-	hm := hmac.New(sha256.New, []byte(h.signingSecret))
-	if _, err := hm.Write([]byte(signString)); err != nil {
-		return fmt.Errorf("error writing signing string into hmac: %w", err)
+	// remove `v=0` from signature
+	received, err := hex.DecodeString(signature[3:])
+	if err != nil {
+		return errors.New("received wrong signature signing hash")
 	}
 
-	hash := hm.Sum(nil)
-	challenge := hex.EncodeToString(hash)
+	signString := []byte("v0:" + timestamp + ":" + string(body))
 
-	// remove `v=0` from signature
-	if challenge != signature[3:] {
-		return errors.New("received wrong signature signing hash")
+	// any of the configured secrets may have been used to sign the request
+	// while a secret rotation is in progress
+	matches := func(secret string) bool {
+		hm := hmac.New(sha256.New, []byte(secret))
+		hm.Write(signString) //nolint:errcheck // hash.Hash never returns an error
+		return hmac.Equal(hm.Sum(nil), received)
+	}
+
+	for _, s := range secrets {
+		if s.Match(ctx, matches) {
+			return nil
+		}
 	}
 
-	return nil
+	return errors.New("received wrong signature signing hash")
 }
@@ -24,14 +24,50 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
 	"go.uber.org/zap"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
 )
 
-const apiAppIdCeExtension = "comslackapiappid"
+const (
+	apiAppIdCeExtension = "comslackapiappid"
+
+	// headers set by Slack on retried requests
+	// See: https://api.slack.com/apis/connections/events-api#retries
+	headerSlackRetryNum    = "X-Slack-Retry-Num"
+	headerSlackRetryReason = "X-Slack-Retry-Reason"
+
+	// CloudEvents extensions set on events which delivery was retried
+	ceExtRetryNum    = "comslackretrynum"
+	ceExtRetryReason = "comslackretryreason"
+)
+
+// deliveryRetry describes a re-delivery of an event by Slack. Its zero value
+// describes a first delivery.
+type deliveryRetry struct {
+	num    int
+	reason string
+}
+
+// retryFromHeader returns the deliveryRetry described by the headers of a
+// request sent by Slack.
+func retryFromHeader(hdr http.Header) deliveryRetry {
+	num, _ := strconv.Atoi(hdr.Get(headerSlackRetryNum))
+	return deliveryRetry{
+		num:    num,
+		reason: hdr.Get(headerSlackRetryReason),
+	}
+}
 
 // SlackEventAPIHandler listen for Slack API Events
 type SlackEventAPIHandler interface {
@@ -39,28 +75,173 @@ type SlackEventAPIHandler interface {
 }
 
 type slackEventAPIHandler struct {
-	port          int
-	signingSecret string
-	appID         string
+	port           int
+	signingSecrets *credentials.Set
+	appID          string
+	// optional, Slack apps accepted in multi-app mode, by ID. Supersedes
+	// appID and signingSecrets.
+	apps map[string]*SlackApp
+
+	// optional, restricts the IP addresses requests are accepted from
+	ipFilter *ipfilter.Filter
+	// optional, restricts the size and rate of requests
+	limiter *limiter.Limiter
+	// optional, restricts the events which are sent
+	filter *EventFilter
+	// optional, suppresses duplicate deliveries of the same event
+	dedup *dedup.Cache
+	// optional, enables the asynchronous delivery of events
+	queue *delivery.Queue
+	// optional, validates events against the schema of the Events API
+	validator *schema.Validator
+	// optional, resolves the users and channels referenced by events
+	enricher *enricher
+	// optional, receives events over a Socket Mode connection instead of
+	// HTTP requests
+	socketMode *socketModeClient
+	// whether events are typed after the type of the Slack event
+	specificEventTypes bool
+	// whether the sink replies to interactions and slash commands
+	interactionReplies bool
+	// tracks interactions sent in the background after the sink failed to
+	// reply in time
+	lateInteractions sync.WaitGroup
 
 	ceClient cloudevents.Client
-	srv      *http.Server
+	// optional, target of events when it differs from the sink of the
+	// adapter, such as in multi-tenant mode
+	sink string
+	srv  *http.Server
 
 	time   timeWrap
 	logger *zap.SugaredLogger
 }
 
+// HandlerOption is a functional option for a Slack API Events handler.
+type HandlerOption func(*slackEventAPIHandler)
+
+// WithApps accepts events originating from any of the given Slack apps,
+// which requests are authenticated with their own signing secrets.
+func WithApps(apps ...*SlackApp) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.apps = make(map[string]*SlackApp, len(apps))
+		for _, app := range apps {
+			h.apps[app.ID] = app
+		}
+	}
+}
+
+// WithSink sends events to the given sink instead of the default target of
+// the CloudEvents client.
+func WithSink(uri string) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.sink = uri
+	}
+}
+
+// WithIPFilter restricts the IP addresses the handler accepts requests from.
+func WithIPFilter(f *ipfilter.Filter) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.ipFilter = f
+	}
+}
+
+// WithLimiter restricts the size and rate of the requests accepted by the
+// handler.
+func WithLimiter(l *limiter.Limiter) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.limiter = l
+	}
+}
+
+// WithEventFilter restricts the events received from the Events API which are
+// sent to those which pass the given filter.
+func WithEventFilter(f *EventFilter) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.filter = f
+	}
+}
+
+// WithDeduplication suppresses duplicate deliveries of events which ID is
+// contained in the given cache.
+func WithDeduplication(c *dedup.Cache) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.dedup = c
+	}
+}
+
+// WithAsyncDelivery acknowledges events as soon as they are buffered in a
+// delivery queue with the given configuration, instead of after they were
+// delivered to the sink.
+func WithAsyncDelivery(cfg delivery.Config) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.queue = delivery.New(h.ceClient, cfg, h.logger.Named("delivery"))
+	}
+}
+
+// WithValidator validates the payload of events against a JSON Schema before
+// sending them.
+func WithValidator(v *schema.Validator) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.validator = v
+	}
+}
+
+// WithEnrichment sets the names of the users and channels referenced by
+// events received from the Events API as extensions of the events. Names are
+// resolved through the Slack Web API at the given base URL, authenticated
+// with the given bot token, and cached for the given duration.
+func WithEnrichment(botToken, apiURL string, cacheTTL time.Duration) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.enricher = newEnricher(botToken, apiURL, cacheTTL, h.logger.Named("enrichment"))
+	}
+}
+
+// WithSocketMode receives events over a Socket Mode connection authenticated
+// with the given app-level token, instead of HTTP requests.
+func WithSocketMode(appToken string) HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.socketMode = newSocketModeClient(appToken, h)
+	}
+}
+
+// WithSpecificEventTypes sets the type of events received from the Events API
+// after the type and subtype of the Slack event, instead of the generic
+// "com.slack.events".
+func WithSpecificEventTypes() HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.specificEventTypes = true
+	}
+}
+
+// WithInteractionReplies responds to interactions and slash commands with the
+// data of the event the sink replies with, instead of an empty
+// acknowledgement.
+func WithInteractionReplies() HandlerOption {
+	return func(h *slackEventAPIHandler) {
+		h.interactionReplies = true
+	}
+}
+
 // NewSlackEventAPIHandler creates the default implementation of the Slack API Events handler
-func NewSlackEventAPIHandler(ceClient cloudevents.Client, port int, signingSecret, appID string, tw timeWrap, logger *zap.SugaredLogger) SlackEventAPIHandler {
-	return &slackEventAPIHandler{
-		port:          port,
-		signingSecret: signingSecret,
-		appID:         appID,
+func NewSlackEventAPIHandler(ceClient cloudevents.Client, port int, signingSecrets *credentials.Set, appID string,
+	tw timeWrap, logger *zap.SugaredLogger, opts ...HandlerOption) SlackEventAPIHandler {
+
+	h := &slackEventAPIHandler{
+		port:           port,
+		signingSecrets: signingSecrets,
+		appID:          appID,
 
 		ceClient: ceClient,
 		time:     tw,
 		logger:   logger,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // Start the server for receiving Slack callbacks. Will block
@@ -68,8 +249,21 @@ func NewSlackEventAPIHandler(ceClient cloudevents.Client, port int, signingSecre
 func (h *slackEventAPIHandler) Start(ctx context.Context) error {
 	h.logger.Info("Starting Slack event handler")
 
+	handler := h.httpHandler()
+
+	if h.socketMode != nil {
+		// events are received over the Socket Mode connection, the HTTP
+		// server only keeps the adapter's Knative Service ready
+		handler = http.NotFoundHandler()
+		go h.socketMode.run(ctx)
+	}
+
+	if h.queue != nil {
+		h.queue.Start()
+	}
+
 	m := http.NewServeMux()
-	m.HandleFunc("/", h.handleAll)
+	m.Handle("/", handler)
 
 	h.srv = &http.Server{
 		Addr:    ":" + strconv.Itoa(h.port),
@@ -89,6 +283,29 @@ func (h *slackEventAPIHandler) Start(ctx context.Context) error {
 	return nil
 }
 
+// httpHandler returns the HTTP handler which receives Slack events, wrapped
+// with the optional IP filter and request limiter.
+func (h *slackEventAPIHandler) httpHandler() http.Handler {
+	var handler http.Handler = http.HandlerFunc(h.handleAll)
+	if h.limiter != nil {
+		handler = h.limiter.Handler(handler)
+	}
+	if h.ipFilter != nil {
+		handler = h.ipFilter.Handler(handler)
+	}
+
+	return handler
+}
+
+// sendContext returns the context events are sent with.
+func (h *slackEventAPIHandler) sendContext() context.Context {
+	ctx := context.Background()
+	if h.sink != "" {
+		ctx = cloudevents.ContextWithTarget(ctx, h.sink)
+	}
+	return ctx
+}
+
 // handleAll receives all Slack events at a single resource, it
 // is up to this function to parse event wrapper and dispatch.
 func (h *slackEventAPIHandler) handleAll(w http.ResponseWriter, r *http.Request) {
@@ -104,14 +321,21 @@ func (h *slackEventAPIHandler) handleAll(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if h.signingSecret != "" {
-		err = h.verifySigning(r.Header, body)
+	if secrets := h.signingSecretsFor(r, body); secrets != nil {
+		err = h.verifySigning(r.Context(), r.Header, body, secrets...)
 		if err != nil {
 			h.handleError(err, http.StatusUnauthorized, w)
 			return
 		}
 	}
 
+	// interactions and slash commands are form-encoded, they are not
+	// subject to the schema of the Events API
+	if isForm(r) {
+		h.handleForm(body, w)
+		return
+	}
+
 	event := &SlackEventWrapper{}
 	err = json.Unmarshal(body, event)
 	if err != nil {
@@ -124,15 +348,19 @@ func (h *slackEventAPIHandler) handleAll(w http.ResponseWriter, r *http.Request)
 	// - `event_callback`, See: https://api.slack.com/events-api#subscriptions
 	switch event.Type {
 	case "event_callback":
-		// All paths that are not managed by this integration and are
-		// not errors need to return 2xx withing 3 seconds to Slack API.
-		// Otherwise the message will be retried.
-		// See: https://api.slack.com/events-api#receiving_events (Responding to Events)
-		if h.appID != "" && event.APIAppID != h.appID {
-			return
+		code, err := h.processCallback(event, body, retryFromHeader(r.Header))
+		if err != nil {
+			h.handleError(err, code, w)
 		}
 
-		h.handleCallback(event, w)
+	case "app_rate_limited":
+		// Slack stops sending events to apps which exceed the rate limit
+		// of the Events API for the rest of the minute.
+		// See: https://api.slack.com/apis/connections/events-api#rate-limiting
+		code, err := h.processRateLimited(body)
+		if err != nil {
+			h.handleError(err, code, w)
+		}
 
 	case "url_verification":
 		// url_verification does not include an appID so there is no way to
@@ -156,9 +384,34 @@ func (h *slackEventAPIHandler) gracefulShutdown(stopCh <-chan struct{}, done cha
 	if err := h.srv.Shutdown(ctx); err != nil {
 		h.logger.Fatalf("Could not gracefully shutdown the server: %v", err)
 	}
+
+	// buffered events are delivered within the same grace period
+	h.drain(ctx)
 	close(done)
 }
 
+// drain delivers the events buffered in the delivery queue, if any, and waits
+// for the interactions being sent in the background, until ctx is done.
+func (h *slackEventAPIHandler) drain(ctx context.Context) {
+	if h.queue != nil {
+		if err := h.queue.Drain(ctx); err != nil {
+			h.logger.Errorw("Failed to drain delivery queue", zap.Error(err))
+		}
+	}
+
+	sent := make(chan struct{})
+	go func() {
+		h.lateInteractions.Wait()
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+	case <-ctx.Done():
+		h.logger.Errorw("Failed to send pending interactions", zap.Error(ctx.Err()))
+	}
+}
+
 func (h *slackEventAPIHandler) handleError(err error, code int, w http.ResponseWriter) {
 	h.logger.Error("An error ocurred", zap.Error(err))
 	http.Error(w, err.Error(), code)
@@ -189,25 +442,171 @@ func (h *slackEventAPIHandler) handleChallenge(body []byte, w http.ResponseWrite
 	}
 }
 
-func (h *slackEventAPIHandler) handleCallback(wrapper *SlackEventWrapper, w http.ResponseWriter) {
+// processCallback sends the event contained in the given callback, unless it
+// is intended for another app or was already processed. The returned status
+// code describes the error, if any.
+func (h *slackEventAPIHandler) processCallback(wrapper *SlackEventWrapper, body []byte,
+	retry deliveryRetry) (code int, err error) {
+
+	// All paths that are not managed by this integration and are
+	// not errors need to return 2xx withing 3 seconds to Slack API.
+	// Otherwise the message will be retried.
+	// See: https://api.slack.com/events-api#receiving_events (Responding to Events)
+	if !h.acceptsApp(wrapper.APIAppID) {
+		return http.StatusOK, nil
+	}
+
+	// filtered events are acknowledged, otherwise Slack retries them
+	if h.filter != nil && !h.filter.Match(wrapper) {
+		h.logger.Debugw("Ignoring filtered event",
+			zap.String("eventID", wrapper.EventID),
+			zap.String("type", wrapper.Event.Type()))
+		return http.StatusOK, nil
+	}
+
+	// Slack preserves the event_id of retried deliveries.
+	// See: https://api.slack.com/apis/connections/events-api#retries
+	//
+	// The event_id is reserved until the event is processed, so that
+	// deliveries which are retried while the original one is still being
+	// processed are suppressed as well.
+	if h.dedup != nil && wrapper.EventID != "" {
+		if !h.dedup.Reserve(wrapper.EventID) {
+			h.logger.Debugw("Ignoring duplicate event",
+				zap.String("eventID", wrapper.EventID),
+				zap.Int("retryNum", retry.num),
+				zap.String("retryReason", retry.reason))
+			return http.StatusOK, nil
+		}
+
+		// retries of events which couldn't be processed must not be
+		// suppressed
+		defer func() {
+			if err != nil {
+				h.dedup.Release(wrapper.EventID)
+			}
+		}()
+	}
+
 	h.logger.Info("callback received")
 
-	event, err := cloudEventFromEventWrapper(wrapper)
+	event, err := cloudEventFromEventWrapper(wrapper, h.specificEventTypes)
 	if err != nil {
-		h.handleError(err, http.StatusBadRequest, w)
-		return
+		return http.StatusBadRequest, err
 	}
 
-	if result := h.ceClient.Send(context.Background(), *event); !cloudevents.IsACK(result) {
-		h.handleError(err, http.StatusInternalServerError, w)
+	h.setAppEventType(event, wrapper.APIAppID)
+
+	if h.enricher != nil {
+		h.enricher.enrich(context.Background(), event, wrapper.Event)
+	}
+
+	if retry.num > 0 {
+		reportRetry(context.Background(), retry.reason)
+
+		event.SetExtension(ceExtRetryNum, retry.num)
+		if retry.reason != "" {
+			event.SetExtension(ceExtRetryReason, retry.reason)
+		}
 	}
+
+	ctx := h.sendContext()
+
+	if h.validator != nil {
+		valid, err := h.validator.CheckDocument(ctx, body, event)
+		switch {
+		case schema.IsValidationError(err):
+			return http.StatusBadRequest, err
+		case err != nil:
+			return http.StatusInternalServerError, err
+		case !valid:
+			return http.StatusOK, nil
+		}
+	}
+
+	if code, err := h.dispatch(ctx, event); err != nil {
+		return code, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// processRateLimited sends an event notifying that the app was rate-limited,
+// unless the notification is intended for another app. The returned status
+// code describes the error, if any.
+func (h *slackEventAPIHandler) processRateLimited(body []byte) (int, error) {
+	rl := &SlackAppRateLimited{}
+	if err := json.Unmarshal(body, rl); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("could not unmarshall rate limit notification: %w", err)
+	}
+
+	if !h.acceptsApp(rl.APIAppID) {
+		return http.StatusOK, nil
+	}
+
+	h.logger.Warnw("The app was rate-limited by Slack",
+		zap.String("teamID", rl.TeamID),
+		zap.Int64("minute", rl.MinuteRateLimited))
+
+	reportRateLimited(context.Background(), rl.TeamID)
+
+	event, err := cloudEventFromRateLimited(rl)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	h.setAppEventType(event, rl.APIAppID)
+
+	return h.dispatch(h.sendContext(), event)
 }
 
-func cloudEventFromEventWrapper(wrapper *SlackEventWrapper) (*cloudevents.Event, error) {
+// dispatch sends the given event, or schedules its asynchronous delivery when
+// a delivery queue is set. The returned status code describes the error, if
+// any. Events rejected by a full queue are reported with the status code 503
+// (Service Unavailable), which causes Slack to retry them later.
+func (h *slackEventAPIHandler) dispatch(ctx context.Context, event *cloudevents.Event) (int, error) {
+	if h.queue != nil {
+		switch err := h.queue.Enqueue(ctx, *event); err {
+		case nil:
+			return http.StatusOK, nil
+		case delivery.ErrQueueFull, delivery.ErrQueueClosed:
+			return http.StatusServiceUnavailable, fmt.Errorf("could not accept Cloud Event: %w", err)
+		default:
+			return http.StatusInternalServerError, fmt.Errorf("could not accept Cloud Event: %w", err)
+		}
+	}
+
+	if result := h.ceClient.Send(ctx, *event); !cloudevents.IsACK(result) {
+		return http.StatusInternalServerError, fmt.Errorf("could not send Cloud Event: %w", result)
+	}
+
+	return http.StatusOK, nil
+}
+
+func cloudEventFromRateLimited(rl *SlackAppRateLimited) (*cloudevents.Event, error) {
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+
+	// Slack sends at most one notification per app and minute
+	event.SetID(fmt.Sprintf("%s-%s-%d", rl.APIAppID, rl.TeamID, rl.MinuteRateLimited))
+	event.SetType(v1alpha1.SlackAppRateLimitedEventType)
+	event.SetSource(rl.TeamID)
+	event.SetExtension(apiAppIdCeExtension, rl.APIAppID)
+	event.SetTime(time.Unix(rl.MinuteRateLimited, 0))
+	if err := event.SetData(cloudevents.ApplicationJSON, rl); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+func cloudEventFromEventWrapper(wrapper *SlackEventWrapper, specificType bool) (*cloudevents.Event, error) {
 	event := cloudevents.NewEvent(cloudevents.VersionV1)
 
 	event.SetID(wrapper.EventID)
-	event.SetType(v1alpha1.SlackGenericEventType)
+	if specificType && wrapper.Event.Type() != "" {
+		event.SetType(v1alpha1.SlackEventType(wrapper.Event.Type(), wrapper.Event.Subtype()))
+	} else {
+		event.SetType(v1alpha1.SlackGenericEventType)
+	}
 	event.SetSource(wrapper.TeamID)
 	event.SetExtension(apiAppIdCeExtension, wrapper.APIAppID)
 	event.SetTime(time.Unix(int64(wrapper.EventTime), 0))
@@ -17,16 +17,29 @@ limitations under the License.
 package slacksource
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	cloudeventst "github.com/cloudevents/sdk-go/v2/client/test"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/schemas"
 )
 
 func TestSlackEvent(t *testing.T) {
@@ -34,11 +47,12 @@ func TestSlackEvent(t *testing.T) {
 	logger := zapt.NewLogger(t).Sugar()
 
 	tc := map[string]struct {
-		body          io.Reader
-		headers       map[string]string
-		appID         string
-		signingSecret string
-		timewrap      timeWrap
+		body              io.Reader
+		headers           map[string]string
+		appID             string
+		signingSecret     string
+		additionalSecrets []string
+		timewrap          timeWrap
 
 		expectedCode     int
 		expectedContains string
@@ -222,6 +236,37 @@ func TestSlackEvent(t *testing.T) {
 			expectedCode: http.StatusOK,
 		},
 
+		"signed event ok with additional secret": {
+			body:              read(`{"token":"rryC9de5GMzbu8oA3qVZRcVY","team_id":"TA1J7JEBS","api_app_id":"A01624EULRY","event":{"client_msg_id":"ed372e63-845a-4981-9c3d-ecb8f64c6cef","type":"message","text":"<@U016RST62SU> asdfa","user":"UT8LFLXR8","ts":"1593192794.008000","team":"TA1J7JEBS","blocks":[{"type":"rich_text","block_id":"R5h","elements":[{"type":"rich_text_section","elements":[{"type":"user","user_id":"U016RST62SU"},{"type":"text","text":" asdfa"}]}]}],"channel":"C01112A09FT","event_ts":"1593192794.008000","channel_type":"channel"},"type":"event_callback","event_id":"Ev016HBFLLP3","event_time":1593192794,"authed_users":["U015MC994F9"]}`),
+			signingSecret:     "0000000000000000000000000000000a",
+			additionalSecrets: []string{"", "6623e5d64e469c64908c481b6de975f0"},
+			headers: map[string]string{
+				signatureHeader:          "v0=e270150d7eee7a9f176e056f4b647ea4f529e5b4946de36e82e29be6364a72a9",
+				signatureTimestampHeader: "1593192795",
+			},
+			timewrap: &mockedTime{
+				time.Unix(1593192796, 0),
+			},
+
+			expectedCode: http.StatusOK,
+		},
+
+		"signed event with unknown secret": {
+			body:              read(`{"token":"rryC9de5GMzbu8oA3qVZRcVY","team_id":"TA1J7JEBS","api_app_id":"A01624EULRY","event":{"client_msg_id":"ed372e63-845a-4981-9c3d-ecb8f64c6cef","type":"message","text":"<@U016RST62SU> asdfa","user":"UT8LFLXR8","ts":"1593192794.008000","team":"TA1J7JEBS","blocks":[{"type":"rich_text","block_id":"R5h","elements":[{"type":"rich_text_section","elements":[{"type":"user","user_id":"U016RST62SU"},{"type":"text","text":" asdfa"}]}]}],"channel":"C01112A09FT","event_ts":"1593192794.008000","channel_type":"channel"},"type":"event_callback","event_id":"Ev016HBFLLP3","event_time":1593192794,"authed_users":["U015MC994F9"]}`),
+			signingSecret:     "0000000000000000000000000000000a",
+			additionalSecrets: []string{"0000000000000000000000000000000b"},
+			headers: map[string]string{
+				signatureHeader:          "v0=e270150d7eee7a9f176e056f4b647ea4f529e5b4946de36e82e29be6364a72a9",
+				signatureTimestampHeader: "1593192795",
+			},
+			timewrap: &mockedTime{
+				time.Unix(1593192796, 0),
+			},
+
+			expectedCode:     http.StatusUnauthorized,
+			expectedContains: "received wrong signature signing hash",
+		},
+
 		"signed event tampered": {
 			body:          read(`{"TamPeREd":"yES","token":"rryC9de5GMzbu8oA3qVZRcVY","team_id":"TA1J7JEBS","api_app_id":"A01624EULRY","event":{"client_msg_id":"ed372e63-845a-4981-9c3d-ecb8f64c6cef","type":"message","text":"<@U016RST62SU> asdfa","user":"UT8LFLXR8","ts":"1593192794.008000","team":"TA1J7JEBS","blocks":[{"type":"rich_text","block_id":"R5h","elements":[{"type":"rich_text_section","elements":[{"type":"user","user_id":"U016RST62SU"},{"type":"text","text":" asdfa"}]}]}],"channel":"C01112A09FT","event_ts":"1593192794.008000","channel_type":"channel"},"type":"event_callback","event_id":"Ev016HBFLLP3","event_time":1593192794,"authed_users":["U015MC994F9"]}`),
 			signingSecret: "6623e5d64e469c64908c481b6de975f0",
@@ -248,11 +293,11 @@ func TestSlackEvent(t *testing.T) {
 			}
 
 			handler := &slackEventAPIHandler{
-				appID:         c.appID,
-				signingSecret: c.signingSecret,
-				ceClient:      ceClient,
-				logger:        logger,
-				time:          tw,
+				appID:          c.appID,
+				signingSecrets: credentials.New(c.signingSecret, c.additionalSecrets...),
+				ceClient:       ceClient,
+				logger:         logger,
+				time:           tw,
 			}
 
 			req, _ := http.NewRequest("GET", "/", c.body)
@@ -282,6 +327,221 @@ func TestSlackEvent(t *testing.T) {
 	}
 }
 
+func TestSlackDeduplication(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const body = `{
+		"team_id": "TXXXXXXXX",
+		"api_app_id": "AXXXXXXXXX",
+		"event": {"type": "name_of_event"},
+		"type": "event_callback",
+		"event_id": "Ev08MFMKH6",
+		"event_time": 1234567890
+	}`
+
+	tc := map[string]struct {
+		// whether the delivery of the first event fails
+		firstFails bool
+
+		expectedCodes []int
+		expectedSent  int
+	}{
+		"retry suppressed": {
+			expectedCodes: []int{http.StatusOK, http.StatusOK},
+			expectedSent:  1,
+		},
+		"retry of failed delivery": {
+			firstFails:    true,
+			expectedCodes: []int{http.StatusInternalServerError, http.StatusOK},
+			expectedSent:  2,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+			if c.firstFails {
+				ceClient.Send_AppendResult(cehttp.NewResult(http.StatusInternalServerError, ""))
+			}
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+				WithDeduplication(dedup.New(time.Minute)),
+			).(*slackEventAPIHandler)
+
+			for i, expectCode := range c.expectedCodes {
+				req, _ := http.NewRequest(http.MethodPost, "/", read(body))
+				if i > 0 {
+					req.Header.Set(headerSlackRetryNum, "1")
+				}
+
+				rr := httptest.NewRecorder()
+				handler.handleAll(rr, req)
+
+				assert.Equal(t, expectCode, rr.Code, "unexpected response code")
+			}
+
+			assert.Len(t, ceClient.Sent(), c.expectedSent)
+		})
+	}
+}
+
+func TestSlackDeduplicationConcurrent(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const body = `{"team_id":"TXXXXXXXX","api_app_id":"AXXXXXXXXX","event":{"type":"name_of_event"},` +
+		`"type":"event_callback","event_id":"Ev08MFMKH6","event_time":1234567890}`
+
+	// slow sink, so that retries are received while the original delivery
+	// is still being processed
+	ceClient := adaptertest.NewTestClientWithDelay(50 * time.Millisecond)
+
+	handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+		WithDeduplication(dedup.New(time.Minute)),
+	).(*slackEventAPIHandler)
+
+	const deliveries = 5
+
+	var wg sync.WaitGroup
+	for i := 0; i < deliveries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(body))
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, ceClient.Sent(), 1, "concurrent duplicates were not suppressed")
+}
+
+func TestSlackAsyncDelivery(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	callback := func(eventID string) string {
+		return `{"team_id":"TXXXXXXXX","api_app_id":"AXXXXXXXXX","event":{"type":"name_of_event"},` +
+			`"type":"event_callback","event_id":"` + eventID + `","event_time":1234567890}`
+	}
+
+	postCallback := func(h *slackEventAPIHandler, eventID string) int {
+		req, _ := http.NewRequest(http.MethodPost, "/", read(callback(eventID)))
+		rr := httptest.NewRecorder()
+		h.handleAll(rr, req)
+		return rr.Code
+	}
+
+	t.Run("events are acknowledged before delivery", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClientWithDelay(time.Second)
+
+		handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+			WithAsyncDelivery(delivery.Config{}),
+		).(*slackEventAPIHandler)
+		handler.queue.Start()
+
+		assert.Equal(t, http.StatusOK, postCallback(handler, "Ev08MFMKH6"), "unexpected response code")
+		assert.Empty(t, ceClient.Sent(), "event was sent before the acknowledgement")
+
+		require.NoError(t, handler.queue.Drain(context.Background()))
+		assert.Len(t, ceClient.Sent(), 1)
+	})
+
+	t.Run("full queue rejects events", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		// the queue is not started, so events accumulate in it
+		handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+			WithAsyncDelivery(delivery.Config{Size: 1}),
+		).(*slackEventAPIHandler)
+
+		assert.Equal(t, http.StatusOK, postCallback(handler, "Ev08MFMKH6"), "unexpected response code")
+		assert.Equal(t, http.StatusServiceUnavailable, postCallback(handler, "Ev08MFMKH7"), "unexpected response code")
+	})
+
+	t.Run("undelivered events are sent to the dead-letter sink", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+		ceClient.Send_AppendResult(cehttp.NewResult(http.StatusInternalServerError, ""))
+
+		handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+			WithAsyncDelivery(delivery.Config{DeadLetterSink: "http://dls.example.com"}),
+		).(*slackEventAPIHandler)
+		handler.queue.Start()
+
+		assert.Equal(t, http.StatusOK, postCallback(handler, "Ev08MFMKH6"), "unexpected response code")
+
+		require.NoError(t, handler.queue.Drain(context.Background()))
+		assert.Len(t, ceClient.Sent(), 2)
+	})
+}
+
+func TestSlackSchemaValidation(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	tc := map[string]struct {
+		body string
+
+		expectedCode int
+		expectedSent bool
+	}{
+		"valid event": {
+			body: `{
+				"token": "XXYYZZ",
+				"team_id": "TXXXXXXXX",
+				"api_app_id": "AXXXXXXXXX",
+				"event": {"type": "name_of_event", "event_ts": "1234567890.123456"},
+				"type": "event_callback",
+				"event_id": "Ev08MFMKH6",
+				"event_time": 1234567890
+			}`,
+			expectedCode: http.StatusOK,
+			expectedSent: true,
+		},
+		"event without timestamp": {
+			body: `{
+				"token": "XXYYZZ",
+				"team_id": "TXXXXXXXX",
+				"api_app_id": "AXXXXXXXXX",
+				"event": {"type": "name_of_event"},
+				"type": "event_callback",
+				"event_id": "Ev08MFMKH6",
+				"event_time": 1234567890
+			}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			v, err := schema.New(schema.Config{Schema: schemas.SlackEvents, URI: schemas.SlackEventsURI}, ceClient)
+			require.NoError(t, err)
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger,
+				WithValidator(v),
+			).(*slackEventAPIHandler)
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(c.body))
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, c.expectedCode, rr.Code, "unexpected response code")
+
+			sent := ceClient.Sent()
+			if !c.expectedSent {
+				assert.Empty(t, sent, "invalid event was sent")
+				return
+			}
+			require.Len(t, sent, 1)
+			assert.Equal(t, schemas.SlackEventsURI, sent[0].DataSchema())
+		})
+	}
+}
+
 type mockedTime struct {
 	t time.Time
 }
@@ -295,3 +555,162 @@ var _ timeWrap = (*mockedTime)(nil)
 func read(s string) io.Reader {
 	return strings.NewReader(s)
 }
+
+func TestCloudEventType(t *testing.T) {
+	tc := map[string]struct {
+		event        SlackEvent
+		specificType bool
+
+		expectedType string
+	}{
+		"generic type": {
+			event:        SlackEvent{"type": "app_mention"},
+			expectedType: "com.slack.events",
+		},
+		"specific type": {
+			event:        SlackEvent{"type": "app_mention"},
+			specificType: true,
+			expectedType: "com.slack.events.app_mention",
+		},
+		"specific type with subtype": {
+			event:        SlackEvent{"type": "message", "subtype": "channel_join"},
+			specificType: true,
+			expectedType: "com.slack.events.message.channel_join",
+		},
+		"specific type without event type": {
+			event:        SlackEvent{},
+			specificType: true,
+			expectedType: "com.slack.events",
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			wrapper := &SlackEventWrapper{
+				EventID: "Ev08MFMKH6",
+				Event:   c.event,
+			}
+
+			event, err := cloudEventFromEventWrapper(wrapper, c.specificType)
+			require.NoError(t, err)
+
+			assert.Equal(t, c.expectedType, event.Type())
+			assert.Equal(t, c.event.Type(), event.Subject())
+		})
+	}
+}
+
+func TestSlackRetries(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const body = `{
+		"team_id": "TXXXXXXXX",
+		"api_app_id": "AXXXXXXXXX",
+		"event": {"type": "app_mention"},
+		"type": "event_callback",
+		"event_id": "Ev08MFMKH6"
+	}`
+
+	tc := map[string]struct {
+		headers map[string]string
+
+		expectedExtensions map[string]interface{}
+	}{
+		"first delivery": {
+			expectedExtensions: map[string]interface{}{
+				apiAppIdCeExtension: "AXXXXXXXXX",
+			},
+		},
+		"retried delivery": {
+			headers: map[string]string{
+				headerSlackRetryNum:    "2",
+				headerSlackRetryReason: "http_timeout",
+			},
+			expectedExtensions: map[string]interface{}{
+				apiAppIdCeExtension: "AXXXXXXXXX",
+				ceExtRetryNum:       int32(2),
+				ceExtRetryReason:    "http_timeout",
+			},
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, "", standardTime{}, logger).(*slackEventAPIHandler)
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(body))
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+
+			sent := ceClient.Sent()
+			require.Len(t, sent, 1)
+			assert.Equal(t, c.expectedExtensions, sent[0].Extensions())
+		})
+	}
+}
+
+func TestSlackAppRateLimited(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const body = `{
+		"token": "Jhj5dZrVaK7ZwHHjRyZWjbDl",
+		"type": "app_rate_limited",
+		"team_id": "T123456",
+		"minute_rate_limited": 1518467820,
+		"api_app_id": "A123456"
+	}`
+
+	tc := map[string]struct {
+		appID string
+
+		expectSent bool
+	}{
+		"notification for this app": {
+			appID:      "A123456",
+			expectSent: true,
+		},
+		"notification for another app": {
+			appID: "A654321",
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			handler := NewSlackEventAPIHandler(ceClient, 0, nil, c.appID, standardTime{}, logger).(*slackEventAPIHandler)
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(body))
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+
+			sent := ceClient.Sent()
+			if !c.expectSent {
+				assert.Empty(t, sent, "unexpected event was sent")
+				return
+			}
+
+			require.Len(t, sent, 1)
+			event := sent[0]
+			assert.Equal(t, v1alpha1.SlackAppRateLimitedEventType, event.Type())
+			assert.Equal(t, "A123456-T123456-1518467820", event.ID())
+			assert.Equal(t, "T123456", event.Source())
+			assert.Equal(t, time.Unix(1518467820, 0).UTC(), event.Time().UTC())
+			assert.JSONEq(t, `{"type":"app_rate_limited","team_id":"T123456",`+
+				`"api_app_id":"A123456","minute_rate_limited":1518467820}`, string(event.Data()))
+		})
+	}
+}
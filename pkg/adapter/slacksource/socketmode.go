@@ -0,0 +1,318 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSlackAPIURL = "https://slack.com/api/"
+
+	// Types of Socket Mode envelopes.
+	// See: https://api.slack.com/apis/connections/socket-implement
+	envelopeHello         = "hello"
+	envelopeDisconnect    = "disconnect"
+	envelopeEventsAPI     = "events_api"
+	envelopeInteractive   = "interactive"
+	envelopeSlashCommands = "slash_commands"
+
+	// bounds of the delay between failed connection attempts
+	socketModeMinBackoff = time.Second
+	socketModeMaxBackoff = time.Minute
+)
+
+// socketModeEnvelope wraps the messages received over a Socket Mode
+// connection.
+type socketModeEnvelope struct {
+	EnvelopeID             string          `json:"envelope_id"`
+	Type                   string          `json:"type"`
+	Payload                json.RawMessage `json:"payload"`
+	AcceptsResponsePayload bool            `json:"accepts_response_payload"`
+	RetryAttempt           int             `json:"retry_attempt"`
+	RetryReason            string          `json:"retry_reason"`
+	// only set on disconnect messages
+	Reason string `json:"reason"`
+}
+
+// socketModeAck acknowledges the reception of an envelope.
+type socketModeAck struct {
+	EnvelopeID string          `json:"envelope_id"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// connectionsOpenResponse is the response of the apps.connections.open API
+// method.
+// See: https://api.slack.com/methods/apps.connections.open
+type connectionsOpenResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	URL   string `json:"url"`
+}
+
+// socketModeClient receives events over a Socket Mode connection and passes
+// them to the handler, the same way as events received over HTTP.
+type socketModeClient struct {
+	appToken string
+	apiURL   string
+
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+
+	handler *slackEventAPIHandler
+	logger  *zap.SugaredLogger
+}
+
+// newSocketModeClient returns a socketModeClient which authenticates using
+// the given app-level token.
+func newSocketModeClient(appToken string, h *slackEventAPIHandler) *socketModeClient {
+	return &socketModeClient{
+		appToken: appToken,
+		apiURL:   defaultSlackAPIURL,
+
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		dialer:     websocket.DefaultDialer,
+
+		handler: h,
+		logger:  h.logger.Named("socketmode"),
+	}
+}
+
+// run maintains a Socket Mode connection until the given context is
+// cancelled. Connections are re-opened when Slack requests it, and after
+// failures with an exponential backoff.
+func (c *socketModeClient) run(ctx context.Context) {
+	backoff := socketModeMinBackoff
+
+	for {
+		established, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if established {
+			backoff = socketModeMinBackoff
+		}
+		if err == nil {
+			continue
+		}
+
+		c.logger.Errorw("Socket Mode connection failed, reconnecting",
+			zap.Error(err), zap.Duration("backoff", backoff))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > socketModeMaxBackoff {
+			backoff = socketModeMaxBackoff
+		}
+	}
+}
+
+// connect opens a Socket Mode connection and handles the envelopes received
+// over it until Slack requests a disconnection, the connection fails or the
+// given context is cancelled. It returns whether the connection was
+// successfully established.
+func (c *socketModeClient) connect(ctx context.Context) (bool, error) {
+	wsURL, err := c.openConnection(ctx)
+	if err != nil {
+		return false, fmt.Errorf("requesting connection URL: %w", err)
+	}
+
+	conn, _, err := c.dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("opening WebSocket connection: %w", err)
+	}
+	defer conn.Close()
+
+	// unblocks reads when the context is cancelled
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	// acknowledgements are sent before the connection is closed
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// WebSocket connections support one concurrent writer
+	var writeMu sync.Mutex
+	ack := func(a *socketModeAck) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(a)
+	}
+
+	var established bool
+
+	for {
+		env := &socketModeEnvelope{}
+		if err := conn.ReadJSON(env); err != nil {
+			return established, fmt.Errorf("reading from WebSocket connection: %w", err)
+		}
+
+		switch env.Type {
+		case envelopeHello:
+			established = true
+			c.logger.Info("Socket Mode connection established")
+
+		case envelopeDisconnect:
+			// sent ahead of the periodic refresh of connections
+			c.logger.Infow("Slack requested a disconnection", zap.String("reason", env.Reason))
+			return established, nil
+
+		default:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.handleEnvelope(env, ack)
+			}()
+		}
+	}
+}
+
+// openConnection requests the URL of a new Socket Mode connection.
+func (c *socketModeClient) openConnection(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"apps.connections.open", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.appToken)
+	req.Header.Set("Content-Type", contentTypeForm)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	r := &connectionsOpenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(r); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if !r.OK {
+		return "", fmt.Errorf("slack API error: %s", r.Error)
+	}
+
+	return r.URL, nil
+}
+
+// handleEnvelope processes the payload of the given envelope and
+// acknowledges it. Envelopes which processing failed because of the sink are
+// not acknowledged, so that Slack retries their delivery.
+func (c *socketModeClient) handleEnvelope(env *socketModeEnvelope, ack func(*socketModeAck) error) {
+	reply, code, err := c.process(env)
+	if err != nil {
+		c.logger.Errorw("Failed to process envelope", zap.Error(err),
+			zap.String("type", env.Type), zap.String("envelopeID", env.EnvelopeID))
+
+		if code >= http.StatusInternalServerError {
+			return
+		}
+	}
+
+	a := &socketModeAck{EnvelopeID: env.EnvelopeID}
+	if env.AcceptsResponsePayload {
+		a.Payload = reply
+	}
+
+	if err := ack(a); err != nil {
+		c.logger.Errorw("Failed to acknowledge envelope", zap.Error(err),
+			zap.String("envelopeID", env.EnvelopeID))
+	}
+}
+
+// process passes the payload of the given envelope to the handler, and
+// returns the reply of the sink if any. The returned status code describes
+// the error, if any, as if the payload was received over HTTP.
+func (c *socketModeClient) process(env *socketModeEnvelope) ([]byte, int, error) {
+	switch env.Type {
+	case envelopeEventsAPI:
+		wrapper := &SlackEventWrapper{}
+		if err := json.Unmarshal(env.Payload, wrapper); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("could not unmarshall event payload: %w", err)
+		}
+		retry := deliveryRetry{num: env.RetryAttempt, reason: env.RetryReason}
+		code, err := c.handler.processCallback(wrapper, env.Payload, retry)
+		return nil, code, err
+
+	case envelopeInteractive:
+		event, appID, err := cloudEventFromInteraction(env.Payload)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return replyPayload(c.handler.processInteraction(event, appID))
+
+	case envelopeSlashCommands:
+		fields := make(map[string]string)
+		if err := json.Unmarshal(env.Payload, &fields); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("could not unmarshall slash command payload: %w", err)
+		}
+
+		form := make(url.Values, len(fields))
+		for k, v := range fields {
+			form.Set(k, v)
+		}
+
+		event, appID, err := cloudEventFromCommand(form)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return replyPayload(c.handler.processInteraction(event, appID))
+
+	default:
+		return nil, http.StatusBadRequest, fmt.Errorf("unsupported envelope type %q", env.Type)
+	}
+}
+
+// replyPayload returns the data of the given reply to an interaction, if any,
+// as the payload of an acknowledgement. Socket Mode only accepts JSON
+// payloads, so replies in other formats are rejected.
+func replyPayload(reply *cloudevents.Event, code int, err error) ([]byte, int, error) {
+	if err != nil || reply == nil {
+		return nil, code, err
+	}
+
+	if !isJSONContentType(reply.DataContentType()) {
+		return nil, http.StatusUnsupportedMediaType,
+			fmt.Errorf("reply has unsupported data content type %q", reply.DataContentType())
+	}
+
+	return reply.Data(), code, nil
+}
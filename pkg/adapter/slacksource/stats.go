@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"context"
+	"log"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"knative.dev/pkg/metrics"
+)
+
+var (
+	// rateLimitedCountM is a counter which records the number of
+	// notifications that the app exceeded the rate limit of the Events API.
+	rateLimitedCountM = stats.Int64(
+		"slack_app_rate_limited_count",
+		"Number of notifications that the app was rate-limited by Slack",
+		stats.UnitDimensionless,
+	)
+
+	// retryCountM is a counter which records the number of events which
+	// delivery was retried by Slack.
+	retryCountM = stats.Int64(
+		"slack_retry_count",
+		"Number of events which delivery was retried by Slack",
+		stats.UnitDimensionless,
+	)
+)
+
+var (
+	// teamKey is the tag which identifies the Slack workspace an event
+	// originates from.
+	teamKey = tag.MustNewKey("team_id")
+	// retryReasonKey is the tag which contains the reason why Slack retried
+	// the delivery of an event, e.g. "http_timeout".
+	retryReasonKey = tag.MustNewKey("retry_reason")
+)
+
+func init() {
+	register()
+}
+
+func register() {
+	err := metrics.RegisterResourceView(
+		&view.View{
+			Description: rateLimitedCountM.Description(),
+			Measure:     rateLimitedCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{teamKey},
+		},
+		&view.View{
+			Description: retryCountM.Description(),
+			Measure:     retryCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{retryReasonKey},
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %s", err)
+	}
+}
+
+// reportRateLimited captures a rate limit notification for the given Slack
+// workspace.
+func reportRateLimited(ctx context.Context, teamID string) {
+	metrics.Record(ctx, rateLimitedCountM.M(1), stats.WithTags(tag.Upsert(teamKey, teamID)))
+}
+
+// reportRetry captures an event which delivery was retried for the given
+// reason.
+func reportRetry(ctx context.Context, reason string) {
+	metrics.Record(ctx, retryCountM.M(1), stats.WithTags(tag.Upsert(retryReasonKey, reason)))
+}
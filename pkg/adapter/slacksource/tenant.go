@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"fmt"
+	"time"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/pkg/secret"
+)
+
+// tenantEnv returns the configuration of the handler serving the given source
+// in the multi-tenant adapter. The configuration is equivalent to the
+// environment the reconciler would propagate to a single-tenant adapter for
+// the same source, with values read from Secrets resolved.
+func tenantEnv(src *v1alpha1.SlackSource, secrGetter secret.Getter) (*envAccessor, error) {
+	spec := &src.Spec
+
+	// defaults match the ones declared on the fields of envAccessor
+	env := &envAccessor{
+		EnvConfig: adapter.EnvConfig{
+			Namespace: src.Namespace,
+			Name:      src.Name,
+		},
+
+		EventTypeMode:      string(v1alpha1.SlackEventTypeGeneric),
+		ResponseMode:       string(v1alpha1.SlackResponseAck),
+		EnrichmentCacheTTL: defaultEnrichmentCacheTTL,
+		DeduplicationTTL:   dedup.DefaultTTL,
+		AsyncQueueSize:     delivery.DefaultSize,
+		AsyncWorkers:       delivery.DefaultWorkers,
+		AsyncRetries:       delivery.DefaultRetries,
+		AsyncBackoffDelay:  delivery.DefaultBackoffDelay,
+	}
+
+	if appID := spec.AppID; appID != nil {
+		env.AppID = *appID
+	}
+
+	if signingSecret := spec.SigningSecret; signingSecret != nil {
+		secrets, err := secrGetter.Get(*signingSecret)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining signing secret: %w", err)
+		}
+		env.SigningSecret = secrets[0]
+	}
+
+	if refs := spec.AdditionalSigningSecrets; len(refs) > 0 {
+		secrets, err := secrGetter.Get(refs...)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining additional signing secrets: %w", err)
+		}
+		env.AdditionalSigningSecrets = secrets
+	}
+
+	for _, app := range spec.Apps {
+		secrets, err := secrGetter.Get(append([]v1alpha1.ValueFromField{app.SigningSecret},
+			app.AdditionalSigningSecrets...)...)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining signing secrets of app %q: %w", app.AppID, err)
+		}
+
+		a := &SlackApp{
+			ID:             app.AppID,
+			SigningSecrets: credentials.New(secrets[0], secrets[1:]...),
+		}
+		if p := app.EventTypePrefix; p != nil {
+			a.EventTypePrefix = *p
+		}
+		env.Apps = append(env.Apps, a)
+	}
+
+	if socketMode := spec.SocketMode; socketMode != nil {
+		secrets, err := secrGetter.Get(socketMode.AppToken)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining app-level token: %w", err)
+		}
+		env.AppToken = secrets[0]
+	}
+
+	if ipAllowlist := spec.IPAllowlist; ipAllowlist != nil {
+		env.AllowedCIDRs = ipAllowlist.AllowedCIDRs
+		env.TrustedProxyCIDRs = ipAllowlist.TrustedProxyCIDRs
+	}
+
+	lim := limiter.ConfigFromSpec(spec.RequestLimits)
+	env.EnvLimits = limiter.EnvLimits{
+		MaxBodySize:           lim.MaxBodySize,
+		MaxConcurrentRequests: lim.MaxConcurrentRequests,
+		RequestsPerSecond:     lim.RequestsPerSecond,
+		Burst:                 lim.Burst,
+		Key:                   string(lim.Key),
+		TrustedProxyCIDRs:     lim.TrustedProxyCIDRs,
+	}
+
+	if et := spec.EventTypes; et != nil && et.Mode != nil {
+		env.EventTypeMode = string(*et.Mode)
+	}
+
+	if inter := spec.Interactivity; inter != nil && inter.ResponseMode != nil {
+		env.ResponseMode = string(*inter.ResponseMode)
+	}
+
+	if filters := spec.Filters; filters != nil {
+		env.FilterEventTypes = filters.EventTypes
+		env.FilterExcludedSubtypes = filters.ExcludedSubtypes
+		env.FilterChannels = filters.Channels
+		env.FilterUsers = filters.Users
+		env.FilterExcludeBots = filters.ExcludeBots != nil && *filters.ExcludeBots
+		env.FilterExcludeSelf = filters.ExcludeSelf != nil && *filters.ExcludeSelf
+	}
+
+	if enrichment := spec.Enrichment; enrichment != nil {
+		secrets, err := secrGetter.Get(enrichment.BotToken)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining bot token: %w", err)
+		}
+		env.BotToken = secrets[0]
+
+		if ttl := enrichment.CacheTTL; ttl != nil {
+			env.EnrichmentCacheTTL = time.Duration(*ttl)
+		}
+		if apiURL := enrichment.APIURL; apiURL != nil {
+			env.EnrichmentAPIURL = apiURL.String()
+		}
+	}
+
+	if d := spec.Deduplication; d != nil {
+		env.Deduplication = true
+		if ttl := d.TTL; ttl != nil {
+			env.DeduplicationTTL = time.Duration(*ttl)
+		}
+	}
+
+	if async := spec.AsyncDelivery; async != nil {
+		env.AsyncDelivery = true
+		// the adapter runs one queue per source
+		env.AsyncQueueName = src.Namespace + "/" + src.Name
+		if qs := async.QueueSize; qs != nil {
+			env.AsyncQueueSize = int(*qs)
+		}
+		if w := async.Workers; w != nil {
+			env.AsyncWorkers = int(*w)
+		}
+		if r := async.Retries; r != nil {
+			env.AsyncRetries = int(*r)
+		}
+		if bd := async.BackoffDelay; bd != nil {
+			env.AsyncBackoffDelay = time.Duration(*bd)
+		}
+		if dls := async.DeadLetterSink; dls != nil {
+			env.AsyncDeadLetterSink = dls.String()
+		}
+	}
+
+	if sv := spec.SchemaValidation; sv != nil {
+		env.SchemaValidation = true
+		if sink := sv.ErrorSink; sink != nil {
+			env.SchemaErrorSink = sink.String()
+		}
+	}
+
+	return env, nil
+}
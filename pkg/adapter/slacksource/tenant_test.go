@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/pkg/apis"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	tmapis "github.com/triggermesh/knative-sources/pkg/apis"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestTenantEnv(t *testing.T) {
+	testCases := map[string]struct {
+		spec      func(*v1alpha1.SlackSourceSpec)
+		failSG    bool
+		expectEnv func(*envAccessor)
+		expectErr bool
+	}{
+		"Defaults": {
+			spec:      func(*v1alpha1.SlackSourceSpec) {},
+			expectEnv: func(*envAccessor) {},
+		},
+		"Signing secrets": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				appID := "AXXXXXXXXX"
+				s.AppID = &appID
+				s.SigningSecret = &v1alpha1.ValueFromField{Value: "secret"}
+				s.AdditionalSigningSecrets = []v1alpha1.ValueFromField{{Value: "old"}}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.AppID = "AXXXXXXXXX"
+				e.SigningSecret = "fake" // value returned by mockedSecretGetter
+				e.AdditionalSigningSecrets = []string{"fake"}
+			},
+		},
+		"Multiple apps": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				prefix := "com.example.helpdesk"
+				s.Apps = []v1alpha1.SlackApp{{
+					AppID:           tHelpdeskAppID,
+					SigningSecret:   v1alpha1.ValueFromField{Value: tHelpdeskSecret},
+					EventTypePrefix: &prefix,
+				}, {
+					AppID:                    tDeployAppID,
+					SigningSecret:            v1alpha1.ValueFromField{Value: tDeploySecret},
+					AdditionalSigningSecrets: []v1alpha1.ValueFromField{{Value: "old"}},
+				}}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.Apps = []*SlackApp{{
+					ID:              tHelpdeskAppID,
+					SigningSecrets:  credentials.New("fake"),
+					EventTypePrefix: "com.example.helpdesk",
+				}, {
+					ID:             tDeployAppID,
+					SigningSecrets: credentials.New("fake", "fake"),
+				}}
+			},
+		},
+		"Socket Mode": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				s.SocketMode = &v1alpha1.SlackSocketMode{
+					AppToken: v1alpha1.ValueFromField{Value: tAppToken},
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.AppToken = "fake"
+			},
+		},
+		"Filters": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				excludeBots := true
+				s.Filters = &v1alpha1.SlackEventFilters{
+					EventTypes:  []string{"message"},
+					Channels:    []string{"C2147483705"},
+					ExcludeBots: &excludeBots,
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.FilterEventTypes = []string{"message"}
+				e.FilterChannels = []string{"C2147483705"}
+				e.FilterExcludeBots = true
+			},
+		},
+		"Enrichment": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				ttl := tmapis.Duration(time.Minute)
+				s.Enrichment = &v1alpha1.SlackEnrichment{
+					BotToken: v1alpha1.ValueFromField{Value: tBotToken},
+					CacheTTL: &ttl,
+					APIURL:   &apis.URL{Scheme: "https", Host: "slack.example.com", Path: "/api/"},
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.BotToken = "fake"
+				e.EnrichmentCacheTTL = time.Minute
+				e.EnrichmentAPIURL = "https://slack.example.com/api/"
+			},
+		},
+		"Deduplication": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				ttl := tmapis.Duration(time.Minute)
+				s.Deduplication = &v1alpha1.SlackDeduplication{TTL: &ttl}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.Deduplication = true
+				e.DeduplicationTTL = time.Minute
+			},
+		},
+		"Asynchronous delivery": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				workers := int32(4)
+				s.AsyncDelivery = &v1alpha1.SlackAsyncDelivery{
+					Workers:        &workers,
+					DeadLetterSink: &apis.URL{Scheme: "http", Host: "dls.example.com"},
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.AsyncDelivery = true
+				e.AsyncWorkers = 4
+				e.AsyncDeadLetterSink = "http://dls.example.com"
+				e.AsyncQueueName = tNs + "/" + tName
+			},
+		},
+		"Event types and response modes": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				etMode := v1alpha1.SlackEventTypeSpecific
+				s.EventTypes = &v1alpha1.SlackEventTypes{Mode: &etMode}
+				respMode := v1alpha1.SlackResponseReply
+				s.Interactivity = &v1alpha1.SlackInteractivity{ResponseMode: &respMode}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.EventTypeMode = string(v1alpha1.SlackEventTypeSpecific)
+				e.ResponseMode = string(v1alpha1.SlackResponseReply)
+			},
+		},
+		"Unreadable secret": {
+			spec: func(s *v1alpha1.SlackSourceSpec) {
+				s.SigningSecret = &v1alpha1.ValueFromField{Value: "secret"}
+			},
+			failSG:    true,
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			src := newEventSource()
+			tc.spec(&src.Spec)
+
+			env, err := tenantEnv(src, &mockedSecretGetter{fail: tc.failSG})
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			lim := limiter.ConfigFromSpec(nil)
+
+			expectEnv := &envAccessor{
+				EnvConfig: adapter.EnvConfig{
+					Namespace: tNs,
+					Name:      tName,
+				},
+				EnvLimits: limiter.EnvLimits{
+					MaxBodySize:           lim.MaxBodySize,
+					MaxConcurrentRequests: lim.MaxConcurrentRequests,
+					RequestsPerSecond:     lim.RequestsPerSecond,
+					Burst:                 lim.Burst,
+					Key:                   string(lim.Key),
+				},
+				EventTypeMode:      string(v1alpha1.SlackEventTypeGeneric),
+				ResponseMode:       string(v1alpha1.SlackResponseAck),
+				EnrichmentCacheTTL: time.Hour,
+				DeduplicationTTL:   dedup.DefaultTTL,
+				AsyncQueueSize:     delivery.DefaultSize,
+				AsyncWorkers:       delivery.DefaultWorkers,
+				AsyncRetries:       delivery.DefaultRetries,
+				AsyncBackoffDelay:  delivery.DefaultBackoffDelay,
+			}
+			tc.expectEnv(expectEnv)
+
+			assert.Equal(t, expectEnv, env)
+		})
+	}
+}
@@ -75,3 +75,13 @@ func (l *Listers) GetSourcesObjects() []runtime.Object {
 func (l *Listers) GetZendeskSourceLister() listersv1alpha1.ZendeskSourceLister {
 	return listersv1alpha1.NewZendeskSourceLister(l.IndexerFor(&v1alpha1.ZendeskSource{}))
 }
+
+// GetWebhookSourceLister returns a Lister for WebhookSource objects.
+func (l *Listers) GetWebhookSourceLister() listersv1alpha1.WebhookSourceLister {
+	return listersv1alpha1.NewWebhookSourceLister(l.IndexerFor(&v1alpha1.WebhookSource{}))
+}
+
+// GetSlackSourceLister returns a Lister for SlackSource objects.
+func (l *Listers) GetSlackSourceLister() listersv1alpha1.SlackSourceLister {
+	return listersv1alpha1.NewSlackSourceLister(l.IndexerFor(&v1alpha1.SlackSource{}))
+}
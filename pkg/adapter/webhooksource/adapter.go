@@ -18,26 +18,124 @@ package webhooksource
 
 import (
 	"context"
+	"fmt"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
 
 	"knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
 // NewAdapter implementation
 func NewAdapter(ctx context.Context, aEnv adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
 	env := aEnv.(*envAccessor)
+	logger := logging.FromContext(ctx)
+
+	env.BasicAuthAdditionalPasswords = credentials.FromEnv(envBasicAuthAdditionalPasswords)
+
+	h, err := newHandler(env, ceClient, logger)
+	if err != nil {
+		logger.Panicw("Invalid webhook settings", zap.Error(err))
+	}
+
+	return h
+}
+
+// newHandler returns a webhookHandler configured from the given environment.
+func newHandler(env *envAccessor, ceClient cloudevents.Client, logger *zap.SugaredLogger) (*webhookHandler, error) {
+	jwtAuth, err := newJWTValidator(env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT authentication settings: %w", err)
+	}
+
+	clientCert, err := newClientCertValidator(env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate authentication settings: %w", err)
+	}
+
+	var ipFilter *ipfilter.Filter
+	if len(env.AllowedCIDRs) > 0 {
+		if ipFilter, err = ipfilter.New(env.AllowedCIDRs, env.TrustedProxyCIDRs); err != nil {
+			return nil, fmt.Errorf("invalid IP allowlist: %w", err)
+		}
+		ipFilter = ipFilter.WithOwner(env.Namespace, env.Name)
+	}
+
+	var lim *limiter.Limiter
+	if cfg := env.EnvLimits.Config(); cfg.Enabled() {
+		if lim, err = limiter.New(cfg); err != nil {
+			return nil, fmt.Errorf("invalid request limits: %w", err)
+		}
+	}
+
+	var bodyDecoder *bodyDecoder
+	if env.BodyDecoding {
+		if bodyDecoder, err = newBodyDecoder(env.MultipartFiles); err != nil {
+			return nil, fmt.Errorf("invalid body decoding settings: %w", err)
+		}
+	}
+
+	var batchSplitter *batchSplitter
+	if env.BatchSplitting {
+		if batchSplitter, err = newBatchSplitter(env.BatchPath, env.BatchIDField, env.BatchPartialFailure); err != nil {
+			return nil, fmt.Errorf("invalid batch splitting settings: %w", err)
+		}
+	}
+
+	var validator *schema.Validator
+	if cfg, ok := env.EnvSchema.Config("", ""); ok {
+		if validator, err = schema.New(cfg, ceClient); err != nil {
+			return nil, fmt.Errorf("invalid schema validation settings: %w", err)
+		}
+	}
+
+	resp, err := newResponse(env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response settings: %w", err)
+	}
+
+	var queue *delivery.Queue
+	if env.AsyncDelivery {
+		queue = delivery.New(ceClient, delivery.Config{
+			Size:         env.AsyncQueueSize,
+			Retries:      env.AsyncRetries,
+			BackoffDelay: env.AsyncBackoffDelay,
+			Name:         env.AsyncQueueName,
+		}, logger.Named("delivery"))
+	}
+
+	passwords := credentials.New(env.BasicAuthPassword, env.BasicAuthAdditionalPasswords...).
+		WithOwner(env.Namespace, env.Name, "")
 
 	return &webhookHandler{
 		eventType:   env.EventType,
 		eventSource: env.EventSource,
 
-		username: env.BasicAuthUsername,
-		password: env.BasicAuthPassword,
+		username:   env.BasicAuthUsername,
+		passwords:  passwords,
+		jwtAuth:    jwtAuth,
+		clientCert: clientCert,
+		ipFilter:   ipFilter,
+		limiter:    lim,
+		cors:       newCORS(env),
+
+		bodyDecoder:   bodyDecoder,
+		batchSplitter: batchSplitter,
+		idempotency:   newIdempotency(env),
+		validator:     validator,
+		response:      resp,
+
 		ceClient: ceClient,
-		logger:   logging.FromContext(ctx),
-	}
+		queue:    queue,
+		logger:   logger,
+	}, nil
 }
 
 var _ adapter.Adapter = (*webhookHandler)(nil)
@@ -0,0 +1,310 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+)
+
+const (
+	defaultClientCertHeader = "X-Forwarded-Client-Cert"
+
+	// CloudEvents extension containing the subject of the client
+	// certificate
+	ceExtClientCertSubject = "clientcertsubject"
+)
+
+// clientCertValidator validates the TLS client certificates presented by HTTP
+// clients and forwarded by an ingress.
+type clientCertValidator struct {
+	roots *x509.CertPool
+
+	// name of the header containing forwarded client certificates
+	header string
+	// proxies which are trusted to set the header
+	proxies *ipfilter.Filter
+
+	allowedSubjects []string
+	allowedSANs     []string
+
+	// allows mocking the current time in tests
+	now func() time.Time
+}
+
+// newClientCertValidator returns a clientCertValidator configured from the
+// given environment, or nil if client certificate authentication is not
+// enabled.
+func newClientCertValidator(env *envAccessor) (*clientCertValidator, error) {
+	if env.ClientCertCABundle == "" {
+		return nil, nil
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(env.ClientCertCABundle)) {
+		return nil, errors.New("CA bundle contains no PEM-encoded certificate")
+	}
+
+	// without trusted proxies, any HTTP client could forge the header
+	if len(env.ClientCertTrustedProxyCIDRs) == 0 {
+		return nil, errors.New("at least one trusted proxy IP range is required")
+	}
+	proxies, err := ipfilter.New(nil, env.ClientCertTrustedProxyCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	header := env.ClientCertHeader
+	if header == "" {
+		header = defaultClientCertHeader
+	}
+
+	return &clientCertValidator{
+		roots:           roots,
+		header:          header,
+		proxies:         proxies,
+		allowedSubjects: env.ClientCertAllowedSubjects,
+		allowedSANs:     env.ClientCertAllowedSANs,
+		now:             time.Now,
+	}, nil
+}
+
+// validate verifies the client certificate presented with the given request
+// and returns it.
+func (v *clientCertValidator) validate(r *http.Request) (*x509.Certificate, error) {
+	chain, err := v.peerCertificates(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("missing client certificate")
+	}
+
+	cert := chain[0]
+
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   v.now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		return nil, fmt.Errorf("verifying certificate: %w", err)
+	}
+
+	if !v.hasAllowedSubject(cert) {
+		return nil, fmt.Errorf("certificate subject %q is not allowed", cert.Subject.String())
+	}
+	if !v.hasAllowedSAN(cert) {
+		return nil, errors.New("certificate contains no allowed subject alternative name")
+	}
+
+	return cert, nil
+}
+
+// peerCertificates returns the chain of certificates presented by the client,
+// starting with the client's own certificate. The header containing the
+// certificates is ignored unless the request was received from a trusted
+// proxy.
+func (v *clientCertValidator) peerCertificates(r *http.Request) ([]*x509.Certificate, error) {
+	if !v.proxies.FromTrustedProxy(r) {
+		return nil, nil
+	}
+
+	hdr := r.Header.Get(v.header)
+	if hdr == "" {
+		return nil, nil
+	}
+
+	chain, err := parseForwardedCert(hdr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing forwarded certificate: %w", err)
+	}
+	return chain, nil
+}
+
+// hasAllowedSubject returns whether the distinguished name of the given
+// certificate's subject is accepted. Any subject is accepted if none was
+// configured.
+// The subject is compared in the same format as the one propagated by
+// setExtensions.
+func (v *clientCertValidator) hasAllowedSubject(cert *x509.Certificate) bool {
+	if len(v.allowedSubjects) == 0 {
+		return true
+	}
+
+	subject := cert.Subject.String()
+	for _, s := range v.allowedSubjects {
+		if s == subject {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllowedSAN returns whether the given certificate contains at least one
+// accepted Subject Alternative Name. Any certificate is accepted if no name
+// was configured.
+func (v *clientCertValidator) hasAllowedSAN(cert *x509.Certificate) bool {
+	if len(v.allowedSANs) == 0 {
+		return true
+	}
+
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.URIs)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	for _, allowed := range v.allowedSANs {
+		for _, san := range sans {
+			if allowed == san {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setExtensions propagates the subject of the given client certificate to
+// the given event as a CloudEvents extension.
+func (v *clientCertValidator) setExtensions(event *cloudevents.Event, cert *x509.Certificate) {
+	event.SetExtension(ceExtClientCertSubject, cert.Subject.String())
+}
+
+// parseForwardedCert parses the chain of certificates contained in the value
+// of a header set by an ingress. Supported formats are Envoy's
+// X-Forwarded-Client-Cert, and URL-encoded PEM certificates such as NGINX's
+// $ssl_client_escaped_cert.
+func parseForwardedCert(hdr string) ([]*x509.Certificate, error) {
+	if isXFCC(hdr) {
+		hdr = xfccCertificate(hdr)
+		if hdr == "" {
+			return nil, errors.New("no certificate in header value")
+		}
+	}
+
+	// PathUnescape preserves '+' characters from the base64 alphabet
+	data, err := url.PathUnescape(hdr)
+	if err != nil {
+		return nil, fmt.Errorf("decoding header value: %w", err)
+	}
+
+	var chain []*x509.Certificate
+
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("no PEM-encoded certificate in header value")
+	}
+
+	return chain, nil
+}
+
+// isXFCC returns whether the given header value is in the format of Envoy's
+// X-Forwarded-Client-Cert header.
+func isXFCC(hdr string) bool {
+	return strings.Contains(hdr, "Cert=") || strings.Contains(hdr, "Chain=")
+}
+
+// xfccCertificate returns the (still URL-encoded) certificate chain contained
+// in the first element of an X-Forwarded-Client-Cert header value. The Chain
+// field is preferred over the Cert field, which only contains the client's
+// own certificate.
+// https://www.envoyproxy.io/docs/envoy/latest/configuration/http/http_conn_man/headers#x-forwarded-client-cert
+func xfccCertificate(hdr string) string {
+	elem := splitUnquoted(hdr, ',')[0]
+
+	var cert, chain string
+
+	for _, kv := range splitUnquoted(elem, ';') {
+		kv := strings.SplitN(kv, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		val := strings.Trim(kv[1], `"`)
+
+		switch strings.TrimSpace(kv[0]) {
+		case "Cert":
+			cert = val
+		case "Chain":
+			chain = val
+		}
+	}
+
+	if chain != "" {
+		return chain
+	}
+	return cert
+}
+
+// splitUnquoted slices s into all substrings separated by sep, ignoring
+// separators which are enclosed in double quotes, such as in the Subject
+// field of an X-Forwarded-Client-Cert header value.
+func splitUnquoted(s string, sep rune) []string {
+	var parts []string
+
+	var quoted bool
+	var start int
+
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
@@ -0,0 +1,364 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+)
+
+const (
+	tClientCN      = "partner.example.com"
+	tClientSubject = "CN=" + tClientCN + ",O=Partner"
+
+	// address of the ingress which forwards client certificates
+	tProxyAddr = "10.0.0.1:51234"
+)
+
+func TestClientCertValidate(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	otherCA := newTestCA(t, "Other CA")
+
+	clientCert := ca.issue(t, tClientCN, x509.ExtKeyUsageClientAuth)
+	otherClientCert := otherCA.issue(t, tClientCN, x509.ExtKeyUsageClientAuth)
+	serverCert := ca.issue(t, tClientCN, x509.ExtKeyUsageServerAuth)
+
+	escapedPEM := url.PathEscape(string(encodePEM(clientCert)))
+
+	tc := map[string]struct {
+		header          string
+		remoteAddr      string
+		allowedSubjects []string
+		allowedSANs     []string
+
+		expectErr string
+	}{
+		"URL-encoded PEM": {
+			header: escapedPEM,
+		},
+		"XFCC with Cert field": {
+			header: `By=spiffe://cluster.local/ns/default/sa/webhook;Hash=abc;` +
+				`Subject="CN=` + tClientCN + `,O=Partner";Cert="` + escapedPEM + `"`,
+		},
+		"XFCC with Chain field": {
+			header: `Hash=abc;Chain="` + url.PathEscape(string(encodePEM(clientCert, ca.cert))) + `",` +
+				`By=spiffe://cluster.local/ns/default/sa/proxy;Hash=def`,
+		},
+		"header from untrusted peer": {
+			header:     escapedPEM,
+			remoteAddr: "192.0.2.10:51234",
+			expectErr:  "missing client certificate",
+		},
+		"allowed subject and SAN": {
+			header:          escapedPEM,
+			allowedSubjects: []string{"CN=other.example.com", tClientSubject},
+			allowedSANs:     []string{"spiffe://example.com/partner"},
+		},
+		"missing certificate": {
+			expectErr: "missing client certificate",
+		},
+		"malformed header": {
+			header:    "not a certificate",
+			expectErr: "no PEM-encoded certificate in header value",
+		},
+		"untrusted authority": {
+			header:    url.PathEscape(string(encodePEM(otherClientCert))),
+			expectErr: "verifying certificate",
+		},
+		"not a client certificate": {
+			header:    url.PathEscape(string(encodePEM(serverCert))),
+			expectErr: "verifying certificate",
+		},
+		"disallowed subject": {
+			header:          escapedPEM,
+			allowedSubjects: []string{"CN=other.example.com"},
+			expectErr:       `certificate subject "` + tClientSubject + `" is not allowed`,
+		},
+		"subject matching only the Common Name": {
+			header:          escapedPEM,
+			allowedSubjects: []string{tClientCN},
+			expectErr:       `certificate subject "` + tClientSubject + `" is not allowed`,
+		},
+		"disallowed SAN": {
+			header:      escapedPEM,
+			allowedSANs: []string{"other.example.com"},
+			expectErr:   "certificate contains no allowed subject alternative name",
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			v := &clientCertValidator{
+				roots:           ca.pool(),
+				header:          defaultClientCertHeader,
+				proxies:         newTestProxies(t),
+				allowedSubjects: c.allowedSubjects,
+				allowedSANs:     c.allowedSANs,
+				now:             time.Now,
+			}
+
+			req, err := http.NewRequest(http.MethodPost, "/", nil)
+			require.NoError(t, err)
+
+			req.RemoteAddr = tProxyAddr
+			if c.remoteAddr != "" {
+				req.RemoteAddr = c.remoteAddr
+			}
+			if c.header != "" {
+				req.Header.Set(defaultClientCertHeader, c.header)
+			}
+
+			cert, err := v.validate(req)
+
+			if c.expectErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tClientCN, cert.Subject.CommonName)
+		})
+	}
+}
+
+func TestNewClientCertValidator(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	caBundle := string(encodePEM(ca.cert))
+
+	tc := map[string]struct {
+		env *envAccessor
+
+		expectNil    bool
+		expectHeader string
+		expectErr    string
+	}{
+		"disabled": {
+			env:       &envAccessor{},
+			expectNil: true,
+		},
+		"default header": {
+			env: &envAccessor{
+				ClientCertCABundle:          caBundle,
+				ClientCertTrustedProxyCIDRs: []string{"10.0.0.0/8"},
+			},
+			expectHeader: defaultClientCertHeader,
+		},
+		"custom header": {
+			env: &envAccessor{
+				ClientCertCABundle:          caBundle,
+				ClientCertHeader:            "Ssl-Client-Cert",
+				ClientCertTrustedProxyCIDRs: []string{"10.0.0.0/8"},
+			},
+			expectHeader: "Ssl-Client-Cert",
+		},
+		"invalid CA bundle": {
+			env: &envAccessor{
+				ClientCertCABundle:          "not a certificate",
+				ClientCertTrustedProxyCIDRs: []string{"10.0.0.0/8"},
+			},
+			expectErr: "CA bundle contains no PEM-encoded certificate",
+		},
+		"missing trusted proxies": {
+			env: &envAccessor{
+				ClientCertCABundle: caBundle,
+			},
+			expectErr: "at least one trusted proxy IP range is required",
+		},
+		"invalid trusted proxies": {
+			env: &envAccessor{
+				ClientCertCABundle:          caBundle,
+				ClientCertTrustedProxyCIDRs: []string{"10.0.0.0"},
+			},
+			expectErr: "parsing trusted proxies IP ranges",
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			v, err := newClientCertValidator(c.env)
+
+			if c.expectErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if c.expectNil {
+				assert.Nil(t, v)
+				return
+			}
+
+			assert.Equal(t, c.expectHeader, v.header)
+		})
+	}
+}
+
+func TestWebhookClientCertAuth(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	ca := newTestCA(t, "Test CA")
+	clientCert := ca.issue(t, tClientCN, x509.ExtKeyUsageClientAuth)
+
+	ceClient := adaptertest.NewTestClient()
+
+	handler := &webhookHandler{
+		eventType:   tEventType,
+		eventSource: tEventSource,
+		clientCert: &clientCertValidator{
+			roots:   ca.pool(),
+			header:  defaultClientCertHeader,
+			proxies: newTestProxies(t),
+			now:     time.Now,
+		},
+
+		ceClient: ceClient,
+		logger:   logger,
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/", read("arbitrary message"))
+	rr := httptest.NewRecorder()
+	handler.handleAll(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code, "unexpected response code")
+	assert.Empty(t, ceClient.Sent(), "event was sent without client certificate")
+
+	req, _ = http.NewRequest(http.MethodPost, "/", read("arbitrary message"))
+	req.RemoteAddr = tProxyAddr
+	req.Header.Set(defaultClientCertHeader, url.PathEscape(string(encodePEM(clientCert))))
+	rr = httptest.NewRecorder()
+	handler.handleAll(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+
+	sent := ceClient.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, tClientSubject, sent[0].Extensions()[ceExtClientCertSubject])
+}
+
+// testCA is a certificate authority which issues certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, cn string) *testCA {
+	t.Helper()
+
+	key := newECDSAKey(t)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	return &testCA{
+		cert: createCert(t, tmpl, tmpl, key, key),
+		key:  key,
+	}
+}
+
+// issue returns a certificate signed by the CA for the given Common Name and
+// extended key usage.
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) *x509.Certificate {
+	t.Helper()
+
+	spiffeID, err := url.Parse("spiffe://example.com/partner")
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"Partner"}},
+		DNSNames:     []string{cn},
+		URIs:         []*url.URL{spiffeID},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	return createCert(t, tmpl, ca.cert, newECDSAKey(t), ca.key)
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	p := x509.NewCertPool()
+	p.AddCert(ca.cert)
+	return p
+}
+
+// newTestProxies returns a filter which trusts the proxy at tProxyAddr.
+func newTestProxies(t *testing.T) *ipfilter.Filter {
+	t.Helper()
+
+	f, err := ipfilter.New(nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	return f
+}
+
+func newECDSAKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func createCert(t *testing.T, tmpl, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func encodePEM(certs ...*x509.Certificate) []byte {
+	var data []byte
+	for _, c := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})...)
+	}
+	return data
+}
@@ -0,0 +1,205 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS headers.
+// https://fetch.spec.whatwg.org/#http-cors-protocol
+const (
+	headerOrigin         = "Origin"
+	headerRequestMethod  = "Access-Control-Request-Method"
+	headerRequestHeaders = "Access-Control-Request-Headers"
+	headerAllowOrigin    = "Access-Control-Allow-Origin"
+	headerAllowMethods   = "Access-Control-Allow-Methods"
+	headerAllowHeaders   = "Access-Control-Allow-Headers"
+	headerMaxAge         = "Access-Control-Max-Age"
+	headerVary           = "Vary"
+)
+
+// matches any origin or header
+const corsWildcard = "*"
+
+var (
+	defaultCORSAllowedMethods = []string{http.MethodGet, http.MethodPost}
+	defaultCORSAllowedHeaders = []string{"Content-Type"}
+)
+
+// cors handles the Cross-Origin Resource Sharing (CORS) protocol, which allows
+// browsers to send requests to the webhook from web pages served from other
+// origins.
+type cors struct {
+	allowedOrigins []string
+	// values are canonicalized, for case-insensitive comparisons
+	allowedMethods []string
+	allowedHeaders []string
+	// zero if browsers should apply their own default
+	maxAge time.Duration
+}
+
+// newCORS returns a cors configured from the given environment, or nil if
+// CORS is not enabled.
+func newCORS(env *envAccessor) *cors {
+	if len(env.CORSAllowedOrigins) == 0 {
+		return nil
+	}
+
+	methods := defaultCORSAllowedMethods
+	if len(env.CORSAllowedMethods) > 0 {
+		methods = make([]string, len(env.CORSAllowedMethods))
+		for i, m := range env.CORSAllowedMethods {
+			methods[i] = strings.ToUpper(m)
+		}
+	}
+
+	headers := defaultCORSAllowedHeaders
+	if len(env.CORSAllowedHeaders) > 0 {
+		headers = make([]string, len(env.CORSAllowedHeaders))
+		for i, h := range env.CORSAllowedHeaders {
+			headers[i] = http.CanonicalHeaderKey(h)
+		}
+	}
+
+	return &cors{
+		allowedOrigins: env.CORSAllowedOrigins,
+		allowedMethods: methods,
+		allowedHeaders: headers,
+		maxAge:         env.CORSMaxAge,
+	}
+}
+
+// Handler returns a http.Handler which answers CORS preflight requests, and
+// sets CORS headers on responses to requests from allowed origins before
+// passing them to the given handler.
+func (c *cors) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get(headerOrigin)
+
+		// responses depend on the origin, caches must not share them
+		// between origins
+		w.Header().Add(headerVary, headerOrigin)
+
+		if isPreflight(r) {
+			c.handlePreflight(w, r, origin)
+			return
+		}
+
+		if origin != "" && c.isAllowedOrigin(origin) {
+			w.Header().Set(headerAllowOrigin, c.allowOriginValue(origin))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handlePreflight responds to a CORS preflight request. Preflight requests
+// which don't satisfy the CORS configuration are rejected.
+func (c *cors) handlePreflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add(headerVary, headerRequestMethod)
+	w.Header().Add(headerVary, headerRequestHeaders)
+
+	if !c.isAllowedOrigin(origin) ||
+		!c.isAllowedMethod(r.Header.Get(headerRequestMethod)) ||
+		!c.areAllowedHeaders(r.Header.Get(headerRequestHeaders)) {
+
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set(headerAllowOrigin, c.allowOriginValue(origin))
+	w.Header().Set(headerAllowMethods, strings.Join(c.allowedMethods, ", "))
+	w.Header().Set(headerAllowHeaders, strings.Join(c.allowedHeaders, ", "))
+	if c.maxAge > 0 {
+		w.Header().Set(headerMaxAge, strconv.Itoa(int(c.maxAge.Seconds())))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// isAllowedOrigin returns whether requests from the given origin are allowed.
+func (c *cors) isAllowedOrigin(origin string) bool {
+	for _, o := range c.allowedOrigins {
+		if o == corsWildcard || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// allowOriginValue returns the value of the Access-Control-Allow-Origin header
+// for requests from the given allowed origin.
+func (c *cors) allowOriginValue(origin string) string {
+	for _, o := range c.allowedOrigins {
+		if o == corsWildcard {
+			return corsWildcard
+		}
+	}
+	return origin
+}
+
+// isAllowedMethod returns whether the given method is allowed.
+func (c *cors) isAllowedMethod(method string) bool {
+	method = strings.ToUpper(method)
+
+	for _, m := range c.allowedMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// areAllowedHeaders returns whether all the headers in the given
+// comma-separated list are allowed.
+func (c *cors) areAllowedHeaders(headers string) bool {
+	if headers == "" {
+		return true
+	}
+
+	for _, h := range strings.Split(headers, ",") {
+		h = http.CanonicalHeaderKey(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+		if !c.isAllowedHeader(h) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAllowedHeader returns whether the given canonical header name is allowed.
+func (c *cors) isAllowedHeader(header string) bool {
+	for _, h := range c.allowedHeaders {
+		if h == corsWildcard || h == header {
+			return true
+		}
+	}
+	return false
+}
+
+// isPreflight returns whether the given request is a CORS preflight request.
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get(headerOrigin) != "" &&
+		r.Header.Get(headerRequestMethod) != ""
+}
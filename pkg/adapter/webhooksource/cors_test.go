@@ -0,0 +1,199 @@
+/*
+Copyright (c) 2020 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+)
+
+const tOrigin = "https://app.example.com"
+
+func TestCORS(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	tc := map[string]struct {
+		env     *envAccessor
+		method  string
+		headers map[string]string
+
+		expectCode    int
+		expectHeaders map[string]string
+		expectSent    bool
+	}{
+		"preflight request from allowed origin": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{"https://other.example.com", tOrigin},
+				CORSMaxAge:         10 * time.Minute,
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				headerOrigin:         tOrigin,
+				headerRequestMethod:  http.MethodPost,
+				headerRequestHeaders: "content-type",
+			},
+			expectCode: http.StatusNoContent,
+			expectHeaders: map[string]string{
+				headerAllowOrigin:  tOrigin,
+				headerAllowMethods: "GET, POST",
+				headerAllowHeaders: "Content-Type",
+				headerMaxAge:       "600",
+			},
+		},
+		"preflight request with wildcards": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{"*"},
+				CORSAllowedMethods: []string{"put"},
+				CORSAllowedHeaders: []string{"*"},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				headerOrigin:         tOrigin,
+				headerRequestMethod:  http.MethodPut,
+				headerRequestHeaders: "Content-Type, X-Custom",
+			},
+			expectCode: http.StatusNoContent,
+			expectHeaders: map[string]string{
+				headerAllowOrigin:  "*",
+				headerAllowMethods: "PUT",
+				headerAllowHeaders: "*",
+				headerMaxAge:       "",
+			},
+		},
+		"preflight request from disallowed origin": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{"https://other.example.com"},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				headerOrigin:        tOrigin,
+				headerRequestMethod: http.MethodPost,
+			},
+			expectCode: http.StatusForbidden,
+			expectHeaders: map[string]string{
+				headerAllowOrigin: "",
+			},
+		},
+		"preflight request with disallowed method": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{tOrigin},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				headerOrigin:        tOrigin,
+				headerRequestMethod: http.MethodDelete,
+			},
+			expectCode: http.StatusForbidden,
+		},
+		"preflight request with disallowed header": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{tOrigin},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				headerOrigin:         tOrigin,
+				headerRequestMethod:  http.MethodPost,
+				headerRequestHeaders: "Content-Type, X-Custom",
+			},
+			expectCode: http.StatusForbidden,
+		},
+		"request from allowed origin": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{tOrigin},
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				headerOrigin: tOrigin,
+			},
+			expectCode: http.StatusOK,
+			expectHeaders: map[string]string{
+				headerAllowOrigin: tOrigin,
+				headerVary:        headerOrigin,
+			},
+			expectSent: true,
+		},
+		"request from disallowed origin": {
+			env: &envAccessor{
+				CORSAllowedOrigins: []string{"https://other.example.com"},
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				headerOrigin: tOrigin,
+			},
+			expectCode: http.StatusOK,
+			expectHeaders: map[string]string{
+				headerAllowOrigin: "",
+			},
+			// the browser hides the response, but the event was sent
+			expectSent: true,
+		},
+		"OPTIONS request without CORS": {
+			env:    &envAccessor{},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				headerOrigin:        tOrigin,
+				headerRequestMethod: http.MethodPost,
+			},
+			expectCode: http.StatusNoContent,
+			expectHeaders: map[string]string{
+				headerAllowOrigin: "",
+			},
+		},
+	}
+
+	for name, c := range tc {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			handler := &webhookHandler{
+				eventType:   tEventType,
+				eventSource: tEventSource,
+				cors:        newCORS(c.env),
+
+				ceClient: ceClient,
+				logger:   logger,
+			}
+
+			req, _ := http.NewRequest(c.method, "/", read("arbitrary message"))
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.httpHandler().ServeHTTP(rr, req)
+
+			assert.Equal(t, c.expectCode, rr.Code, "unexpected response code")
+			for k, v := range c.expectHeaders {
+				assert.Equal(t, v, rr.Header().Get(k), "unexpected value of header %s", k)
+			}
+
+			if c.expectSent {
+				assert.Len(t, ceClient.Sent(), 1)
+			} else {
+				assert.Empty(t, ceClient.Sent())
+			}
+		})
+	}
+}
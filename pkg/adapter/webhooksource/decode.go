@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+const (
+	mediaTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	mediaTypeMultipartForm  = "multipart/form-data"
+
+	// maximum size of multipart form data kept in memory, beyond which
+	// files are buffered on disk while being decoded
+	multipartMaxMemory = 32 << 20 // 32 MiB
+)
+
+// bodyDecoder converts form submissions and query strings to JSON objects.
+type bodyDecoder struct {
+	// whether files contained in multipart forms are encoded in base64
+	// instead of being discarded
+	base64Files bool
+}
+
+// newBodyDecoder returns a bodyDecoder which handles files contained in
+// multipart forms according to the given mode.
+func newBodyDecoder(multipartFiles string) (*bodyDecoder, error) {
+	switch v1alpha1.WebhookMultipartFilesMode(multipartFiles) {
+	case v1alpha1.WebhookMultipartFilesIgnore:
+		return &bodyDecoder{}, nil
+	case v1alpha1.WebhookMultipartFilesBase64:
+		return &bodyDecoder{base64Files: true}, nil
+	default:
+		return nil, fmt.Errorf("unsupported handling mode of multipart files %q", multipartFiles)
+	}
+}
+
+// multipartFile is the JSON representation of a file contained in a
+// multipart form.
+type multipartFile struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType,omitempty"`
+	Content     string `json:"content"`
+}
+
+// decode returns the JSON representation of the payload of the given request.
+// The returned boolean is false when the payload is not subject to any
+// conversion, in which case body should be used as is.
+func (d *bodyDecoder) decode(r *http.Request, body []byte) ([]byte, bool, error) {
+	if r.Method == http.MethodGet {
+		if r.URL.RawQuery == "" {
+			return nil, false, nil
+		}
+		return marshalFields(r.URL.Query(), nil)
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return nil, false, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing Content-Type header: %w", err)
+	}
+
+	switch mediaType {
+	case mediaTypeFormURLEncoded:
+		vals, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, false, fmt.Errorf("parsing form: %w", err)
+		}
+		return marshalFields(vals, nil)
+
+	case mediaTypeMultipartForm:
+		return d.decodeMultipart(body, params["boundary"])
+
+	default:
+		return nil, false, nil
+	}
+}
+
+// decodeMultipart returns the JSON representation of a multipart form.
+func (d *bodyDecoder) decodeMultipart(body []byte, boundary string) ([]byte, bool, error) {
+	if boundary == "" {
+		return nil, false, errors.New("multipart form without boundary")
+	}
+
+	form, err := multipart.NewReader(bytes.NewReader(body), boundary).ReadForm(multipartMaxMemory)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing multipart form: %w", err)
+	}
+	defer func() { _ = form.RemoveAll() }()
+
+	var files map[string][]interface{}
+
+	if d.base64Files {
+		files = make(map[string][]interface{}, len(form.File))
+
+		for name, fhs := range form.File {
+			for _, fh := range fhs {
+				f, err := encodeFile(fh)
+				if err != nil {
+					return nil, false, fmt.Errorf("reading file %q: %w", fh.Filename, err)
+				}
+				files[name] = append(files[name], f)
+			}
+		}
+	}
+
+	return marshalFields(form.Value, files)
+}
+
+// encodeFile returns the JSON representation of a file contained in a
+// multipart form.
+func encodeFile(fh *multipart.FileHeader) (*multipartFile, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &multipartFile{
+		Filename:    fh.Filename,
+		ContentType: fh.Header.Get("Content-Type"),
+		Content:     base64.StdEncoding.EncodeToString(content),
+	}, nil
+}
+
+// marshalFields returns a JSON object containing the given fields. Fields
+// which occur only once are represented as single values, other fields as
+// arrays.
+func marshalFields(vals map[string][]string, files map[string][]interface{}) ([]byte, bool, error) {
+	fields := make(map[string][]interface{}, len(vals)+len(files))
+
+	for name, vs := range vals {
+		for _, v := range vs {
+			fields[name] = append(fields[name], v)
+		}
+	}
+	for name, fs := range files {
+		fields[name] = append(fields[name], fs...)
+	}
+
+	obj := make(map[string]interface{}, len(fields))
+	for name, vs := range fields {
+		if len(vs) == 1 {
+			obj[name] = vs[0]
+			continue
+		}
+		obj[name] = vs
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false, fmt.Errorf("serializing fields to JSON: %w", err)
+	}
+
+	return data, true, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyDecode(t *testing.T) {
+	multipartBody, multipartCT := multipartForm(t)
+
+	tc := map[string]struct {
+		method      string
+		target      string
+		contentType string
+		body        []byte
+		base64Files bool
+
+		expectDecoded bool
+		expectData    string
+		expectErr     string
+	}{
+		"query string": {
+			method:        http.MethodGet,
+			target:        "/?foo=bar&baz=1&baz=2",
+			expectDecoded: true,
+			expectData:    `{"baz":["1","2"],"foo":"bar"}`,
+		},
+		"GET without query string": {
+			method: http.MethodGet,
+			target: "/",
+		},
+		"query string ignored on POST": {
+			method:      http.MethodPost,
+			target:      "/?foo=bar",
+			contentType: "text/plain",
+			body:        []byte("hello"),
+		},
+		"JSON body": {
+			method:      http.MethodPost,
+			target:      "/",
+			contentType: "application/json",
+			body:        []byte(`{"foo":"bar"}`),
+		},
+		"urlencoded form": {
+			method:        http.MethodPost,
+			target:        "/",
+			contentType:   "application/x-www-form-urlencoded; charset=utf-8",
+			body:          []byte("foo=bar&baz=a+b&baz=c"),
+			expectDecoded: true,
+			expectData:    `{"baz":["a b","c"],"foo":"bar"}`,
+		},
+		"malformed urlencoded form": {
+			method:      http.MethodPost,
+			target:      "/",
+			contentType: "application/x-www-form-urlencoded",
+			body:        []byte("foo=%zz"),
+			expectErr:   "parsing form",
+		},
+		"multipart form fields only": {
+			method:        http.MethodPost,
+			target:        "/",
+			contentType:   multipartCT,
+			body:          multipartBody,
+			expectDecoded: true,
+			expectData:    `{"foo":"bar"}`,
+		},
+		"multipart form with base64 files": {
+			method:        http.MethodPost,
+			target:        "/",
+			contentType:   multipartCT,
+			body:          multipartBody,
+			base64Files:   true,
+			expectDecoded: true,
+			expectData: `{"foo":"bar","doc":{"filename":"hello.txt",` +
+				`"contentType":"application/octet-stream","content":"aGVsbG8="}}`,
+		},
+		"multipart form without boundary": {
+			method:      http.MethodPost,
+			target:      "/",
+			contentType: "multipart/form-data",
+			body:        multipartBody,
+			expectErr:   "multipart form without boundary",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.target, bytes.NewReader(c.body))
+			if c.contentType != "" {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+
+			d := &bodyDecoder{base64Files: c.base64Files}
+
+			data, decoded, err := d.decode(req, c.body)
+
+			if c.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expectDecoded, decoded)
+			if c.expectDecoded {
+				assert.JSONEq(t, c.expectData, string(data))
+			}
+		})
+	}
+}
+
+func TestNewBodyDecoder(t *testing.T) {
+	d, err := newBodyDecoder("Ignore")
+	require.NoError(t, err)
+	assert.False(t, d.base64Files)
+
+	d, err = newBodyDecoder("Base64")
+	require.NoError(t, err)
+	assert.True(t, d.base64Files)
+
+	_, err = newBodyDecoder("Raw")
+	assert.EqualError(t, err, `unsupported handling mode of multipart files "Raw"`)
+}
+
+// multipartForm returns a multipart form containing a field and a file,
+// along with its Content-Type.
+func multipartForm(t *testing.T) ([]byte, string) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	mw := multipart.NewWriter(buf)
+
+	require.NoError(t, mw.WriteField("foo", "bar"))
+
+	fw, err := mw.CreateFormFile("doc", "hello.txt")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	require.NoError(t, mw.Close())
+
+	return buf.Bytes(), mw.FormDataContentType()
+}
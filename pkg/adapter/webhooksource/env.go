@@ -17,9 +17,19 @@ limitations under the License.
 package webhooksource
 
 import (
+	"encoding/json"
+	"time"
+
 	"knative.dev/eventing/pkg/adapter/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
+// envBasicAuthAdditionalPasswords is the prefix of the indexed environment
+// variables which contain additional passwords for HTTP Basic authentication.
+const envBasicAuthAdditionalPasswords = "WEBHOOK_BASICAUTH_ADDITIONAL_PASSWORD"
+
 // EnvAccessor for configuration parameters
 func EnvAccessor() adapter.EnvConfigAccessor {
 	return &envAccessor{}
@@ -27,9 +37,78 @@ func EnvAccessor() adapter.EnvConfigAccessor {
 
 type envAccessor struct {
 	adapter.EnvConfig
+	limiter.EnvLimits
+	schema.EnvSchema
 
 	EventType         string `envconfig:"WEBHOOK_EVENT_TYPE" required:"true"`
 	EventSource       string `envconfig:"WEBHOOK_EVENT_SOURCE" required:"true"`
 	BasicAuthUsername string `envconfig:"WEBHOOK_BASICAUTH_USERNAME"`
 	BasicAuthPassword string `envconfig:"WEBHOOK_BASICAUTH_PASSWORD"`
+	// Populated from indexed variables, see credentials.FromEnv.
+	BasicAuthAdditionalPasswords []string `ignored:"true"`
+
+	JWTIssuer             string        `envconfig:"WEBHOOK_JWT_ISSUER"`
+	JWTAudiences          []string      `envconfig:"WEBHOOK_JWT_AUDIENCES"`
+	JWTAlgorithms         []string      `envconfig:"WEBHOOK_JWT_ALGORITHMS"`
+	JWTJWKS               string        `envconfig:"WEBHOOK_JWT_JWKS"`
+	JWTJWKSURL            string        `envconfig:"WEBHOOK_JWT_JWKS_URL"`
+	JWTJWKSCacheDuration  time.Duration `envconfig:"WEBHOOK_JWT_JWKS_CACHE_DURATION" default:"1h"`
+	JWTClaimsToExtensions jsonStringMap `envconfig:"WEBHOOK_JWT_CLAIMS_TO_EXTENSIONS"`
+
+	ClientCertCABundle          string         `envconfig:"WEBHOOK_CLIENT_CERT_CA_BUNDLE"`
+	ClientCertHeader            string         `envconfig:"WEBHOOK_CLIENT_CERT_HEADER"`
+	ClientCertTrustedProxyCIDRs []string       `envconfig:"WEBHOOK_CLIENT_CERT_TRUSTED_PROXY_CIDRS"`
+	ClientCertAllowedSubjects   jsonStringList `envconfig:"WEBHOOK_CLIENT_CERT_ALLOWED_SUBJECTS"`
+	ClientCertAllowedSANs       jsonStringList `envconfig:"WEBHOOK_CLIENT_CERT_ALLOWED_SANS"`
+
+	AsyncDelivery     bool          `envconfig:"WEBHOOK_ASYNC_DELIVERY"`
+	AsyncQueueSize    int           `envconfig:"WEBHOOK_ASYNC_QUEUE_SIZE" default:"100"`
+	AsyncRetries      int           `envconfig:"WEBHOOK_ASYNC_RETRIES" default:"3"`
+	AsyncBackoffDelay time.Duration `envconfig:"WEBHOOK_ASYNC_BACKOFF_DELAY" default:"1s"`
+	// Set in multi-tenant mode, see tenantEnv.
+	AsyncQueueName string `ignored:"true"`
+
+	CORSAllowedOrigins []string      `envconfig:"WEBHOOK_CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods []string      `envconfig:"WEBHOOK_CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders []string      `envconfig:"WEBHOOK_CORS_ALLOWED_HEADERS"`
+	CORSMaxAge         time.Duration `envconfig:"WEBHOOK_CORS_MAX_AGE"`
+
+	AllowedCIDRs      []string `envconfig:"WEBHOOK_ALLOWED_CIDRS"`
+	TrustedProxyCIDRs []string `envconfig:"WEBHOOK_TRUSTED_PROXY_CIDRS"`
+
+	BodyDecoding   bool   `envconfig:"WEBHOOK_BODY_DECODING"`
+	MultipartFiles string `envconfig:"WEBHOOK_MULTIPART_FILES" default:"Ignore"`
+
+	BatchSplitting      bool   `envconfig:"WEBHOOK_BATCH_SPLITTING"`
+	BatchPath           string `envconfig:"WEBHOOK_BATCH_PATH"`
+	BatchIDField        string `envconfig:"WEBHOOK_BATCH_ID_FIELD"`
+	BatchPartialFailure string `envconfig:"WEBHOOK_BATCH_PARTIAL_FAILURE" default:"Fail"`
+
+	IdempotencyKeyHeader string        `envconfig:"WEBHOOK_IDEMPOTENCY_KEY_HEADER"`
+	IdempotencyKeyField  string        `envconfig:"WEBHOOK_IDEMPOTENCY_KEY_FIELD"`
+	IdempotencyTTL       time.Duration `envconfig:"WEBHOOK_IDEMPOTENCY_TTL" default:"1h"`
+
+	ResponseStatusCode int           `envconfig:"WEBHOOK_RESPONSE_STATUS_CODE"`
+	ResponseHeaders    jsonStringMap `envconfig:"WEBHOOK_RESPONSE_HEADERS"`
+	ResponseBody       string        `envconfig:"WEBHOOK_RESPONSE_BODY"`
+}
+
+// jsonStringMap is a map of strings decoded from a JSON object. Unlike maps in
+// the native envconfig format, its keys and values may contain commas and
+// colons, such as in header values or URLs.
+type jsonStringMap map[string]string
+
+// Decode implements envconfig.Decoder.
+func (m *jsonStringMap) Decode(value string) error {
+	return json.Unmarshal([]byte(value), (*map[string]string)(m))
+}
+
+// jsonStringList is a list of strings decoded from a JSON array. Unlike lists
+// in the native envconfig format, its elements may contain commas, such as in
+// distinguished names with multiple RDNs.
+type jsonStringList []string
+
+// Decode implements envconfig.Decoder.
+func (l *jsonStringList) Decode(value string) error {
+	return json.Unmarshal([]byte(value), (*[]string)(l))
 }
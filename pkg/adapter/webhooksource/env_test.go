@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvJSONStringMaps(t *testing.T) {
+	setEnv(t, map[string]string{
+		"WEBHOOK_EVENT_TYPE":               tEventType,
+		"WEBHOOK_EVENT_SOURCE":             tEventSource,
+		"WEBHOOK_RESPONSE_HEADERS":         `{"Cache-Control":"no-cache, no-store","Link":"<https://example.com/docs>; rel=help"}`,
+		"WEBHOOK_JWT_CLAIMS_TO_EXTENSIONS": `{"https://example.com/claims/tenant":"tenant"}`,
+	})
+
+	env := &envAccessor{}
+	require.NoError(t, envconfig.Process("", env))
+
+	expectHeaders := jsonStringMap{
+		"Cache-Control": "no-cache, no-store",
+		"Link":          "<https://example.com/docs>; rel=help",
+	}
+	assert.Equal(t, expectHeaders, env.ResponseHeaders)
+
+	expectClaims := jsonStringMap{
+		"https://example.com/claims/tenant": "tenant",
+	}
+	assert.Equal(t, expectClaims, env.JWTClaimsToExtensions)
+}
+
+func TestEnvInvalidJSONStringMap(t *testing.T) {
+	setEnv(t, map[string]string{
+		"WEBHOOK_EVENT_TYPE":       tEventType,
+		"WEBHOOK_EVENT_SOURCE":     tEventSource,
+		"WEBHOOK_RESPONSE_HEADERS": "Cache-Control:no-cache",
+	})
+
+	assert.Error(t, envconfig.Process("", &envAccessor{}))
+}
+
+// setEnv sets the given environment variables for the duration of a test.
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for k, v := range env {
+		require.NoError(t, os.Setenv(k, v))
+		k := k
+		t.Cleanup(func() { _ = os.Unsetenv(k) })
+	}
+}
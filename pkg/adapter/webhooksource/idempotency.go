@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"net/http"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+)
+
+// idempotency identifies duplicate requests using an idempotency key read
+// from a header or a field of the JSON payload.
+type idempotency struct {
+	header string
+	field  []string
+
+	seen *dedup.Cache
+}
+
+// newIdempotency returns an idempotency for the given configuration, or nil if
+// no source of idempotency keys is configured.
+func newIdempotency(env *envAccessor) *idempotency {
+	if env.IdempotencyKeyHeader == "" && env.IdempotencyKeyField == "" {
+		return nil
+	}
+
+	return &idempotency{
+		header: env.IdempotencyKeyHeader,
+		field:  splitPath(env.IdempotencyKeyField),
+		seen:   dedup.New(env.IdempotencyTTL),
+	}
+}
+
+// key returns the idempotency key of a request, or an empty string if the
+// request doesn't have any.
+// The key is read from the header when set, otherwise from the payload.
+func (i *idempotency) key(h http.Header, body []byte) string {
+	if i.header != "" {
+		if k := h.Get(i.header); k != "" {
+			return k
+		}
+	}
+
+	if i.field == nil {
+		return ""
+	}
+
+	raw, err := lookupRaw(body, i.field)
+	if err != nil {
+		return ""
+	}
+
+	return scalarString(raw)
+}
@@ -0,0 +1,408 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer " // the trailing space must not be removed
+
+	// tolerated clock skew when validating time-based claims
+	jwtLeeway = time.Minute
+
+	defaultJWTAlgorithm = jose.RS256
+
+	// minimum delay between two retrievals of a remote JWKS document
+	jwksMinRefreshInterval = 30 * time.Second
+	// minimum delay before retrying a failed retrieval of a remote JWKS
+	// document which was never retrieved successfully
+	jwksRetryInterval  = 5 * time.Second
+	jwksRequestTimeout = 10 * time.Second
+)
+
+// jwtValidator validates JSON Web Tokens passed by HTTP clients in the
+// Authorization header.
+type jwtValidator struct {
+	issuer     string
+	audiences  []string
+	algorithms []string
+
+	keys keySet
+
+	// claim name -> CloudEvent extension name
+	claimsToExtensions map[string]string
+
+	// allows mocking the current time in tests
+	now func() time.Time
+}
+
+// newJWTValidator returns a jwtValidator configured from the given
+// environment, or nil if JWT authentication is not enabled.
+func newJWTValidator(env *envAccessor) (*jwtValidator, error) {
+	var ks keySet
+
+	switch {
+	case env.JWTJWKS != "":
+		sks, err := newStaticKeySet([]byte(env.JWTJWKS))
+		if err != nil {
+			return nil, err
+		}
+		ks = sks
+
+	case env.JWTJWKSURL != "":
+		ks = &remoteKeySet{
+			url:      env.JWTJWKSURL,
+			cacheTTL: env.JWTJWKSCacheDuration,
+			client:   &http.Client{Timeout: jwksRequestTimeout},
+			now:      time.Now,
+		}
+
+	default:
+		return nil, nil
+	}
+
+	if env.JWTIssuer == "" {
+		return nil, errors.New("an issuer is required for JWT authentication")
+	}
+
+	algs := env.JWTAlgorithms
+	if len(algs) == 0 {
+		algs = []string{string(defaultJWTAlgorithm)}
+	}
+
+	return &jwtValidator{
+		issuer:             env.JWTIssuer,
+		audiences:          env.JWTAudiences,
+		algorithms:         algs,
+		keys:               ks,
+		claimsToExtensions: env.JWTClaimsToExtensions,
+		now:                time.Now,
+	}, nil
+}
+
+// validate verifies the bearer token contained in the given request headers
+// and returns its claims.
+func (v *jwtValidator) validate(ctx context.Context, h http.Header) (map[string]interface{}, error) {
+	auth := h.Get(headerAuthorization)
+	if !strings.HasPrefix(auth, bearerPrefix) {
+		return nil, errors.New("missing bearer token")
+	}
+
+	tok, err := jwt.ParseSigned(auth[len(bearerPrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("parsing token: %w", err)
+	}
+
+	if len(tok.Headers) != 1 {
+		return nil, errors.New("token must have exactly one signature")
+	}
+	hdr := tok.Headers[0]
+
+	if !v.isAllowedAlgorithm(hdr.Algorithm) {
+		return nil, fmt.Errorf("signature algorithm %q is not allowed", hdr.Algorithm)
+	}
+
+	keys, err := v.keys.keys(ctx, hdr.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("obtaining verification keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no verification key matches the token's key ID %q", hdr.KeyID)
+	}
+
+	var stdClaims jwt.Claims
+	var allClaims map[string]interface{}
+
+	var verifyErr error
+	for _, k := range keys {
+		if verifyErr = tok.Claims(k.Key, &stdClaims, &allClaims); verifyErr == nil {
+			break
+		}
+	}
+	if verifyErr != nil {
+		return nil, fmt.Errorf("verifying token signature: %w", verifyErr)
+	}
+
+	expect := jwt.Expected{
+		Issuer: v.issuer,
+		Time:   v.now(),
+	}
+	if err := stdClaims.ValidateWithLeeway(expect, jwtLeeway); err != nil {
+		return nil, fmt.Errorf("validating token claims: %w", err)
+	}
+	// tokens are otherwise valid forever
+	if stdClaims.Expiry == nil {
+		return nil, errors.New("token has no expiration time")
+	}
+
+	if !v.hasAllowedAudience(stdClaims.Audience) {
+		return nil, errors.New("token was not issued for an accepted audience")
+	}
+
+	return allClaims, nil
+}
+
+// isAllowedAlgorithm returns whether the given signature algorithm is accepted.
+func (v *jwtValidator) isAllowedAlgorithm(alg string) bool {
+	for _, a := range v.algorithms {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllowedAudience returns whether the given audience claim contains at
+// least one accepted audience. Any audience is accepted if none was
+// configured.
+func (v *jwtValidator) hasAllowedAudience(aud jwt.Audience) bool {
+	if len(v.audiences) == 0 {
+		return true
+	}
+
+	for _, a := range v.audiences {
+		if aud.Contains(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// setExtensions propagates the configured claims to the given event as
+// CloudEvents extensions.
+func (v *jwtValidator) setExtensions(event *cloudevents.Event, claims map[string]interface{}) {
+	for claim, ext := range v.claimsToExtensions {
+		val, ok := claims[claim]
+		if !ok {
+			continue
+		}
+
+		if s, isStr := val.(string); isStr {
+			event.SetExtension(ext, s)
+			continue
+		}
+
+		// non-string claims are propagated in their JSON representation
+		if b, err := json.Marshal(val); err == nil {
+			event.SetExtension(ext, string(b))
+		}
+	}
+}
+
+// keySet provides the keys used to verify the signature of tokens.
+type keySet interface {
+	// keys returns the keys matching the given key ID, or all keys if the
+	// key ID is empty.
+	keys(ctx context.Context, kid string) ([]jose.JSONWebKey, error)
+}
+
+// staticKeySet is a keySet read once from a JWKS document.
+type staticKeySet struct {
+	jwks *jose.JSONWebKeySet
+}
+
+var _ keySet = (*staticKeySet)(nil)
+
+// newStaticKeySet parses the given JWKS document.
+func newStaticKeySet(doc []byte) (*staticKeySet, error) {
+	jwks := &jose.JSONWebKeySet{}
+	if err := json.Unmarshal(doc, jwks); err != nil {
+		return nil, fmt.Errorf("parsing JWKS document: %w", err)
+	}
+
+	return &staticKeySet{jwks: jwks}, nil
+}
+
+// keys implements keySet.
+func (s *staticKeySet) keys(_ context.Context, kid string) ([]jose.JSONWebKey, error) {
+	return selectKeys(s.jwks, kid), nil
+}
+
+// remoteKeySet is a keySet which JWKS document is retrieved from a URL and
+// cached for a given duration.
+type remoteKeySet struct {
+	url      string
+	cacheTTL time.Duration
+	client   *http.Client
+
+	// coalesces concurrent retrievals of the JWKS document
+	group singleflight.Group
+
+	mu        sync.Mutex
+	jwks      *jose.JSONWebKeySet
+	fetchedAt time.Time
+	expiresAt time.Time
+	// error of the last retrieval, returned until retryAt when no JWKS
+	// document was ever retrieved
+	fetchErr error
+	retryAt  time.Time
+
+	// allows mocking the current time in tests
+	now func() time.Time
+}
+
+var _ keySet = (*remoteKeySet)(nil)
+
+// keys implements keySet.
+// The cached JWKS document is refreshed once it expires, or when it doesn't
+// contain the requested key ID, which typically happens after a rotation of
+// the issuer's keys.
+func (s *remoteKeySet) keys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	if keys, ok, err := s.cachedKeys(kid); ok {
+		return keys, err
+	}
+
+	// the document is retrieved without holding the lock, so that requests
+	// which can be served from the cache aren't held up by a slow JWKS
+	// endpoint, and concurrent requests wait for a single retrieval
+	resCh := s.group.DoChan("", func() (interface{}, error) {
+		return nil, s.refresh()
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-resCh:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return selectKeys(s.jwks, kid), nil
+}
+
+// cachedKeys returns the keys matching the given key ID from the cached JWKS
+// document, or the error of a recent failed retrieval. The returned boolean is
+// false if the JWKS document should be retrieved instead.
+func (s *remoteKeySet) cachedKeys(kid string) ([]jose.JSONWebKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if s.jwks == nil {
+		if s.fetchErr != nil && now.Before(s.retryAt) {
+			return nil, true, s.fetchErr
+		}
+		return nil, false, nil
+	}
+
+	if now.Before(s.expiresAt) {
+		keys := selectKeys(s.jwks, kid)
+
+		// prevent clients from causing excessive requests to the JWKS
+		// endpoint by presenting unknown key IDs
+		if len(keys) > 0 || now.Sub(s.fetchedAt) < jwksMinRefreshInterval {
+			return keys, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// refresh retrieves the JWKS document and updates the cache.
+func (s *remoteKeySet) refresh() error {
+	// the retrieval is shared by concurrent requests, so it isn't bound to
+	// the context of any of them
+	jwks, err := s.fetch(context.Background())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.fetchedAt = now
+
+	if err != nil {
+		// serve stale keys rather than rejecting all requests while
+		// the JWKS endpoint is unavailable
+		if s.jwks != nil {
+			s.expiresAt = now.Add(jwksMinRefreshInterval)
+			return nil
+		}
+
+		// don't retry immediately, so that requests fail fast while
+		// the JWKS endpoint is unavailable
+		s.fetchErr = err
+		s.retryAt = now.Add(jwksRetryInterval)
+		return err
+	}
+
+	s.jwks = jwks
+	s.expiresAt = now.Add(s.cacheTTL)
+	s.fetchErr = nil
+
+	return nil
+}
+
+// fetch retrieves the JWKS document from the key set's URL.
+func (s *remoteKeySet) fetch(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting JWKS document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("requesting JWKS document: unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWKS document: %w", err)
+	}
+
+	ks, err := newStaticKeySet(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ks.jwks, nil
+}
+
+// selectKeys returns the keys from jwks matching the given key ID, or all keys
+// if the key ID is empty.
+func selectKeys(jwks *jose.JSONWebKeySet, kid string) []jose.JSONWebKey {
+	if kid == "" {
+		return jwks.Keys
+	}
+	return jwks.Key(kid)
+}
@@ -0,0 +1,390 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	tKeyID    = "test-key"
+	tIssuer   = "https://issuer.example.com"
+	tAudience = "webhook"
+)
+
+var tNow = time.Unix(1600000000, 0)
+
+func TestJWTValidate(t *testing.T) {
+	key := newRSAKey(t)
+	otherKey := newRSAKey(t)
+
+	validClaims := jwt.Claims{
+		Issuer:   tIssuer,
+		Audience: jwt.Audience{tAudience},
+		Subject:  "producer-1",
+		Expiry:   jwt.NewNumericDate(tNow.Add(time.Hour)),
+	}
+
+	tc := map[string]struct {
+		header     string
+		algorithms []string
+
+		expectErr string
+	}{
+		"valid token": {
+			header: bearer(sign(t, key, tKeyID, jose.RS256, validClaims)),
+		},
+		"no bearer token": {
+			header:    basicAuth("foo", "bar"),
+			expectErr: "missing bearer token",
+		},
+		"malformed token": {
+			header:    bearer("not.a.token"),
+			expectErr: "parsing token",
+		},
+		"disallowed algorithm": {
+			header:     bearer(sign(t, key, tKeyID, jose.RS256, validClaims)),
+			algorithms: []string{string(jose.ES256)},
+			expectErr:  `signature algorithm "RS256" is not allowed`,
+		},
+		"unknown key ID": {
+			header:    bearer(sign(t, key, "unknown", jose.RS256, validClaims)),
+			expectErr: "no verification key matches",
+		},
+		"invalid signature": {
+			header:    bearer(sign(t, otherKey, tKeyID, jose.RS256, validClaims)),
+			expectErr: "verifying token signature",
+		},
+		"wrong issuer": {
+			header: bearer(sign(t, key, tKeyID, jose.RS256, jwt.Claims{
+				Issuer:   "https://evil.example.com",
+				Audience: jwt.Audience{tAudience},
+				Expiry:   jwt.NewNumericDate(tNow.Add(time.Hour)),
+			})),
+			expectErr: "validating token claims",
+		},
+		"expired token": {
+			header: bearer(sign(t, key, tKeyID, jose.RS256, jwt.Claims{
+				Issuer:   tIssuer,
+				Audience: jwt.Audience{tAudience},
+				Expiry:   jwt.NewNumericDate(tNow.Add(-time.Hour)),
+			})),
+			expectErr: "validating token claims",
+		},
+		"no expiration time": {
+			header: bearer(sign(t, key, tKeyID, jose.RS256, jwt.Claims{
+				Issuer:   tIssuer,
+				Audience: jwt.Audience{tAudience},
+			})),
+			expectErr: "token has no expiration time",
+		},
+		"wrong audience": {
+			header: bearer(sign(t, key, tKeyID, jose.RS256, jwt.Claims{
+				Issuer:   tIssuer,
+				Audience: jwt.Audience{"other"},
+				Expiry:   jwt.NewNumericDate(tNow.Add(time.Hour)),
+			})),
+			expectErr: "token was not issued for an accepted audience",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			algs := c.algorithms
+			if algs == nil {
+				algs = []string{string(jose.RS256)}
+			}
+
+			v := &jwtValidator{
+				issuer:     tIssuer,
+				audiences:  []string{"other-webhook", tAudience},
+				algorithms: algs,
+				keys:       &staticKeySet{jwks: jwksFor(key)},
+				now:        func() time.Time { return tNow },
+			}
+
+			h := http.Header{}
+			h.Set(headerAuthorization, c.header)
+
+			claims, err := v.validate(context.Background(), h)
+
+			if c.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "producer-1", claims["sub"])
+		})
+	}
+}
+
+func TestNewJWTValidatorRequiresIssuer(t *testing.T) {
+	env := &envAccessor{
+		JWTJWKSURL: "https://example.com/jwks.json",
+	}
+
+	_, err := newJWTValidator(env)
+	assert.Error(t, err)
+
+	env.JWTIssuer = tIssuer
+
+	v, err := newJWTValidator(env)
+	require.NoError(t, err)
+	assert.Equal(t, tIssuer, v.issuer)
+}
+
+func TestJWTSetExtensions(t *testing.T) {
+	v := &jwtValidator{
+		claimsToExtensions: map[string]string{
+			"sub":    "jwtsubject",
+			"groups": "jwtgroups",
+			"absent": "jwtabsent",
+		},
+	}
+
+	claims := map[string]interface{}{
+		"sub":    "producer-1",
+		"groups": []interface{}{"a", "b"},
+	}
+
+	event := cloudevents.NewEvent()
+	v.setExtensions(&event, claims)
+
+	assert.Equal(t, map[string]interface{}{
+		"jwtsubject": "producer-1",
+		"jwtgroups":  `["a","b"]`,
+	}, event.Extensions())
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	key := newRSAKey(t)
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(jwksFor(key))
+	}))
+	defer srv.Close()
+
+	now := tNow
+
+	ks := &remoteKeySet{
+		url:      srv.URL,
+		cacheTTL: time.Hour,
+		client:   srv.Client(),
+		now:      func() time.Time { return now },
+	}
+
+	keys, err := ks.keys(context.Background(), tKeyID)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests), "Expected JWKS document to be fetched")
+
+	_, err = ks.keys(context.Background(), tKeyID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests), "Expected JWKS document to be cached")
+
+	// unknown key IDs don't cause a refresh within the minimum interval
+	keys, err = ks.keys(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests), "Expected JWKS document to be cached")
+
+	now = now.Add(jwksMinRefreshInterval)
+	_, err = ks.keys(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests), "Expected JWKS document to be refreshed")
+
+	now = now.Add(2 * time.Hour)
+	_, err = ks.keys(context.Background(), tKeyID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests), "Expected expired JWKS document to be refreshed")
+}
+
+func TestRemoteKeySetConcurrentFetch(t *testing.T) {
+	key := newRSAKey(t)
+
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_ = json.NewEncoder(w).Encode(jwksFor(key))
+	}))
+	defer srv.Close()
+
+	ks := &remoteKeySet{
+		url:      srv.URL,
+		cacheTTL: time.Hour,
+		client:   srv.Client(),
+		now:      func() time.Time { return tNow },
+	}
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ks.keys(context.Background(), tKeyID)
+			errs <- err
+		}()
+	}
+
+	// give all callers a chance to wait for the pending retrieval
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests), "Expected JWKS document to be fetched once")
+}
+
+func TestRemoteKeySetCachedDuringFetch(t *testing.T) {
+	key := newRSAKey(t)
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ks := &remoteKeySet{
+		url:       srv.URL,
+		cacheTTL:  time.Hour,
+		client:    srv.Client(),
+		jwks:      jwksFor(key),
+		fetchedAt: tNow,
+		expiresAt: tNow.Add(time.Hour),
+		now:       func() time.Time { return tNow.Add(jwksMinRefreshInterval) },
+	}
+
+	// unknown key ID, causes a retrieval which doesn't complete
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := ks.keys(ctx, "unknown")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		keys, err := ks.keys(context.Background(), tKeyID)
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Cached keys were not returned during the retrieval of the JWKS document")
+	}
+}
+
+func TestRemoteKeySetFetchFailure(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	now := tNow
+
+	ks := &remoteKeySet{
+		url:      srv.URL,
+		cacheTTL: time.Hour,
+		client:   srv.Client(),
+		now:      func() time.Time { return now },
+	}
+
+	_, err := ks.keys(context.Background(), tKeyID)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+
+	_, err = ks.keys(context.Background(), tKeyID)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests), "Expected failure to be cached")
+
+	now = now.Add(jwksRetryInterval)
+	_, err = ks.keys(context.Background(), tKeyID)
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests), "Expected retrieval to be retried")
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return key
+}
+
+// jwksFor returns a JWKS containing the public part of the given key.
+func jwksFor(key *rsa.PrivateKey) *jose.JSONWebKeySet {
+	return &jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       &key.PublicKey,
+			KeyID:     tKeyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}},
+	}
+}
+
+// sign returns a signed and serialized JWT containing the given claims.
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, alg jose.SignatureAlgorithm, claims jwt.Claims) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: alg, Key: jose.JSONWebKey{Key: key, KeyID: kid}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	require.NoError(t, err)
+
+	tok, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return tok
+}
+
+func bearer(token string) string {
+	return bearerPrefix + token
+}
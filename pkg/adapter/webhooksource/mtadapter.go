@@ -0,0 +1,226 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	pkgadapter "knative.dev/eventing/pkg/adapter/v2"
+	k8sclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/env"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/router"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/pkg/routing"
+	"github.com/triggermesh/knative-sources/pkg/secret"
+)
+
+// mtAdapter is the multi-tenant flavour of the source's adapter. It serves
+// all WebhookSources of a namespace, each at its own URL path.
+type mtAdapter struct {
+	logger *zap.SugaredLogger
+
+	ceClient   cloudevents.Client
+	secrGetter secret.Getter
+	cmClient   coreclientv1.ConfigMapInterface
+
+	// fields accessed during object reconciliation
+	router *router.Router
+
+	// tenants currently registered, indexed by URL path
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+// tenant is the handler serving a single WebhookSource in the multi-tenant
+// adapter.
+type tenant struct {
+	handler *webhookHandler
+
+	// configuration the handler was created from
+	env  *envAccessor
+	sink string
+}
+
+// Check the interfaces mtAdapter should implement.
+var (
+	_ pkgadapter.Adapter = (*mtAdapter)(nil)
+	_ MTAdapter          = (*mtAdapter)(nil)
+	_ http.Handler       = (*mtAdapter)(nil)
+)
+
+// NewEnvConfig satisfies env.ConfigConstructor.
+// Returns an accessor for the multi-tenant adapter envConfig.
+func NewEnvConfig() env.ConfigAccessor {
+	return &env.Config{}
+}
+
+// NewMTAdapter returns a constructor for the source's multi-tenant adapter.
+func NewMTAdapter(component string) pkgadapter.AdapterConstructor {
+	return func(ctx context.Context, _ pkgadapter.EnvConfigAccessor,
+		ceClient cloudevents.Client) pkgadapter.Adapter {
+
+		ns := injection.GetNamespaceScope(ctx)
+		coreCli := k8sclient.Get(ctx).CoreV1()
+
+		return &mtAdapter{
+			logger: logging.FromContext(ctx),
+
+			ceClient:   ceClient,
+			secrGetter: secret.NewGetter(coreCli.Secrets(ns)),
+			cmClient:   coreCli.ConfigMaps(ns),
+
+			router:  &router.Router{},
+			tenants: make(map[string]*tenant),
+		}
+	}
+}
+
+// Start implements adapter.Adapter.
+func (a *mtAdapter) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    fmt.Sprint(":", serverPort),
+		Handler: a,
+	}
+
+	err := runHandler(ctx, server, nil)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// all queues are drained concurrently, within a single grace period
+	drainCtx, cancel := context.WithTimeout(context.Background(), serverShutdownGracePeriod)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for path, t := range a.tenants {
+		wg.Add(1)
+		go func(h *webhookHandler) {
+			defer wg.Done()
+			a.drainQueue(drainCtx, h)
+		}(t.handler)
+		delete(a.tenants, path)
+	}
+	wg.Wait()
+
+	return err
+}
+
+// ServeHTTP implements http.Handler.
+// Delegates incoming requests to the underlying router.
+func (a *mtAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.router.ServeHTTP(w, r)
+}
+
+// RegisterHandlerFor implements MTAdapter.
+// The handler of a source is only replaced when its configuration changed,
+// so that the state it accumulates, such as idempotency keys, rate limits or
+// buffered events, survives periodic reconciliations.
+func (a *mtAdapter) RegisterHandlerFor(ctx context.Context, src *v1alpha1.WebhookSource) error {
+	env, err := tenantEnv(src, a.secrGetter, a.cmClient)
+	if err != nil {
+		return err
+	}
+	sink := src.Status.SinkURI.String()
+
+	urlPath := routing.URLPath(src)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prev, hasPrev := a.tenants[urlPath]
+	if hasPrev && prev.sink == sink && reflect.DeepEqual(prev.env, env) {
+		return nil
+	}
+
+	logger := a.logger.With(zap.String("source", src.Namespace+"/"+src.Name))
+
+	h, err := newHandler(env, a.ceClient, logger)
+	if err != nil {
+		return fmt.Errorf("configuring webhook handler: %w", err)
+	}
+	h.sink = sink
+
+	if h.queue != nil {
+		h.queue.Start()
+	}
+
+	a.router.RegisterPath(urlPath, h.httpHandler())
+
+	if hasPrev {
+		a.drainQueueInBackground(prev.handler)
+	}
+	a.tenants[urlPath] = &tenant{
+		handler: h,
+		env:     env,
+		sink:    sink,
+	}
+
+	return nil
+}
+
+// DeregisterHandlerFor implements MTAdapter.
+func (a *mtAdapter) DeregisterHandlerFor(ctx context.Context, src v1alpha1.EventSource) error {
+	urlPath := routing.URLPath(src)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.router.DeregisterPath(urlPath)
+
+	if t, ok := a.tenants[urlPath]; ok {
+		a.drainQueueInBackground(t.handler)
+		delete(a.tenants, urlPath)
+	}
+
+	return nil
+}
+
+// drainQueueInBackground drains the delivery queue of the given handler
+// without blocking, within the server's shutdown grace period.
+func (a *mtAdapter) drainQueueInBackground(h *webhookHandler) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownGracePeriod)
+		defer cancel()
+
+		a.drainQueue(ctx, h)
+	}()
+}
+
+// drainQueue delivers the events buffered in the delivery queue of the given
+// handler, if any, until ctx is done.
+func (a *mtAdapter) drainQueue(ctx context.Context, h *webhookHandler) {
+	if h.queue == nil {
+		return
+	}
+
+	if err := h.queue.Drain(ctx); err != nil {
+		h.logger.Errorw("Failed to drain delivery queue", zap.Error(err))
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// response is a custom response returned to clients which requests are
+// successfully processed.
+type response struct {
+	// zero if the default status code applies
+	statusCode int
+	headers    map[string]string
+	body       []byte
+}
+
+// newResponse returns a response for the given configuration, or nil if no
+// custom response is configured.
+func newResponse(env *envAccessor) (*response, error) {
+	if env.ResponseStatusCode == 0 && len(env.ResponseHeaders) == 0 && env.ResponseBody == "" {
+		return nil, nil
+	}
+
+	if sc := env.ResponseStatusCode; sc != 0 && (sc < 200 || sc > 299) {
+		return nil, fmt.Errorf("status code %d is not a success status code", sc)
+	}
+
+	if env.ResponseStatusCode == http.StatusNoContent && env.ResponseBody != "" {
+		return nil, fmt.Errorf("status code %d doesn't allow a response body", env.ResponseStatusCode)
+	}
+
+	return &response{
+		statusCode: env.ResponseStatusCode,
+		headers:    env.ResponseHeaders,
+		body:       []byte(env.ResponseBody),
+	}, nil
+}
+
+// respond writes the response to a successfully processed request. The given
+// status code applies unless the custom response overrides it.
+func (h *webhookHandler) respond(w http.ResponseWriter, code int) {
+	r := h.response
+	if r == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	for k, v := range r.headers {
+		w.Header().Set(k, v)
+	}
+
+	if r.statusCode != 0 {
+		code = r.statusCode
+	}
+	w.WriteHeader(code)
+
+	if len(r.body) > 0 {
+		if _, err := w.Write(r.body); err != nil {
+			h.logger.Errorw("Failed to write response", zap.Error(err))
+		}
+	}
+}
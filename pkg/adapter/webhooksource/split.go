@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+// batchSplitter splits an array contained in JSON payloads into individual
+// elements.
+type batchSplitter struct {
+	// location of the array in the payload
+	path []string
+	// location of the ID of each element
+	idField []string
+
+	partialFailure v1alpha1.WebhookPartialFailurePolicy
+}
+
+// newBatchSplitter returns a batchSplitter for the given dot-separated paths
+// and partial failure policy.
+func newBatchSplitter(path, idField, partialFailure string) (*batchSplitter, error) {
+	pf := v1alpha1.WebhookPartialFailurePolicy(partialFailure)
+
+	switch pf {
+	case v1alpha1.WebhookPartialFailureFail,
+		v1alpha1.WebhookPartialFailureIgnore,
+		v1alpha1.WebhookPartialFailureMultiStatus:
+	default:
+		return nil, fmt.Errorf("unsupported partial failure policy %q", partialFailure)
+	}
+
+	return &batchSplitter{
+		path:           splitPath(path),
+		idField:        splitPath(idField),
+		partialFailure: pf,
+	}, nil
+}
+
+// batchElement is a single element of a batched payload.
+type batchElement struct {
+	// empty if the element has no ID
+	id   string
+	data []byte
+}
+
+// split returns the elements of the array contained in the given JSON
+// payload. Elements are returned as they appear in the payload, without being
+// re-encoded.
+func (s *batchSplitter) split(body []byte) ([]batchElement, error) {
+	raw, err := lookupRaw(body, s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, fmt.Errorf("value at path %q is not an array", strings.Join(s.path, "."))
+	}
+
+	batch := make([]batchElement, len(elems))
+	for i, e := range elems {
+		batch[i] = batchElement{
+			id:   s.elementID(e),
+			data: e,
+		}
+	}
+
+	return batch, nil
+}
+
+// elementID returns the ID of the given element, or an empty string if the
+// element doesn't contain an ID which can be represented as a string.
+func (s *batchSplitter) elementID(elem json.RawMessage) string {
+	if len(s.idField) == 0 {
+		return ""
+	}
+
+	raw, err := lookupRaw(elem, s.idField)
+	if err != nil {
+		return ""
+	}
+
+	return scalarString(raw)
+}
+
+// scalarString returns the string representation of a raw JSON string or
+// number, or an empty string if the value is of another type.
+func scalarString(raw json.RawMessage) string {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return ""
+	}
+
+	switch s := v.(type) {
+	case string:
+		return s
+	case json.Number:
+		return s.String()
+	default:
+		return ""
+	}
+}
+
+// lookupRaw returns the raw JSON value located at the given path inside the
+// given JSON document.
+func lookupRaw(doc []byte, path []string) (json.RawMessage, error) {
+	if !json.Valid(doc) {
+		return nil, errors.New("invalid JSON payload")
+	}
+
+	raw := json.RawMessage(doc)
+
+	for i, key := range path {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, fmt.Errorf("value at path %q is not an object", strings.Join(path[:i], "."))
+		}
+
+		var ok bool
+		if raw, ok = obj[key]; !ok {
+			return nil, fmt.Errorf("path %q not found", strings.Join(path[:i+1], "."))
+		}
+	}
+
+	return raw, nil
+}
+
+// splitPath splits a dot-separated path into its individual keys.
+func splitPath(p string) []string {
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, ".")
+}
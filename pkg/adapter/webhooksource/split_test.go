@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+)
+
+func TestBatchSplit(t *testing.T) {
+	tc := map[string]struct {
+		path    string
+		idField string
+		body    string
+
+		expectElems []batchElement
+		expectErr   string
+	}{
+		"root array": {
+			body: `[{"a":1}, {"a":2}]`,
+			expectElems: []batchElement{
+				{data: []byte(`{"a":1}`)},
+				{data: []byte(`{"a":2}`)},
+			},
+		},
+		"nested array with IDs": {
+			path:    "data.events",
+			idField: "meta.id",
+			body:    `{"data":{"events":[{"meta":{"id":"e1"}},{"meta":{"id":2}},{"meta":{}}]}}`,
+			expectElems: []batchElement{
+				{id: "e1", data: []byte(`{"meta":{"id":"e1"}}`)},
+				{id: "2", data: []byte(`{"meta":{"id":2}}`)},
+				{data: []byte(`{"meta":{}}`)},
+			},
+		},
+		"empty array": {
+			path:        "events",
+			body:        `{"events":[]}`,
+			expectElems: []batchElement{},
+		},
+		"invalid JSON": {
+			body:      `not json`,
+			expectErr: "invalid JSON payload",
+		},
+		"missing path": {
+			path:      "data.events",
+			body:      `{"data":{}}`,
+			expectErr: `path "data.events" not found`,
+		},
+		"path through non-object": {
+			path:      "data.events",
+			body:      `{"data":[]}`,
+			expectErr: `value at path "data" is not an object`,
+		},
+		"not an array": {
+			path:      "events",
+			body:      `{"events":{}}`,
+			expectErr: `value at path "events" is not an array`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			s, err := newBatchSplitter(c.path, c.idField, "Fail")
+			require.NoError(t, err)
+
+			elems, err := s.split([]byte(c.body))
+
+			if c.expectErr != "" {
+				assert.EqualError(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, elems, len(c.expectElems))
+			for i := range elems {
+				assert.Equal(t, c.expectElems[i].id, elems[i].id)
+				assert.JSONEq(t, string(c.expectElems[i].data), string(elems[i].data))
+			}
+		})
+	}
+}
+
+func TestWebhookBatch(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const body = `{"events":[{"id":"e1"},{"id":"e2"},{"id":"e3"}]}`
+
+	tc := map[string]struct {
+		partialFailure string
+		// number of events which delivery fails, starting with the
+		// second event
+		failures int
+
+		expectCode int
+		expectResp string
+	}{
+		"all delivered": {
+			partialFailure: "Fail",
+			expectCode:     http.StatusOK,
+		},
+		"partial failure": {
+			partialFailure: "Fail",
+			failures:       1,
+			expectCode:     http.StatusInternalServerError,
+			expectResp:     "Internal Server Error",
+		},
+		"partial failure ignored": {
+			partialFailure: "Ignore",
+			failures:       2,
+			expectCode:     http.StatusOK,
+		},
+		"partial failure reported": {
+			partialFailure: "MultiStatus",
+			failures:       1,
+			expectCode:     http.StatusMultiStatus,
+			expectResp: `{"results":[` +
+				`{"index":0,"id":"e1","status":200},` +
+				`{"index":1,"id":"e2","status":500,"error":"Internal Server Error"},` +
+				`{"index":2,"id":"e3","status":200}]}`,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			// results are consumed in order, missing results are ACKs
+			ceClient.Send_AppendResult(nil)
+			for i := 0; i < c.failures; i++ {
+				ceClient.Send_AppendResult(cehttp.NewResult(http.StatusInternalServerError, ""))
+			}
+
+			s, err := newBatchSplitter("events", "id", c.partialFailure)
+			require.NoError(t, err)
+
+			handler := &webhookHandler{
+				eventType:     tEventType,
+				eventSource:   tEventSource,
+				batchSplitter: s,
+
+				ceClient: ceClient,
+				logger:   logger,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", read(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, c.expectCode, rr.Code, "unexpected response code")
+
+			if c.partialFailure == "MultiStatus" {
+				assert.JSONEq(t, c.expectResp, rr.Body.String())
+			} else {
+				assert.Contains(t, rr.Body.String(), c.expectResp)
+			}
+
+			// the delivery of every event is attempted, regardless of failures
+			var sentIDs []string
+			for _, e := range ceClient.Sent() {
+				sentIDs = append(sentIDs, e.ID())
+				assert.Equal(t, tEventType, e.Type())
+			}
+			assert.Equal(t, []string{"e1", "e2", "e3"}, sentIDs)
+		})
+	}
+}
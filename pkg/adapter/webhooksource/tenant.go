@@ -0,0 +1,242 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/pkg/secret"
+)
+
+// tenantEnv returns the configuration of the handler serving the given source
+// in the multi-tenant adapter. The configuration is equivalent to the
+// environment the reconciler would propagate to a single-tenant adapter for
+// the same source, with values read from Secrets and ConfigMaps resolved.
+func tenantEnv(src *v1alpha1.WebhookSource, secrGetter secret.Getter,
+	cmClient coreclientv1.ConfigMapInterface) (*envAccessor, error) {
+
+	spec := &src.Spec
+
+	// defaults match the ones declared on the fields of envAccessor
+	env := &envAccessor{
+		EnvConfig: adapter.EnvConfig{
+			Namespace: src.Namespace,
+			Name:      src.Name,
+		},
+
+		EventType:   spec.EventType,
+		EventSource: src.AsEventSource(),
+
+		JWTJWKSCacheDuration: time.Hour,
+		AsyncQueueSize:       100,
+		AsyncRetries:         3,
+		AsyncBackoffDelay:    time.Second,
+		MultipartFiles:       string(v1alpha1.WebhookMultipartFilesIgnore),
+		BatchPartialFailure:  string(v1alpha1.WebhookPartialFailureFail),
+		IdempotencyTTL:       time.Hour,
+	}
+
+	if user := spec.BasicAuthUsername; user != nil {
+		env.BasicAuthUsername = *user
+	}
+
+	if passw := spec.BasicAuthPassword; passw != nil {
+		secrets, err := secrGetter.Get(*passw)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining basic auth password: %w", err)
+		}
+		env.BasicAuthPassword = secrets[0]
+	}
+
+	if passws := spec.AdditionalBasicAuthPasswords; len(passws) > 0 {
+		secrets, err := secrGetter.Get(passws...)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining additional basic auth passwords: %w", err)
+		}
+		env.BasicAuthAdditionalPasswords = secrets
+	}
+
+	if jwtAuth := spec.JWTAuth; jwtAuth != nil {
+		env.JWTIssuer = jwtAuth.Issuer
+		env.JWTAudiences = jwtAuth.Audiences
+		env.JWTAlgorithms = jwtAuth.Algorithms
+		env.JWTClaimsToExtensions = jwtAuth.ClaimsToExtensions
+
+		jwks := jwtAuth.JWKS
+
+		switch {
+		case jwks.ValueFromConfigMap != nil:
+			doc, err := configMapValue(cmClient, jwks.ValueFromConfigMap)
+			if err != nil {
+				return nil, fmt.Errorf("obtaining JWKS document: %w", err)
+			}
+			env.JWTJWKS = doc
+
+		case jwks.URL != nil:
+			env.JWTJWKSURL = jwks.URL.String()
+			if cd := jwks.CacheDuration; cd != nil {
+				env.JWTJWKSCacheDuration = time.Duration(*cd)
+			}
+
+		default:
+			secrets, err := secrGetter.Get(jwks.ValueFromField)
+			if err != nil {
+				return nil, fmt.Errorf("obtaining JWKS document: %w", err)
+			}
+			env.JWTJWKS = secrets[0]
+		}
+	}
+
+	if certAuth := spec.ClientCertAuth; certAuth != nil {
+		secrets, err := secrGetter.Get(certAuth.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining client certificate CA bundle: %w", err)
+		}
+		env.ClientCertCABundle = secrets[0]
+
+		if hdr := certAuth.Header; hdr != nil {
+			env.ClientCertHeader = *hdr
+		}
+		env.ClientCertTrustedProxyCIDRs = certAuth.TrustedProxyCIDRs
+		env.ClientCertAllowedSubjects = certAuth.AllowedSubjects
+		env.ClientCertAllowedSANs = certAuth.AllowedSANs
+	}
+
+	if async := spec.AsyncDelivery; async != nil {
+		env.AsyncDelivery = true
+		// the adapter runs one queue per source
+		env.AsyncQueueName = src.Namespace + "/" + src.Name
+		if qs := async.QueueSize; qs != nil {
+			env.AsyncQueueSize = int(*qs)
+		}
+		if r := async.Retries; r != nil {
+			env.AsyncRetries = int(*r)
+		}
+		if bd := async.BackoffDelay; bd != nil {
+			env.AsyncBackoffDelay = time.Duration(*bd)
+		}
+	}
+
+	lim := limiter.ConfigFromSpec(spec.RequestLimits)
+	env.EnvLimits = limiter.EnvLimits{
+		MaxBodySize:           lim.MaxBodySize,
+		MaxConcurrentRequests: lim.MaxConcurrentRequests,
+		RequestsPerSecond:     lim.RequestsPerSecond,
+		Burst:                 lim.Burst,
+		Key:                   string(lim.Key),
+		TrustedProxyCIDRs:     lim.TrustedProxyCIDRs,
+	}
+
+	if cors := spec.CORS; cors != nil {
+		env.CORSAllowedOrigins = cors.AllowedOrigins
+		env.CORSAllowedMethods = cors.AllowedMethods
+		env.CORSAllowedHeaders = cors.AllowedHeaders
+		if ma := cors.MaxAge; ma != nil {
+			env.CORSMaxAge = time.Duration(*ma)
+		}
+	}
+
+	if ipAllowlist := spec.IPAllowlist; ipAllowlist != nil {
+		env.AllowedCIDRs = ipAllowlist.AllowedCIDRs
+		env.TrustedProxyCIDRs = ipAllowlist.TrustedProxyCIDRs
+	}
+
+	if bd := spec.BodyDecoding; bd != nil {
+		env.BodyDecoding = true
+		if mf := bd.MultipartFiles; mf != nil {
+			env.MultipartFiles = string(*mf)
+		}
+	}
+
+	if bs := spec.BatchSplitting; bs != nil {
+		env.BatchSplitting = true
+		if p := bs.Path; p != nil {
+			env.BatchPath = *p
+		}
+		if f := bs.IDField; f != nil {
+			env.BatchIDField = *f
+		}
+		if pf := bs.PartialFailure; pf != nil {
+			env.BatchPartialFailure = string(*pf)
+		}
+	}
+
+	if idem := spec.Idempotency; idem != nil {
+		if kh := idem.KeyHeader; kh != nil {
+			env.IdempotencyKeyHeader = *kh
+		}
+		if kf := idem.KeyField; kf != nil {
+			env.IdempotencyKeyField = *kf
+		}
+		if ttl := idem.TTL; ttl != nil {
+			env.IdempotencyTTL = time.Duration(*ttl)
+		}
+	}
+
+	if sv := spec.SchemaValidation; sv != nil {
+		doc, err := configMapValue(cmClient, &sv.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("obtaining JSON Schema: %w", err)
+		}
+		env.SchemaDocument = doc
+
+		if uri := sv.DataSchema; uri != nil {
+			env.SchemaURI = uri.String()
+		}
+		if sink := sv.ErrorSink; sink != nil {
+			env.SchemaErrorSink = sink.String()
+		}
+	}
+
+	if resp := spec.Response; resp != nil {
+		if sc := resp.StatusCode; sc != nil {
+			env.ResponseStatusCode = int(*sc)
+		}
+		env.ResponseHeaders = resp.Headers
+		if b := resp.Body; b != nil {
+			env.ResponseBody = *b
+		}
+	}
+
+	return env, nil
+}
+
+// configMapValue returns the value referenced by the given ConfigMap key
+// selector.
+func configMapValue(cli coreclientv1.ConfigMapInterface, sel *corev1.ConfigMapKeySelector) (string, error) {
+	cm, err := cli.Get(context.Background(), sel.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("getting ConfigMap from cluster: %w", err)
+	}
+
+	val, ok := cm.Data[sel.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in ConfigMap %q", sel.Key, sel.Name)
+	}
+
+	return val, nil
+}
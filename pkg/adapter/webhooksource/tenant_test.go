@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakek8sclient "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/pkg/apis"
+
+	tmapis "github.com/triggermesh/knative-sources/pkg/apis"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestTenantEnv(t *testing.T) {
+	const (
+		jwks      = `{"keys":[]}`
+		jsonSchem = `{"type":"object"}`
+	)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: tNs,
+			Name:      "docs",
+		},
+		Data: map[string]string{
+			"jwks":   jwks,
+			"schema": jsonSchem,
+		},
+	}
+
+	cmSelector := func(key string) corev1.ConfigMapKeySelector {
+		return corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+			Key:                  key,
+		}
+	}
+
+	testCases := map[string]struct {
+		spec      func(*v1alpha1.WebhookSourceSpec)
+		expectEnv func(*envAccessor)
+		expectErr bool
+	}{
+		"Defaults": {
+			spec:      func(*v1alpha1.WebhookSourceSpec) {},
+			expectEnv: func(*envAccessor) {},
+		},
+		"Basic authentication": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				user := "user"
+				s.BasicAuthUsername = &user
+				s.BasicAuthPassword = &v1alpha1.ValueFromField{Value: "passw"}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.BasicAuthUsername = "user"
+				e.BasicAuthPassword = "fake" // value returned by mockedSecretGetter
+			},
+		},
+		"JWKS from ConfigMap": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				sel := cmSelector("jwks")
+				s.JWTAuth = &v1alpha1.WebhookJWTAuth{
+					Issuer:    "https://issuer.example.com",
+					Audiences: []string{"aud"},
+					JWKS: v1alpha1.WebhookJWKS{
+						ValueFromConfigMap: &sel,
+					},
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.JWTAudiences = []string{"aud"}
+				e.JWTIssuer = "https://issuer.example.com"
+				e.JWTJWKS = jwks
+			},
+		},
+		"JWKS from URL": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				cd := tmapis.Duration(time.Minute)
+				s.JWTAuth = &v1alpha1.WebhookJWTAuth{
+					Issuer: "https://issuer.example.com",
+					JWKS: v1alpha1.WebhookJWKS{
+						URL:           &apis.URL{Scheme: "https", Host: "example.com", Path: "/jwks.json"},
+						CacheDuration: &cd,
+					},
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.JWTIssuer = "https://issuer.example.com"
+				e.JWTJWKSURL = "https://example.com/jwks.json"
+				e.JWTJWKSCacheDuration = time.Minute
+			},
+		},
+		"Client certificate authentication": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				hdr := "Ssl-Client-Cert"
+				s.ClientCertAuth = &v1alpha1.WebhookClientCertAuth{
+					CABundle:          v1alpha1.ValueFromField{Value: "ca"},
+					Header:            &hdr,
+					TrustedProxyCIDRs: []string{"10.0.0.0/8"},
+					AllowedSubjects:   []string{"CN=partner.example.com"},
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.ClientCertCABundle = "fake" // value returned by mockedSecretGetter
+				e.ClientCertHeader = "Ssl-Client-Cert"
+				e.ClientCertTrustedProxyCIDRs = []string{"10.0.0.0/8"}
+				e.ClientCertAllowedSubjects = []string{"CN=partner.example.com"}
+			},
+		},
+		"Asynchronous delivery": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				qs := int32(10)
+				s.AsyncDelivery = &v1alpha1.WebhookAsyncDelivery{
+					QueueSize: &qs,
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.AsyncDelivery = true
+				e.AsyncQueueSize = 10
+				e.AsyncQueueName = tNs + "/" + tName
+			},
+		},
+		"CORS": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				ma := tmapis.Duration(time.Minute)
+				s.CORS = &v1alpha1.WebhookCORS{
+					AllowedOrigins: []string{"https://example.com"},
+					MaxAge:         &ma,
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.CORSAllowedOrigins = []string{"https://example.com"}
+				e.CORSMaxAge = time.Minute
+			},
+		},
+		"Schema validation": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				s.SchemaValidation = &v1alpha1.CustomSchemaValidation{
+					Schema: cmSelector("schema"),
+				}
+			},
+			expectEnv: func(e *envAccessor) {
+				e.SchemaDocument = jsonSchem
+			},
+		},
+		"Missing ConfigMap key": {
+			spec: func(s *v1alpha1.WebhookSourceSpec) {
+				s.SchemaValidation = &v1alpha1.CustomSchemaValidation{
+					Schema: cmSelector("missing"),
+				}
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			src := newEventSource()
+			tc.spec(&src.Spec)
+
+			cmCli := fakek8sclient.NewSimpleClientset(cm).CoreV1().ConfigMaps(tNs)
+
+			env, err := tenantEnv(src, &mockedSecretGetter{}, cmCli)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			expectEnv := &envAccessor{
+				EnvConfig: adapter.EnvConfig{
+					Namespace: tNs,
+					Name:      tName,
+				},
+				EventType:            src.Spec.EventType,
+				EventSource:          src.AsEventSource(),
+				JWTJWKSCacheDuration: time.Hour,
+				AsyncQueueSize:       100,
+				AsyncRetries:         3,
+				AsyncBackoffDelay:    time.Second,
+				MultipartFiles:       "Ignore",
+				BatchPartialFailure:  "Fail",
+				IdempotencyTTL:       time.Hour,
+			}
+			tc.expectEnv(expectEnv)
+
+			assert.Equal(t, expectEnv, env)
+		})
+	}
+}
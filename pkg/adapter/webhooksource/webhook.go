@@ -18,30 +18,69 @@ package webhooksource
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 	"knative.dev/pkg/logging"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/ipfilter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
 )
 
 const (
 	serverPort                uint16 = 8080
 	serverShutdownGracePeriod        = time.Second * 10
+
+	// suggested delay before clients retry a request that was rejected
+	// because the delivery queue was full
+	retryAfterSeconds = 1
 )
 
 type webhookHandler struct {
 	eventType   string
 	eventSource string
 
-	username string
-	password string
+	username  string
+	passwords *credentials.Set
+	// optional, takes precedence over basic auth
+	jwtAuth *jwtValidator
+	// optional, applies in addition to other authentication methods
+	clientCert *clientCertValidator
+	// optional, restricts the IP addresses requests are accepted from
+	ipFilter *ipfilter.Filter
+	// optional, restricts the size and rate of requests
+	limiter *limiter.Limiter
+	// optional, allows requests from browsers on other origins
+	cors *cors
+	// optional, converts form submissions and query strings to JSON
+	bodyDecoder *bodyDecoder
+	// optional, splits batched payloads into individual events
+	batchSplitter *batchSplitter
+	// optional, suppresses duplicate requests
+	idempotency *idempotency
+	// optional, validates the data of events against a JSON Schema
+	validator *schema.Validator
+	// optional, customizes the response to successful requests
+	response *response
 
 	ceClient cloudevents.Client
+	// optional, overrides the default target of the CloudEvents client
+	// when the handler serves one of many tenants
+	sink string
+	// optional, enables the asynchronous delivery of events
+	queue *delivery.Queue
 
 	logger *zap.SugaredLogger
 }
@@ -50,19 +89,41 @@ type webhookHandler struct {
 // Runs the server for receiving HTTP events until ctx gets cancelled.
 func (h *webhookHandler) Start(ctx context.Context) error {
 	m := http.NewServeMux()
-	m.HandleFunc("/", h.handleAll)
+	m.Handle("/", h.httpHandler())
 	m.HandleFunc("/health", healthCheckHandler)
 
 	s := &http.Server{
 		Addr:    fmt.Sprintf(":%d", serverPort),
 		Handler: m,
 	}
+	if h.queue != nil {
+		h.queue.Start()
+	}
+
+	return runHandler(ctx, s, h.queue)
+}
+
+// httpHandler returns the HTTP handler which receives webhook events, wrapped
+// with the optional IP filter, CORS handler and request limiter.
+func (h *webhookHandler) httpHandler() http.Handler {
+	var handler http.Handler = http.HandlerFunc(h.handleAll)
+	if h.limiter != nil {
+		handler = h.limiter.Handler(handler)
+	}
+	if h.cors != nil {
+		handler = h.cors.Handler(handler)
+	}
+	if h.ipFilter != nil {
+		handler = h.ipFilter.Handler(handler)
+	}
 
-	return runHandler(ctx, s)
+	return handler
 }
 
 // runHandler runs the HTTP event handler until ctx get cancelled.
-func runHandler(ctx context.Context, s *http.Server) error {
+// When a delivery queue is provided, it is drained after the server shut
+// down, within the same grace period.
+func runHandler(ctx context.Context, s *http.Server, q *delivery.Queue) error {
 	logging.FromContext(ctx).Info("Starting webhook event handler")
 
 	errCh := make(chan error)
@@ -88,6 +149,12 @@ func runHandler(ctx context.Context, s *http.Server) error {
 			return fmt.Errorf("during server shutdown: %w", err)
 		}
 
+		if q != nil {
+			if err := q.Drain(ctx); err != nil {
+				return fmt.Errorf("draining delivery queue: %w", err)
+			}
+		}
+
 		return handleServerError(<-errCh)
 
 	case err := <-errCh:
@@ -98,18 +165,45 @@ func runHandler(ctx context.Context, s *http.Server) error {
 // handleAll receives all webhook events at a single resource, it
 // is up to this function to parse event wrapper and dispatch.
 func (h *webhookHandler) handleAll(w http.ResponseWriter, r *http.Request) {
+	// OPTIONS requests query the capabilities of the webhook, they don't
+	// carry events. CORS preflight requests are answered before reaching
+	// this handler when CORS is enabled.
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
 	if r.Body == nil {
 		h.handleError(errors.New("request without body not supported"), http.StatusBadRequest, w)
 		return
 	}
 
-	if h.username != "" && h.password != "" {
+	var clientCert *x509.Certificate
+
+	if h.clientCert != nil {
+		var err error
+		if clientCert, err = h.clientCert.validate(r); err != nil {
+			h.handleError(fmt.Errorf("Invalid client certificate: %w", err), http.StatusUnauthorized, w)
+			return
+		}
+	}
+
+	var claims map[string]interface{}
+
+	if h.jwtAuth != nil {
+		var err error
+		if claims, err = h.jwtAuth.validate(r.Context(), r.Header); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			h.handleError(fmt.Errorf("Invalid token: %w", err), http.StatusUnauthorized, w)
+			return
+		}
+	} else if h.username != "" && !h.passwords.Empty() {
 		us, ps, ok := r.BasicAuth()
 		if !ok {
 			h.handleError(errors.New("Wrong authentication header"), http.StatusBadRequest, w)
 			return
 		}
-		if us != h.username || ps != h.password {
+		if us != h.username || !h.passwords.MatchValue(r.Context(), ps) {
 			h.handleError(errors.New("Credentials are not valid"), http.StatusUnauthorized, w)
 			return
 		}
@@ -122,25 +216,225 @@ func (h *webhookHandler) handleAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	contentType := r.Header.Get("Content-Type")
+
+	if h.bodyDecoder != nil {
+		data, decoded, err := h.bodyDecoder.decode(r, body)
+		if err != nil {
+			h.handleError(fmt.Errorf("could not decode request body: %w", err), http.StatusBadRequest, w)
+			return
+		}
+		if decoded {
+			body, contentType = data, cloudevents.ApplicationJSON
+		}
+	}
+
+	var idemKey string
+	if h.idempotency != nil {
+		idemKey = h.idempotency.key(r.Header, body)
+		// the key is reserved until the request is processed, so that
+		// concurrent duplicates are suppressed as well
+		if idemKey != "" && !h.idempotency.seen.Reserve(idemKey) {
+			h.logger.Debugw("Ignoring duplicate request", zap.String("key", idemKey))
+			h.respond(w, http.StatusOK)
+			return
+		}
+	}
+
 	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	if idemKey != "" {
+		event.SetID(idemKey)
+	}
 	event.SetType(h.eventType)
 	event.SetSource(h.eventSource)
 
-	if err := event.SetData(r.Header.Get("Content-Type"), body); err != nil {
+	if h.jwtAuth != nil {
+		h.jwtAuth.setExtensions(&event, claims)
+	}
+	if h.clientCert != nil {
+		h.clientCert.setExtensions(&event, clientCert)
+	}
+
+	if h.batchSplitter != nil {
+		h.handleBatch(w, event, body, idemKey)
+		return
+	}
+
+	if err := event.SetData(contentType, body); err != nil {
+		h.releaseKey(idemKey)
 		h.handleError(fmt.Errorf("failed to set event data: %w", err), http.StatusInternalServerError, w)
 		return
 	}
 
-	if result := h.ceClient.Send(context.Background(), event); !cloudevents.IsACK(result) {
-		h.handleError(fmt.Errorf("could not send Cloud Event: %w", result), http.StatusInternalServerError, w)
+	code, err := h.dispatch(event)
+	if err != nil {
+		h.releaseKey(idemKey)
+		h.handleDispatchError(err, code, w)
+		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	h.respond(w, code)
+}
+
+// batchResult is the delivery status of an event generated from a batched
+// payload.
+type batchResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleBatch splits a batched payload into individual events, which inherit
+// the attributes of the given event, and dispatches them.
+// Events which don't have an ID of their own derive it from the idempotency
+// key of the request, if set.
+func (h *webhookHandler) handleBatch(w http.ResponseWriter, tmpl cloudevents.Event, body []byte, idemKey string) {
+	elems, err := h.batchSplitter.split(body)
+	if err != nil {
+		h.releaseKey(idemKey)
+		h.handleError(fmt.Errorf("could not split batched payload: %w", err), http.StatusBadRequest, w)
+		return
+	}
+
+	results := make([]batchResult, len(elems))
+
+	var failed int
+	var firstErr error
+	var firstErrCode int
+
+	for i, elem := range elems {
+		event := tmpl.Clone()
+		switch {
+		case elem.id != "":
+			event.SetID(elem.id)
+		case idemKey != "":
+			event.SetID(idemKey + "-" + strconv.Itoa(i))
+		}
+
+		code := http.StatusInternalServerError
+		err := event.SetData(cloudevents.ApplicationJSON, elem.data)
+		if err != nil {
+			err = fmt.Errorf("failed to set event data: %w", err)
+		} else {
+			code, err = h.dispatch(event)
+		}
+
+		results[i] = batchResult{
+			Index:  i,
+			ID:     elem.id,
+			Status: code,
+		}
+
+		if err != nil {
+			h.logger.Errorw("Failed to deliver event from batch", zap.Int("index", i), zap.Error(err))
+
+			// error details are only disclosed in logs
+			results[i].Error = http.StatusText(code)
+
+			if failed == 0 {
+				firstErr, firstErrCode = err, code
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		h.releaseKey(idemKey)
+	}
+
+	successCode := http.StatusOK
+	if h.queue != nil {
+		successCode = http.StatusAccepted
+	}
+
+	switch h.batchSplitter.partialFailure {
+	case v1alpha1.WebhookPartialFailureMultiStatus:
+		w.Header().Set("Content-Type", cloudevents.ApplicationJSON)
+		w.WriteHeader(http.StatusMultiStatus)
+		if err := json.NewEncoder(w).Encode(struct {
+			Results []batchResult `json:"results"`
+		}{results}); err != nil {
+			h.logger.Errorw("Failed to write response", zap.Error(err))
+		}
+
+	case v1alpha1.WebhookPartialFailureIgnore:
+		h.respond(w, successCode)
+
+	default:
+		if failed > 0 {
+			h.handleDispatchError(fmt.Errorf("could not deliver %d of %d events: %w", failed, len(elems), firstErr),
+				firstErrCode, w)
+			return
+		}
+		h.respond(w, successCode)
+	}
+}
+
+// dispatch sends the given event, or schedules its asynchronous delivery when
+// a delivery queue is set, and returns the status code to respond with.
+// Events which fail schema validation are either rejected or sent to the
+// error sink.
+func (h *webhookHandler) dispatch(event cloudevents.Event) (int, error) {
+	ctx := context.Background()
+	if h.sink != "" {
+		ctx = cloudevents.ContextWithTarget(ctx, h.sink)
+	}
+
+	if h.validator != nil {
+		valid, err := h.validator.Check(ctx, &event)
+		switch {
+		case schema.IsValidationError(err):
+			return http.StatusBadRequest, err
+		case err != nil:
+			return http.StatusInternalServerError, err
+		case !valid:
+			return http.StatusOK, nil
+		}
+	}
+
+	if h.queue != nil {
+		switch err := h.queue.Enqueue(ctx, event); err {
+		case nil:
+			return http.StatusAccepted, nil
+		case delivery.ErrQueueFull, delivery.ErrQueueClosed:
+			return http.StatusServiceUnavailable, fmt.Errorf("could not accept Cloud Event: %w", err)
+		default:
+			return http.StatusInternalServerError, fmt.Errorf("could not accept Cloud Event: %w", err)
+		}
+	}
+
+	if result := h.ceClient.Send(ctx, event); !cloudevents.IsACK(result) {
+		return http.StatusInternalServerError, fmt.Errorf("could not send Cloud Event: %w", result)
+	}
+
+	return http.StatusOK, nil
+}
+
+// releaseKey forgets the given idempotency key of a request which couldn't be
+// processed, so that retries of that request are not suppressed.
+func (h *webhookHandler) releaseKey(idemKey string) {
+	if idemKey != "" {
+		h.idempotency.seen.Release(idemKey)
+	}
+}
+
+// handleDispatchError responds to a request which events could not be
+// dispatched. Clients are invited to retry later when the delivery queue is
+// full.
+func (h *webhookHandler) handleDispatchError(err error, code int, w http.ResponseWriter) {
+	if code == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+	}
+	h.handleError(err, code, w)
 }
 
+// handleError logs the given error and responds with a generic message, so
+// that internal details such as the address of the sink are not disclosed to
+// HTTP clients.
 func (h *webhookHandler) handleError(err error, code int, w http.ResponseWriter) {
-	h.logger.Error("An error ocurred", zap.Error(err))
-	http.Error(w, err.Error(), code)
+	h.logger.Errorw("An error ocurred", zap.Int("code", code), zap.Error(err))
+	http.Error(w, http.StatusText(code), code)
 }
 
 func healthCheckHandler(w http.ResponseWriter, _ *http.Request) {
@@ -17,6 +17,7 @@ limitations under the License.
 package webhooksource
 
 import (
+	"context"
 	"encoding/base64"
 	"io"
 	"net/http"
@@ -27,8 +28,17 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cloudeventst "github.com/cloudevents/sdk-go/v2/client/test"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	zapt "go.uber.org/zap/zaptest"
+
+	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/dedup"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/delivery"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 )
 
 const (
@@ -43,9 +53,11 @@ func TestWebhookEvent(t *testing.T) {
 	tc := map[string]struct {
 		body io.Reader
 
-		username string
-		password string
-		headers  map[string]string
+		username            string
+		password            string
+		additionalPasswords []string
+		jwtAuth             *jwtValidator
+		headers             map[string]string
 
 		expectedCode             int
 		expectedResponseContains string
@@ -55,7 +67,7 @@ func TestWebhookEvent(t *testing.T) {
 			body: nil,
 
 			expectedCode:             http.StatusBadRequest,
-			expectedResponseContains: "request without body not supported",
+			expectedResponseContains: "Bad Request",
 		},
 
 		"arbitrary message": {
@@ -71,7 +83,7 @@ func TestWebhookEvent(t *testing.T) {
 			password: "bar",
 
 			expectedCode:             http.StatusBadRequest,
-			expectedResponseContains: "Wrong authentication header",
+			expectedResponseContains: "Bad Request",
 		},
 
 		"basic auth wrong header": {
@@ -84,7 +96,7 @@ func TestWebhookEvent(t *testing.T) {
 			password: "bar",
 
 			expectedCode:             http.StatusBadRequest,
-			expectedResponseContains: "Wrong authentication header",
+			expectedResponseContains: "Bad Request",
 		},
 
 		"basic auth wrong creds": {
@@ -97,7 +109,7 @@ func TestWebhookEvent(t *testing.T) {
 			password: "bar",
 
 			expectedCode:             http.StatusUnauthorized,
-			expectedResponseContains: "Credentials are not valid",
+			expectedResponseContains: "Unauthorized",
 		},
 
 		"basic auth success": {
@@ -111,6 +123,45 @@ func TestWebhookEvent(t *testing.T) {
 
 			expectedCode: http.StatusOK,
 		},
+
+		"basic auth success with additional password": {
+			body: read("arbitrary message"),
+			headers: map[string]string{
+				"Authorization": basicAuth("foo", "bar"),
+			},
+
+			username:            "foo",
+			password:            "baz",
+			additionalPasswords: []string{"qux", "bar"},
+
+			expectedCode: http.StatusOK,
+		},
+
+		"basic auth only additional passwords": {
+			body: read("arbitrary message"),
+			headers: map[string]string{
+				"Authorization": basicAuth("foo", "bar"),
+			},
+
+			username:            "foo",
+			additionalPasswords: []string{"bar"},
+
+			expectedCode: http.StatusOK,
+		},
+
+		"jwt auth no token": {
+			body: read("arbitrary message"),
+			headers: map[string]string{
+				"Authorization": basicAuth("foo", "bar"),
+			},
+
+			username: "foo",
+			password: "bar",
+			jwtAuth:  &jwtValidator{},
+
+			expectedCode:             http.StatusUnauthorized,
+			expectedResponseContains: "Unauthorized",
+		},
 	}
 
 	for name, c := range tc {
@@ -123,7 +174,8 @@ func TestWebhookEvent(t *testing.T) {
 				eventType:   tEventType,
 				eventSource: tEventSource,
 				username:    c.username,
-				password:    c.password,
+				passwords:   credentials.New(c.password, c.additionalPasswords...),
+				jwtAuth:     c.jwtAuth,
 
 				ceClient: ceClient,
 				logger:   logger,
@@ -155,6 +207,306 @@ func TestWebhookEvent(t *testing.T) {
 	}
 }
 
+func TestWebhookAsyncDelivery(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	t.Run("event accepted", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		handler := &webhookHandler{
+			eventType:   tEventType,
+			eventSource: tEventSource,
+
+			ceClient: ceClient,
+			queue:    delivery.New(ceClient, delivery.Config{}, logger),
+			logger:   logger,
+		}
+		handler.queue.Start()
+
+		req, _ := http.NewRequest(http.MethodPost, "/", read("arbitrary message"))
+		rr := httptest.NewRecorder()
+		handler.handleAll(rr, req)
+
+		assert.Equal(t, http.StatusAccepted, rr.Code, "unexpected response code")
+
+		require.NoError(t, handler.queue.Drain(context.Background()))
+
+		sent := ceClient.Sent()
+		require.Len(t, sent, 1)
+		assert.Equal(t, "arbitrary message", string(sent[0].Data()), "event Data does not match")
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		// workers are not started, so the single slot of the queue
+		// remains occupied after the first request
+		handler := &webhookHandler{
+			eventType:   tEventType,
+			eventSource: tEventSource,
+
+			ceClient: ceClient,
+			queue:    delivery.New(ceClient, delivery.Config{Size: 1}, logger),
+			logger:   logger,
+		}
+
+		for _, expectCode := range []int{http.StatusAccepted, http.StatusServiceUnavailable} {
+			req, _ := http.NewRequest(http.MethodPost, "/", read("arbitrary message"))
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, expectCode, rr.Code, "unexpected response code")
+		}
+	})
+}
+
+func TestWebhookIdempotency(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	tc := map[string]struct {
+		idem    *idempotency
+		headers map[string]string
+		body    string
+
+		expectedID string
+	}{
+		"key from header": {
+			idem:       &idempotency{header: "Idempotency-Key"},
+			headers:    map[string]string{"Idempotency-Key": "abc-123"},
+			body:       `{"msg":"hello"}`,
+			expectedID: "abc-123",
+		},
+		"key from body field": {
+			idem:       &idempotency{field: []string{"meta", "delivery"}},
+			body:       `{"meta":{"delivery":42}}`,
+			expectedID: "42",
+		},
+		"empty header, key from body field": {
+			idem:       &idempotency{header: "Idempotency-Key", field: []string{"meta", "delivery"}},
+			headers:    map[string]string{"Idempotency-Key": ""},
+			body:       `{"meta":{"delivery":42}}`,
+			expectedID: "42",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			c.idem.seen = dedup.New(time.Minute)
+
+			handler := &webhookHandler{
+				eventType:   tEventType,
+				eventSource: tEventSource,
+				idempotency: c.idem,
+
+				ceClient: ceClient,
+				logger:   logger,
+			}
+
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequest(http.MethodPost, "/", read(c.body))
+				for k, v := range c.headers {
+					req.Header.Set(k, v)
+				}
+
+				rr := httptest.NewRecorder()
+				handler.handleAll(rr, req)
+
+				assert.Equal(t, http.StatusOK, rr.Code, "unexpected response code")
+			}
+
+			sent := ceClient.Sent()
+			require.Len(t, sent, 1, "duplicate request was not suppressed")
+			assert.Equal(t, c.expectedID, sent[0].ID())
+		})
+	}
+}
+
+func TestWebhookIdempotencyFailedDelivery(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	ceClient := adaptertest.NewTestClient()
+	ceClient.Send_AppendResult(cehttp.NewResult(http.StatusInternalServerError, "sink unavailable"))
+
+	handler := &webhookHandler{
+		eventType:   tEventType,
+		eventSource: tEventSource,
+		idempotency: &idempotency{
+			header: "Idempotency-Key",
+			seen:   dedup.New(time.Minute),
+		},
+
+		ceClient: ceClient,
+		logger:   logger,
+	}
+
+	expectCodes := []int{
+		http.StatusInternalServerError,
+		http.StatusOK, // retry after a failed delivery
+		http.StatusOK, // duplicate
+	}
+
+	for _, expectCode := range expectCodes {
+		req, _ := http.NewRequest(http.MethodPost, "/", read("arbitrary message"))
+		req.Header.Set("Idempotency-Key", "abc-123")
+
+		rr := httptest.NewRecorder()
+		handler.handleAll(rr, req)
+
+		assert.Equal(t, expectCode, rr.Code, "unexpected response code")
+	}
+
+	assert.Len(t, ceClient.Sent(), 2, "retry of a failed request was suppressed, or duplicate was sent")
+}
+
+func TestWebhookSchemaValidation(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	const jsonSchema = `{"type": "object", "required": ["msg"]}`
+	const schemaURI = "https://example.com/schema.json"
+
+	tc := map[string]struct {
+		body string
+
+		expectCode int
+		expectSent bool
+	}{
+		"valid data": {
+			body:       `{"msg":"hello"}`,
+			expectCode: http.StatusOK,
+			expectSent: true,
+		},
+		"invalid data": {
+			body:       `{"text":"hello"}`,
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+
+			v, err := schema.New(schema.Config{Schema: jsonSchema, URI: schemaURI}, ceClient)
+			require.NoError(t, err)
+
+			handler := &webhookHandler{
+				eventType:   tEventType,
+				eventSource: tEventSource,
+				validator:   v,
+
+				ceClient: ceClient,
+				logger:   logger,
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read(c.body))
+			req.Header.Set("Content-Type", cloudevents.ApplicationJSON)
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, c.expectCode, rr.Code, "unexpected response code")
+
+			sent := ceClient.Sent()
+			if !c.expectSent {
+				assert.Empty(t, sent, "invalid event was sent")
+				return
+			}
+			require.Len(t, sent, 1)
+			assert.Equal(t, schemaURI, sent[0].DataSchema())
+		})
+	}
+}
+
+func TestWebhookResponse(t *testing.T) {
+	logger := zapt.NewLogger(t).Sugar()
+
+	tc := map[string]struct {
+		response *response
+		// whether the delivery of the event fails
+		sendFails bool
+
+		expectCode    int
+		expectHeaders map[string]string
+		expectBody    string
+	}{
+		"default response": {
+			expectCode: http.StatusOK,
+		},
+		"custom response": {
+			response: &response{
+				statusCode: http.StatusAccepted,
+				headers:    map[string]string{"Content-Type": "application/json"},
+				body:       []byte(`{"ok":true}`),
+			},
+			expectCode:    http.StatusAccepted,
+			expectHeaders: map[string]string{"Content-Type": "application/json"},
+			expectBody:    `{"ok":true}`,
+		},
+		"custom body with default status code": {
+			response: &response{
+				body: []byte("ACK"),
+			},
+			expectCode: http.StatusOK,
+			expectBody: "ACK",
+		},
+		"sanitized error": {
+			response: &response{
+				statusCode: http.StatusAccepted,
+				body:       []byte("ACK"),
+			},
+			sendFails:  true,
+			expectCode: http.StatusInternalServerError,
+			expectBody: "Internal Server Error\n",
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			ceClient := adaptertest.NewTestClient()
+			if c.sendFails {
+				ceClient.Send_AppendResult(cehttp.NewResult(http.StatusInternalServerError, "sink at 10.0.0.1 failed"))
+			}
+
+			handler := &webhookHandler{
+				eventType:   tEventType,
+				eventSource: tEventSource,
+				response:    c.response,
+
+				ceClient: ceClient,
+				logger:   logger,
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, "/", read("arbitrary message"))
+
+			rr := httptest.NewRecorder()
+			handler.handleAll(rr, req)
+
+			assert.Equal(t, c.expectCode, rr.Code, "unexpected response code")
+			for k, v := range c.expectHeaders {
+				assert.Equal(t, v, rr.Header().Get(k), "unexpected value for header "+k)
+			}
+			assert.Equal(t, c.expectBody, rr.Body.String())
+		})
+	}
+}
+
+func TestNewResponse(t *testing.T) {
+	r, err := newResponse(&envAccessor{})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	r, err = newResponse(&envAccessor{ResponseStatusCode: http.StatusNoContent})
+	assert.NoError(t, err)
+	assert.Equal(t, &response{statusCode: http.StatusNoContent, body: []byte{}}, r)
+
+	_, err = newResponse(&envAccessor{ResponseStatusCode: http.StatusFound})
+	assert.EqualError(t, err, "status code 302 is not a success status code")
+
+	_, err = newResponse(&envAccessor{ResponseStatusCode: http.StatusNoContent, ResponseBody: "ok"})
+	assert.EqualError(t, err, "status code 204 doesn't allow a response body")
+}
+
 func read(s string) io.Reader {
 	return strings.NewReader(s)
 }
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -33,11 +35,14 @@ import (
 	"knative.dev/pkg/logging"
 
 	"github.com/triggermesh/knative-sources/pkg/adapter/common/env"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/limiter"
 	"github.com/triggermesh/knative-sources/pkg/adapter/common/router"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 	"github.com/triggermesh/knative-sources/pkg/adapter/zendesksource/handler"
 	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
 	"github.com/triggermesh/knative-sources/pkg/routing"
 	"github.com/triggermesh/knative-sources/pkg/secret"
+	"github.com/triggermesh/knative-sources/schemas"
 )
 
 // adapter implements the source's adapter.
@@ -49,6 +54,34 @@ type adapter struct {
 
 	// fields accessed during object reconciliation
 	router *router.Router
+
+	// tenants currently registered, indexed by URL path
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+// tenant is the handler serving a single ZendeskSource.
+type tenant struct {
+	handler http.Handler
+
+	// configuration the handler was created from
+	cfg *tenantConfig
+}
+
+// tenantConfig contains the settings the handler of a source is created
+// from, with values read from Secrets resolved.
+type tenantConfig struct {
+	eventSource string
+	sink        string
+
+	username            string
+	password            string
+	additionalPasswords []string
+
+	schemaValidation bool
+	errorSink        string
+
+	limits limiter.Config
 }
 
 // Check the interfaces adapter should implement.
@@ -78,7 +111,8 @@ func NewAdapter(component string) pkgadapter.AdapterConstructor {
 			ceClient:   ceClient,
 			secrGetter: secrGetter,
 
-			router: &router.Router{},
+			router:  &router.Router{},
+			tenants: make(map[string]*tenant),
 		}
 	}
 }
@@ -139,23 +173,98 @@ func (a *adapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // RegisterHandlerFor implements MTAdapter.
+// The handler of a source is only replaced when its configuration changed,
+// so that the state it accumulates, such as rate limits, survives periodic
+// reconciliations.
 func (a *adapter) RegisterHandlerFor(ctx context.Context, src *v1alpha1.ZendeskSource) error {
-	secrets, err := a.secrGetter.Get(src.Spec.WebhookPassword)
+	cfg, err := a.tenantConfig(src)
 	if err != nil {
-		return fmt.Errorf("obtaining webhook secret: %w", err)
+		return err
 	}
 
-	username := src.Spec.WebhookUsername
-	passw := secrets[0]
+	urlPath := routing.URLPath(src)
 
-	h := handler.New(src, a.logger, a.ceClient, username, passw)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if prev, hasPrev := a.tenants[urlPath]; hasPrev && reflect.DeepEqual(prev.cfg, cfg) {
+		return nil
+	}
+
+	var validator *schema.Validator
+	if cfg.schemaValidation {
+		vcfg := schema.Config{
+			Schema:    schemas.ZendeskTicket,
+			URI:       schemas.ZendeskTicketURI,
+			ErrorSink: cfg.errorSink,
+		}
+
+		if validator, err = schema.New(vcfg, a.ceClient); err != nil {
+			return fmt.Errorf("configuring schema validation: %w", err)
+		}
+	}
+
+	var h http.Handler = handler.New(src, a.logger, a.ceClient,
+		cfg.username, cfg.password, cfg.additionalPasswords, validator)
+
+	if cfg.limits.Enabled() {
+		l, err := limiter.New(cfg.limits)
+		if err != nil {
+			return fmt.Errorf("configuring request limits: %w", err)
+		}
+		h = l.Handler(h)
+	}
+
+	a.router.RegisterPath(urlPath, h)
+	a.tenants[urlPath] = &tenant{
+		handler: h,
+		cfg:     cfg,
+	}
 
-	a.router.RegisterPath(routing.URLPath(src), h)
 	return nil
 }
 
+// tenantConfig returns the configuration of the handler serving the given
+// source.
+func (a *adapter) tenantConfig(src *v1alpha1.ZendeskSource) (*tenantConfig, error) {
+	secrets, err := a.secrGetter.Get(src.Spec.WebhookPassword)
+	if err != nil {
+		return nil, fmt.Errorf("obtaining webhook secret: %w", err)
+	}
+
+	cfg := &tenantConfig{
+		eventSource: src.AsEventSource(),
+		sink:        src.Status.SinkURI.String(),
+		username:    src.Spec.WebhookUsername,
+		password:    secrets[0],
+		limits:      limiter.ConfigFromSpec(src.Spec.RequestLimits),
+	}
+
+	if refs := src.Spec.AdditionalWebhookPasswords; len(refs) > 0 {
+		if cfg.additionalPasswords, err = a.secrGetter.Get(refs...); err != nil {
+			return nil, fmt.Errorf("obtaining additional webhook secrets: %w", err)
+		}
+	}
+
+	if sv := src.Spec.SchemaValidation; sv != nil {
+		cfg.schemaValidation = true
+		if sv.ErrorSink != nil {
+			cfg.errorSink = sv.ErrorSink.String()
+		}
+	}
+
+	return cfg, nil
+}
+
 // DeregisterHandlerFor implements MTAdapter.
 func (a *adapter) DeregisterHandlerFor(ctx context.Context, src *v1alpha1.ZendeskSource) error {
-	a.router.DeregisterPath(routing.URLPath(src))
+	urlPath := routing.URLPath(src)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.router.DeregisterPath(urlPath)
+	delete(a.tenants, urlPath)
+
 	return nil
 }
@@ -16,10 +16,15 @@ limitations under the License.
 
 package handler
 
-// ticketCreated represents the data sent by Zendesk triggers when a ticket is created.
+import "github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+
+// ticketEvent represents the data sent by Zendesk triggers upon ticket events.
 // Only the fields accessed by the handler are declared here.
-type ticketCreated struct {
-	Ticket ticket `json:"ticket"`
+type ticketEvent struct {
+	// Absent from the data sent by triggers provisioned before the source
+	// supported multiple kinds of events, which all fire on ticket creation.
+	EventKind v1alpha1.ZendeskEventKind `json:"event_kind"`
+	Ticket    ticket                    `json:"ticket"`
 }
 
 type ticket struct {
@@ -33,6 +33,8 @@ import (
 
 	"knative.dev/pkg/logging/logkey"
 
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
 	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
 )
 
@@ -54,16 +56,27 @@ type Handler struct {
 	eventSrc string
 	sink     string
 
-	// base64 encoded username:password
-	base64UsrPass string
+	// base64 encoded username:password, for each valid password
+	base64UsrPass *credentials.Set
+
+	// optional, validates the data of events against a JSON Schema
+	validator *schema.Validator
 }
 
 // Check that Handler implements http.Handler.
 var _ http.Handler = (*Handler)(nil)
 
-// New returns an initialized Handler.
+// New returns an initialized Handler. The additional passwords and the
+// validator are optional.
 func New(src *v1alpha1.ZendeskSource, logger *zap.SugaredLogger, ceClient cloudevents.Client,
-	username, password string) *Handler {
+	username, password string, additionalPasswords []string, validator *schema.Validator) *Handler {
+
+	additionalUsrPass := make([]string, len(additionalPasswords))
+	for i, p := range additionalPasswords {
+		if p != "" {
+			additionalUsrPass[i] = encodeUsrPass(username, p)
+		}
+	}
 
 	return &Handler{
 		logger: logger.With(zap.String(logkey.Key, src.Namespace+"/"+src.Name)),
@@ -72,7 +85,10 @@ func New(src *v1alpha1.ZendeskSource, logger *zap.SugaredLogger, ceClient cloude
 		eventSrc: src.AsEventSource(),
 		sink:     src.Status.SinkURI.String(),
 
-		base64UsrPass: base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+		base64UsrPass: credentials.New(encodeUsrPass(username, password), additionalUsrPass...).
+			WithOwner(src.Namespace, src.Name, ""),
+
+		validator: validator,
 	}
 }
 
@@ -102,15 +118,21 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data := &ticketCreated{}
+	data := &ticketEvent{}
 	if err := json.Unmarshal(body, data); err != nil {
 		handleError("Failed to parse event data", err, http.StatusBadRequest, h.logger, w)
 		return
 	}
 
+	eventType, err := eventTypeOf(data.EventKind)
+	if err != nil {
+		handleError("Failed to determine event type", err, http.StatusBadRequest, h.logger, w)
+		return
+	}
+
 	event := cloudevents.NewEvent(cloudevents.VersionV1)
 
-	event.SetType(v1alpha1.ZendeskTicketCreatedEventType)
+	event.SetType(eventType)
 	event.SetSource(h.eventSrc)
 	event.SetSubject(strconv.Itoa(data.Ticket.ID))
 
@@ -123,6 +145,20 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.validator != nil {
+		valid, err := h.validator.Check(context.Background(), &event)
+		switch {
+		case schema.IsValidationError(err):
+			handleError("Failed to validate event data", err, http.StatusBadRequest, h.logger, w)
+			return
+		case err != nil:
+			handleError("Failed to handle invalid event", err, http.StatusInternalServerError, h.logger, w)
+			return
+		case !valid:
+			return
+		}
+	}
+
 	ctx := cloudevents.ContextWithTarget(context.Background(), h.sink)
 
 	if result := h.ceClient.Send(ctx, event); !cloudevents.IsACK(result) {
@@ -130,22 +166,42 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// eventTypeOf returns the CloudEvent type matching the given kind of ticket
+// event.
+func eventTypeOf(kind v1alpha1.ZendeskEventKind) (string, error) {
+	if kind == "" {
+		return v1alpha1.ZendeskTicketCreatedEventType, nil
+	}
+
+	t := v1alpha1.ZendeskEventType(kind)
+	if t == "" {
+		return "", fmt.Errorf("unknown event kind %q", kind)
+	}
+	return t, nil
+}
+
 // validateAuthHeader verifies that the request contains a valid Basic Auth header.
 // NOTE(antoineco): do not use Zendesk's "Test Target" action to troubleshoot a
 // Target, it always sends a blank password.
-func validateAuthHeader(r *http.Request, expectVal string) error {
+func validateAuthHeader(r *http.Request, expectVals *credentials.Set) error {
 	auth := r.Header.Get(headerAuthKey)
 	if !strings.HasPrefix(auth, headerAuthPrefix) {
 		return errors.New("incorrect header prefix")
 	}
 
-	if auth[len(headerAuthPrefix):] != expectVal {
+	if !expectVals.MatchValue(r.Context(), auth[len(headerAuthPrefix):]) {
 		return errors.New("invalid credentials")
 	}
 
 	return nil
 }
 
+// encodeUsrPass returns the base64 encoded value of a Basic Auth header for
+// the given username and password.
+func encodeUsrPass(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
 // handleError logs the given error and writes it to the given ResponseWriter.
 func handleError(msg string, err error, httpCode int, logger *zap.SugaredLogger, w http.ResponseWriter) {
 	logger.Errorw(msg, zap.Error(err))
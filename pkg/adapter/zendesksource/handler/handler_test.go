@@ -28,6 +28,11 @@ import (
 
 	adaptertest "knative.dev/eventing/pkg/adapter/v2/test"
 	logtesting "knative.dev/pkg/logging/testing"
+
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/credentials"
+	"github.com/triggermesh/knative-sources/pkg/adapter/common/schema"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/schemas"
 )
 
 const (
@@ -88,6 +93,91 @@ func TestHandler(t *testing.T) {
 		assert.Equal(t, tTicketType, event.Extensions()[ceExtTicketType])
 	})
 
+	t.Run("event kinds", func(t *testing.T) {
+		testCases := map[v1alpha1.ZendeskEventKind]struct {
+			expectCode int
+			expectType string
+		}{
+			v1alpha1.ZendeskTicketCreated: {
+				expectCode: http.StatusOK,
+				expectType: "com.zendesk.ticket.created",
+			},
+			v1alpha1.ZendeskTicketSolved: {
+				expectCode: http.StatusOK,
+				expectType: "com.zendesk.ticket.solved",
+			},
+			v1alpha1.ZendeskCommentAdded: {
+				expectCode: http.StatusOK,
+				expectType: "com.zendesk.ticket.comment.added",
+			},
+			"Unknown": {
+				expectCode: http.StatusBadRequest,
+			},
+		}
+
+		for kind, tc := range testCases {
+			//nolint:scopelint
+			t.Run(string(kind), func(t *testing.T) {
+				ceClient := adaptertest.NewTestClient()
+
+				h := newTestHandler(t)
+				h.ceClient = ceClient
+
+				msgBody := strings.NewReader(withEventKind(tTicketCreated, kind))
+
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, newPostRequest(t, msgBody))
+
+				assert.Equal(t, tc.expectCode, rr.Code)
+
+				sentEvents := ceClient.Sent()
+				if tc.expectType == "" {
+					assert.Empty(t, sentEvents)
+					return
+				}
+
+				require.Len(t, sentEvents, 1)
+				assert.Equal(t, tc.expectType, sentEvents[0].Type())
+			})
+		}
+	})
+
+	t.Run("schema validation", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		v, err := schema.New(schema.Config{
+			Schema: schemas.ZendeskTicket,
+			URI:    schemas.ZendeskTicketURI,
+		}, ceClient)
+		require.NoError(t, err)
+
+		h := newTestHandler(t)
+		h.ceClient = ceClient
+		h.validator = v
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, newPostRequest(t, strings.NewReader(tTicketCreated)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		// the schema applies to all kinds of ticket events
+		rr = httptest.NewRecorder()
+		h.ServeHTTP(rr, newPostRequest(t, strings.NewReader(withEventKind(tTicketCreated, v1alpha1.ZendeskCommentAdded))))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		h.ServeHTTP(rr, newPostRequest(t, strings.NewReader(`{"ticket":{"id":0},"unexpected":true}`)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		sentEvents := ceClient.Sent()
+		require.Len(t, sentEvents, 2)
+		for _, e := range sentEvents {
+			assert.Equal(t, schemas.ZendeskTicketURI, e.DataSchema())
+		}
+	})
+
 	t.Run("invalid auth header", func(t *testing.T) {
 		h := newTestHandler(t)
 
@@ -102,6 +192,19 @@ func TestHandler(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	})
 
+	t.Run("additional password", func(t *testing.T) {
+		ceClient := adaptertest.NewTestClient()
+
+		h := New(&v1alpha1.ZendeskSource{}, logtesting.TestLogger(t), ceClient,
+			"admin", "oldpass", []string{"", "hunter2"}, nil)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, newPostRequest(t, strings.NewReader(tTicketCreated)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Len(t, ceClient.Sent(), 1)
+	})
+
 	t.Run("invalid event", func(t *testing.T) {
 		ceClient := adaptertest.NewTestClient()
 
@@ -128,7 +231,7 @@ func newTestHandler(t *testing.T) *Handler {
 	return &Handler{
 		logger:        logtesting.TestLogger(t),
 		eventSrc:      tEventSrc,
-		base64UsrPass: tB64UsrPass,
+		base64UsrPass: credentials.New(tB64UsrPass),
 	}
 }
 
@@ -144,6 +247,12 @@ func newPostRequest(t *testing.T, body io.Reader) *http.Request {
 	return req
 }
 
+// withEventKind returns a copy of the given event data with the given kind of
+// ticket event.
+func withEventKind(data string, kind v1alpha1.ZendeskEventKind) string {
+	return strings.Replace(data, "{", `{"event_kind":"`+string(kind)+`",`, 1)
+}
+
 const tTicketCreated = `{
   "ticket": {
     "id": ` + tTicketID + `,
@@ -119,16 +119,41 @@ func TestReconcile(t *testing.T) {
 	testCases.Test(t, adaptesting.MakeFactory(ctor))
 }
 
+func TestRegisterHandlerForUnchangedSource(t *testing.T) {
+	src := newEventSource()
+
+	a := newTestAdapter(t, &mockedSecretGetter{})
+	require.NoError(t, a.RegisterHandlerFor(context.Background(), src))
+	h := a.tenants[tURLPath].handler
+
+	// e.g. periodic resync
+	require.NoError(t, a.RegisterHandlerFor(context.Background(), src.DeepCopy()))
+	assert.Same(t, h, a.tenants[tURLPath].handler, "Handler should have been kept")
+
+	changedSrc := src.DeepCopy()
+	maxReqs := int32(10)
+	changedSrc.Spec.RequestLimits = &v1alpha1.RequestLimits{MaxConcurrentRequests: &maxReqs}
+	require.NoError(t, a.RegisterHandlerFor(context.Background(), changedSrc))
+	assert.NotSame(t, h, a.tenants[tURLPath].handler, "Handler should have been replaced")
+}
+
+func TestDeregisterHandlerFor(t *testing.T) {
+	src := newEventSource()
+
+	a := newTestAdapter(t, &mockedSecretGetter{})
+	require.NoError(t, a.RegisterHandlerFor(context.Background(), src))
+
+	require.NoError(t, a.DeregisterHandlerFor(context.Background(), src))
+
+	assert.Equal(t, http.StatusNotFound, probeHandler(t, a, tURLPath).Code)
+	assert.Empty(t, a.tenants)
+}
+
 // reconcilerCtor returns a Ctor for a ZendeskSource Reconciler.
 func reconcilerCtor() adaptesting.Ctor {
 	return func(t *testing.T, ctx context.Context, tr *rt.TableRow, ls *adaptesting.Listers) controller.Reconciler {
 
-		a := &adapter{
-			logger:     logtesting.TestLogger(t),
-			ceClient:   adaptertest.NewTestClient(),
-			secrGetter: secretGetterFromContext(ctx),
-			router:     &router.Router{},
-		}
+		a := newTestAdapter(t, secretGetterFromContext(ctx))
 
 		// inject adapter into test data so that table tests can perform
 		// assertions on it
@@ -147,6 +172,17 @@ func reconcilerCtor() adaptesting.Ctor {
 	}
 }
 
+// newTestAdapter returns an adapter initialized with test clients.
+func newTestAdapter(t *testing.T, sg secret.Getter) *adapter {
+	return &adapter{
+		logger:     logtesting.TestLogger(t),
+		ceClient:   adaptertest.NewTestClient(),
+		secrGetter: sg,
+		router:     &router.Router{},
+		tenants:    make(map[string]*tenant),
+	}
+}
+
 const (
 	tNs      = "testns"
 	tName    = "test"
@@ -18,6 +18,9 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
@@ -39,3 +42,89 @@ type ValueFromField struct {
 	// +optional
 	ValueFromSecret *corev1.SecretKeySelector `json:"valueFromSecret,omitempty"`
 }
+
+// IPAllowlist restricts the IP addresses an event source accepts requests from.
+type IPAllowlist struct {
+	// IP ranges, in CIDR notation, requests are accepted from.
+	AllowedCIDRs []string `json:"allowedCIDRs"`
+	// IP ranges, in CIDR notation, of the proxies which are trusted to
+	// report the address of the original client in the Forwarded and
+	// X-Forwarded-For headers.
+	// +optional
+	TrustedProxyCIDRs []string `json:"trustedProxyCIDRs,omitempty"`
+}
+
+// RequestLimits restricts the size and rate of HTTP requests accepted by an
+// event source.
+type RequestLimits struct {
+	// Maximum size of request bodies, e.g. "1Mi". Larger requests are
+	// rejected with the status code 413 (Request Entity Too Large).
+	// +optional
+	MaxBodySize *resource.Quantity `json:"maxBodySize,omitempty"`
+
+	// Maximum number of requests processed concurrently. Excess requests
+	// are rejected with the status code 503 (Service Unavailable).
+	// +optional
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
+
+	// Token-bucket rate limiting of requests. Excess requests are rejected
+	// with the status code 429 (Too Many Requests).
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+}
+
+// RateLimit defines a token-bucket rate limit.
+type RateLimit struct {
+	// Sustained number of requests accepted per second.
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// Maximum number of requests accepted in a single burst.
+	// Defaults to the value of RequestsPerSecond.
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+
+	// Entity the rate limit applies to. Defaults to Source.
+	// +optional
+	Key *RateLimitKey `json:"key,omitempty"`
+
+	// IP ranges, in CIDR notation, of the proxies which are trusted to
+	// report the address of the original client in the Forwarded and
+	// X-Forwarded-For headers. Only applies to the ClientIP key.
+	// +optional
+	TrustedProxyCIDRs []string `json:"trustedProxyCIDRs,omitempty"`
+}
+
+// RateLimitKey is the entity a rate limit applies to.
+type RateLimitKey string
+
+// Accepted rate limit keys.
+const (
+	// All requests to the event source share the same limit.
+	RateLimitKeySource RateLimitKey = "Source"
+	// Each client IP address has its own limit.
+	RateLimitKeyClientIP RateLimitKey = "ClientIP"
+)
+
+// SchemaValidation enables the validation of the data of events against the
+// built-in JSON Schema of an event source.
+type SchemaValidation struct {
+	// URI of a sink events which data fails validation are sent to, with
+	// the reason of the failure in the "validationerror" extension. When
+	// not set, such events are rejected with the status code 400 (Bad
+	// Request).
+	// +optional
+	ErrorSink *apis.URL `json:"errorSink,omitempty"`
+}
+
+// CustomSchemaValidation enables the validation of the data of events against
+// a user-provided JSON Schema.
+type CustomSchemaValidation struct {
+	// JSON Schema document from a Kubernetes ConfigMap.
+	Schema corev1.ConfigMapKeySelector `json:"schema"`
+
+	// URI of the schema, set as the dataschema attribute of valid events.
+	// +optional
+	DataSchema *apis.URL `json:"dataSchema,omitempty"`
+
+	SchemaValidation `json:",inline"`
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// crdManifestsGlob matches the manifests of the CRDs of all sources.
+const crdManifestsGlob = "../../../../config/300-*.yaml"
+
+// TestCRDSchemasAreStructural ensures the OpenAPI schemas of all CRDs are
+// structural, otherwise they are rejected by the Kubernetes API.
+// https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#specifying-a-structural-schema
+func TestCRDSchemasAreStructural(t *testing.T) {
+	manifests, err := filepath.Glob(crdManifestsGlob)
+	require.NoError(t, err)
+	require.NotEmpty(t, manifests, "No CRD manifest found")
+
+	for _, m := range manifests {
+		//nolint:scopelint
+		t.Run(filepath.Base(m), func(t *testing.T) {
+			data, err := ioutil.ReadFile(m)
+			require.NoError(t, err)
+
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			// strict decoding catches misplaced schema attributes
+			require.NoError(t, yaml.UnmarshalStrict(data, crd))
+
+			for _, v := range crd.Spec.Versions {
+				require.NotNil(t, v.Schema, "Version %s has no schema", v.Name)
+
+				schema := &apiextensions.JSONSchemaProps{}
+				err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+					v.Schema.OpenAPIV3Schema, schema, nil)
+				require.NoError(t, err)
+
+				s, err := structuralschema.NewStructural(schema)
+				require.NoError(t, err, "Schema of version %s isn't structural", v.Name)
+
+				fldPath := field.NewPath("spec", "versions").Key(v.Name).Child("schema", "openAPIV3Schema")
+				errs := structuralschema.ValidateStructural(fldPath, s)
+				assert.Empty(t, errs.ToAggregate(), "Schema of version %s isn't structural", v.Name)
+			}
+		})
+	}
+}
@@ -21,10 +21,35 @@ limitations under the License.
 package v1alpha1
 
 import (
+	pkgapis "github.com/triggermesh/knative-sources/pkg/apis"
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	apis "knative.dev/pkg/apis"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomSchemaValidation) DeepCopyInto(out *CustomSchemaValidation) {
+	*out = *in
+	in.Schema.DeepCopyInto(&out.Schema)
+	if in.DataSchema != nil {
+		in, out := &in.DataSchema, &out.DataSchema
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	in.SchemaValidation.DeepCopyInto(&out.SchemaValidation)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomSchemaValidation.
+func (in *CustomSchemaValidation) DeepCopy() *CustomSchemaValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomSchemaValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventSourceStatus) DeepCopyInto(out *EventSourceStatus) {
 	*out = *in
@@ -141,6 +166,11 @@ func (in *HTTPPollerSourceSpec) DeepCopyInto(out *HTTPPollerSourceSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.SchemaValidation != nil {
+		in, out := &in.SchemaValidation, &out.SchemaValidation
+		*out = new(CustomSchemaValidation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -154,6 +184,343 @@ func (in *HTTPPollerSourceSpec) DeepCopy() *HTTPPollerSourceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAllowlist) DeepCopyInto(out *IPAllowlist) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedProxyCIDRs != nil {
+		in, out := &in.TrustedProxyCIDRs, &out.TrustedProxyCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAllowlist.
+func (in *IPAllowlist) DeepCopy() *IPAllowlist {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAllowlist)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(RateLimitKey)
+		**out = **in
+	}
+	if in.TrustedProxyCIDRs != nil {
+		in, out := &in.TrustedProxyCIDRs, &out.TrustedProxyCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestLimits) DeepCopyInto(out *RequestLimits) {
+	*out = *in
+	if in.MaxBodySize != nil {
+		in, out := &in.MaxBodySize, &out.MaxBodySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestLimits.
+func (in *RequestLimits) DeepCopy() *RequestLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaValidation) DeepCopyInto(out *SchemaValidation) {
+	*out = *in
+	if in.ErrorSink != nil {
+		in, out := &in.ErrorSink, &out.ErrorSink
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaValidation.
+func (in *SchemaValidation) DeepCopy() *SchemaValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackApp) DeepCopyInto(out *SlackApp) {
+	*out = *in
+	in.SigningSecret.DeepCopyInto(&out.SigningSecret)
+	if in.AdditionalSigningSecrets != nil {
+		in, out := &in.AdditionalSigningSecrets, &out.AdditionalSigningSecrets
+		*out = make([]ValueFromField, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventTypePrefix != nil {
+		in, out := &in.EventTypePrefix, &out.EventTypePrefix
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackApp.
+func (in *SlackApp) DeepCopy() *SlackApp {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackApp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackAsyncDelivery) DeepCopyInto(out *SlackAsyncDelivery) {
+	*out = *in
+	if in.QueueSize != nil {
+		in, out := &in.QueueSize, &out.QueueSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffDelay != nil {
+		in, out := &in.BackoffDelay, &out.BackoffDelay
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	if in.DeadLetterSink != nil {
+		in, out := &in.DeadLetterSink, &out.DeadLetterSink
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackAsyncDelivery.
+func (in *SlackAsyncDelivery) DeepCopy() *SlackAsyncDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackAsyncDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackDeduplication) DeepCopyInto(out *SlackDeduplication) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackDeduplication.
+func (in *SlackDeduplication) DeepCopy() *SlackDeduplication {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackDeduplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackEnrichment) DeepCopyInto(out *SlackEnrichment) {
+	*out = *in
+	in.BotToken.DeepCopyInto(&out.BotToken)
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	if in.APIURL != nil {
+		in, out := &in.APIURL, &out.APIURL
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackEnrichment.
+func (in *SlackEnrichment) DeepCopy() *SlackEnrichment {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackEnrichment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackEventFilters) DeepCopyInto(out *SlackEventFilters) {
+	*out = *in
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedSubtypes != nil {
+		in, out := &in.ExcludedSubtypes, &out.ExcludedSubtypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeBots != nil {
+		in, out := &in.ExcludeBots, &out.ExcludeBots
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludeSelf != nil {
+		in, out := &in.ExcludeSelf, &out.ExcludeSelf
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackEventFilters.
+func (in *SlackEventFilters) DeepCopy() *SlackEventFilters {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackEventFilters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackEventTypes) DeepCopyInto(out *SlackEventTypes) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(SlackEventTypeMode)
+		**out = **in
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackEventTypes.
+func (in *SlackEventTypes) DeepCopy() *SlackEventTypes {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackEventTypes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackInteractivity) DeepCopyInto(out *SlackInteractivity) {
+	*out = *in
+	if in.ResponseMode != nil {
+		in, out := &in.ResponseMode, &out.ResponseMode
+		*out = new(SlackResponseMode)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackInteractivity.
+func (in *SlackInteractivity) DeepCopy() *SlackInteractivity {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackInteractivity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackSocketMode) DeepCopyInto(out *SlackSocketMode) {
+	*out = *in
+	in.AppToken.DeepCopyInto(&out.AppToken)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackSocketMode.
+func (in *SlackSocketMode) DeepCopy() *SlackSocketMode {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackSocketMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SlackSource) DeepCopyInto(out *SlackSource) {
 	*out = *in
@@ -224,11 +591,75 @@ func (in *SlackSourceSpec) DeepCopyInto(out *SlackSourceSpec) {
 		*out = new(ValueFromField)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalSigningSecrets != nil {
+		in, out := &in.AdditionalSigningSecrets, &out.AdditionalSigningSecrets
+		*out = make([]ValueFromField, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.AppID != nil {
 		in, out := &in.AppID, &out.AppID
 		*out = new(string)
 		**out = **in
 	}
+	if in.SocketMode != nil {
+		in, out := &in.SocketMode, &out.SocketMode
+		*out = new(SlackSocketMode)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Apps != nil {
+		in, out := &in.Apps, &out.Apps
+		*out = make([]SlackApp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IPAllowlist != nil {
+		in, out := &in.IPAllowlist, &out.IPAllowlist
+		*out = new(IPAllowlist)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = new(SlackEventFilters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Enrichment != nil {
+		in, out := &in.Enrichment, &out.Enrichment
+		*out = new(SlackEnrichment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deduplication != nil {
+		in, out := &in.Deduplication, &out.Deduplication
+		*out = new(SlackDeduplication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AsyncDelivery != nil {
+		in, out := &in.AsyncDelivery, &out.AsyncDelivery
+		*out = new(SlackAsyncDelivery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestLimits != nil {
+		in, out := &in.RequestLimits, &out.RequestLimits
+		*out = new(RequestLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = new(SlackEventTypes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Interactivity != nil {
+		in, out := &in.Interactivity, &out.Interactivity
+		*out = new(SlackInteractivity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaValidation != nil {
+		in, out := &in.SchemaValidation, &out.SchemaValidation
+		*out = new(SchemaValidation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -263,6 +694,292 @@ func (in *ValueFromField) DeepCopy() *ValueFromField {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookAsyncDelivery) DeepCopyInto(out *WebhookAsyncDelivery) {
+	*out = *in
+	if in.QueueSize != nil {
+		in, out := &in.QueueSize, &out.QueueSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffDelay != nil {
+		in, out := &in.BackoffDelay, &out.BackoffDelay
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookAsyncDelivery.
+func (in *WebhookAsyncDelivery) DeepCopy() *WebhookAsyncDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookAsyncDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookBatchSplitting) DeepCopyInto(out *WebhookBatchSplitting) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
+		**out = **in
+	}
+	if in.IDField != nil {
+		in, out := &in.IDField, &out.IDField
+		*out = new(string)
+		**out = **in
+	}
+	if in.PartialFailure != nil {
+		in, out := &in.PartialFailure, &out.PartialFailure
+		*out = new(WebhookPartialFailurePolicy)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookBatchSplitting.
+func (in *WebhookBatchSplitting) DeepCopy() *WebhookBatchSplitting {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookBatchSplitting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookBodyDecoding) DeepCopyInto(out *WebhookBodyDecoding) {
+	*out = *in
+	if in.MultipartFiles != nil {
+		in, out := &in.MultipartFiles, &out.MultipartFiles
+		*out = new(WebhookMultipartFilesMode)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookBodyDecoding.
+func (in *WebhookBodyDecoding) DeepCopy() *WebhookBodyDecoding {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookBodyDecoding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookCORS) DeepCopyInto(out *WebhookCORS) {
+	*out = *in
+	if in.AllowedOrigins != nil {
+		in, out := &in.AllowedOrigins, &out.AllowedOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedMethods != nil {
+		in, out := &in.AllowedMethods, &out.AllowedMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedHeaders != nil {
+		in, out := &in.AllowedHeaders, &out.AllowedHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookCORS.
+func (in *WebhookCORS) DeepCopy() *WebhookCORS {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookCORS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookClientCertAuth) DeepCopyInto(out *WebhookClientCertAuth) {
+	*out = *in
+	in.CABundle.DeepCopyInto(&out.CABundle)
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrustedProxyCIDRs != nil {
+		in, out := &in.TrustedProxyCIDRs, &out.TrustedProxyCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedSubjects != nil {
+		in, out := &in.AllowedSubjects, &out.AllowedSubjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedSANs != nil {
+		in, out := &in.AllowedSANs, &out.AllowedSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookClientCertAuth.
+func (in *WebhookClientCertAuth) DeepCopy() *WebhookClientCertAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookClientCertAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookIdempotency) DeepCopyInto(out *WebhookIdempotency) {
+	*out = *in
+	if in.KeyHeader != nil {
+		in, out := &in.KeyHeader, &out.KeyHeader
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyField != nil {
+		in, out := &in.KeyField, &out.KeyField
+		*out = new(string)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookIdempotency.
+func (in *WebhookIdempotency) DeepCopy() *WebhookIdempotency {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookIdempotency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookJWKS) DeepCopyInto(out *WebhookJWKS) {
+	*out = *in
+	in.ValueFromField.DeepCopyInto(&out.ValueFromField)
+	if in.ValueFromConfigMap != nil {
+		in, out := &in.ValueFromConfigMap, &out.ValueFromConfigMap
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(apis.URL)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CacheDuration != nil {
+		in, out := &in.CacheDuration, &out.CacheDuration
+		*out = new(pkgapis.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookJWKS.
+func (in *WebhookJWKS) DeepCopy() *WebhookJWKS {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookJWKS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookJWTAuth) DeepCopyInto(out *WebhookJWTAuth) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Algorithms != nil {
+		in, out := &in.Algorithms, &out.Algorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.JWKS.DeepCopyInto(&out.JWKS)
+	if in.ClaimsToExtensions != nil {
+		in, out := &in.ClaimsToExtensions, &out.ClaimsToExtensions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookJWTAuth.
+func (in *WebhookJWTAuth) DeepCopy() *WebhookJWTAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookJWTAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookResponse) DeepCopyInto(out *WebhookResponse) {
+	*out = *in
+	if in.StatusCode != nil {
+		in, out := &in.StatusCode, &out.StatusCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Body != nil {
+		in, out := &in.Body, &out.Body
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookResponse.
+func (in *WebhookResponse) DeepCopy() *WebhookResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookSource) DeepCopyInto(out *WebhookSource) {
 	*out = *in
@@ -343,6 +1060,68 @@ func (in *WebhookSourceSpec) DeepCopyInto(out *WebhookSourceSpec) {
 		*out = new(ValueFromField)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalBasicAuthPasswords != nil {
+		in, out := &in.AdditionalBasicAuthPasswords, &out.AdditionalBasicAuthPasswords
+		*out = make([]ValueFromField, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JWTAuth != nil {
+		in, out := &in.JWTAuth, &out.JWTAuth
+		*out = new(WebhookJWTAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertAuth != nil {
+		in, out := &in.ClientCertAuth, &out.ClientCertAuth
+		*out = new(WebhookClientCertAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AsyncDelivery != nil {
+		in, out := &in.AsyncDelivery, &out.AsyncDelivery
+		*out = new(WebhookAsyncDelivery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IPAllowlist != nil {
+		in, out := &in.IPAllowlist, &out.IPAllowlist
+		*out = new(IPAllowlist)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CORS != nil {
+		in, out := &in.CORS, &out.CORS
+		*out = new(WebhookCORS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BodyDecoding != nil {
+		in, out := &in.BodyDecoding, &out.BodyDecoding
+		*out = new(WebhookBodyDecoding)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BatchSplitting != nil {
+		in, out := &in.BatchSplitting, &out.BatchSplitting
+		*out = new(WebhookBatchSplitting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Idempotency != nil {
+		in, out := &in.Idempotency, &out.Idempotency
+		*out = new(WebhookIdempotency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestLimits != nil {
+		in, out := &in.RequestLimits, &out.RequestLimits
+		*out = new(RequestLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaValidation != nil {
+		in, out := &in.SchemaValidation, &out.SchemaValidation
+		*out = new(CustomSchemaValidation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(WebhookResponse)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -423,6 +1202,33 @@ func (in *ZendeskSourceSpec) DeepCopyInto(out *ZendeskSourceSpec) {
 	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
 	in.Token.DeepCopyInto(&out.Token)
 	in.WebhookPassword.DeepCopyInto(&out.WebhookPassword)
+	if in.AdditionalWebhookPasswords != nil {
+		in, out := &in.AdditionalWebhookPasswords, &out.AdditionalWebhookPasswords
+		*out = make([]ValueFromField, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventKinds != nil {
+		in, out := &in.EventKinds, &out.EventKinds
+		*out = make([]ZendeskEventKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = new(ZendeskTriggerConditions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestLimits != nil {
+		in, out := &in.RequestLimits, &out.RequestLimits
+		*out = new(RequestLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaValidation != nil {
+		in, out := &in.SchemaValidation, &out.SchemaValidation
+		*out = new(SchemaValidation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -452,3 +1258,54 @@ func (in *ZendeskSourceStatus) DeepCopy() *ZendeskSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZendeskTriggerCondition) DeepCopyInto(out *ZendeskTriggerCondition) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZendeskTriggerCondition.
+func (in *ZendeskTriggerCondition) DeepCopy() *ZendeskTriggerCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ZendeskTriggerCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZendeskTriggerConditions) DeepCopyInto(out *ZendeskTriggerConditions) {
+	*out = *in
+	if in.All != nil {
+		in, out := &in.All, &out.All
+		*out = make([]ZendeskTriggerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Any != nil {
+		in, out := &in.Any, &out.Any
+		*out = make([]ZendeskTriggerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZendeskTriggerConditions.
+func (in *ZendeskTriggerConditions) DeepCopy() *ZendeskTriggerConditions {
+	if in == nil {
+		return nil
+	}
+	out := new(ZendeskTriggerConditions)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -95,6 +95,11 @@ type HTTPPollerSourceSpec struct {
 	// Duration which defines how often the HTTP/S endpoint should be polled.
 	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
 	Interval tmapis.Duration `json:"interval"`
+
+	// Validates the data of events against a JSON Schema. Responses which
+	// fail validation are discarded, unless an error sink is set.
+	// +optional
+	SchemaValidation *CustomSchemaValidation `json:"schemaValidation,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	pkgapis "knative.dev/pkg/apis"
@@ -56,14 +58,99 @@ func (s *SlackSource) AsEventSource() string {
 	return "slack/" + s.Name
 }
 
+// SlackEventTypeRoot is the common prefix of the types of all events
+// originating from Slack, which can be replaced on a per-app basis.
+const SlackEventTypeRoot = "com.slack"
+
 // Supported event types
 const (
 	SlackGenericEventType = "com.slack.events"
+	SlackCommandEventType = "com.slack.commands"
+
+	// Notifies that the app exceeded the rate limit of the Events API.
+	SlackAppRateLimitedEventType = "com.slack.app_rate_limited"
+
+	// Interactions with interactive components are typed after the kind of
+	// interaction, e.g. "com.slack.interactions.block_actions".
+	SlackInteractionEventTypePrefix = "com.slack.interactions."
+
+	SlackBlockActionsEventType   = SlackInteractionEventTypePrefix + "block_actions"
+	SlackMessageActionEventType  = SlackInteractionEventTypePrefix + "message_action"
+	SlackShortcutEventType       = SlackInteractionEventTypePrefix + "shortcut"
+	SlackViewSubmissionEventType = SlackInteractionEventTypePrefix + "view_submission"
+	SlackViewClosedEventType     = SlackInteractionEventTypePrefix + "view_closed"
 )
 
+// SlackEventType returns the specific CloudEvent type of events received from
+// the Events API with the given Slack event type and optional subtype.
+func SlackEventType(typ, subtype string) string {
+	if subtype == "" {
+		return SlackGenericEventType + "." + typ
+	}
+	return SlackGenericEventType + "." + typ + "." + subtype
+}
+
+// PrefixSlackEventType returns the given event type with its root replaced by
+// the given prefix, or unchanged if the prefix is empty.
+func PrefixSlackEventType(prefix, typ string) string {
+	if prefix == "" {
+		return typ
+	}
+	return prefix + strings.TrimPrefix(typ, SlackEventTypeRoot)
+}
+
 // GetEventTypes implements EventSource.
-func (*SlackSource) GetEventTypes() []string {
-	return []string{
-		SlackGenericEventType,
+func (s *SlackSource) GetEventTypes() []string {
+	types := s.slackEventTypes()
+
+	if len(s.Spec.Apps) == 0 {
+		return types
 	}
+
+	// each distinct prefix yields its own set of types, apps without a
+	// prefix use the default ones
+	var prefixedTypes []string
+	seen := make(map[string]bool, len(s.Spec.Apps))
+
+	for _, app := range s.Spec.Apps {
+		var prefix string
+		if app.EventTypePrefix != nil {
+			prefix = *app.EventTypePrefix
+		}
+
+		if seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+
+		for _, t := range types {
+			prefixedTypes = append(prefixedTypes, PrefixSlackEventType(prefix, t))
+		}
+	}
+
+	return prefixedTypes
+}
+
+// slackEventTypes returns the types of events originating from Slack, before
+// any prefix is applied.
+func (s *SlackSource) slackEventTypes() []string {
+	var types []string
+
+	if et := s.Spec.EventTypes; et != nil && et.Mode != nil && *et.Mode == SlackEventTypeSpecific {
+		for _, sub := range et.Subscriptions {
+			types = append(types, SlackGenericEventType+"."+sub)
+		}
+	} else {
+		types = append(types, SlackGenericEventType)
+	}
+
+	return append(types,
+		SlackAppRateLimitedEventType,
+		SlackCommandEventType,
+		SlackBlockActionsEventType,
+		SlackMessageActionEventType,
+		SlackShortcutEventType,
+		SlackViewSubmissionEventType,
+		SlackViewClosedEventType,
+	)
 }
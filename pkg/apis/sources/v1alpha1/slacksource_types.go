@@ -20,7 +20,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	tmapis "github.com/triggermesh/knative-sources/pkg/apis"
 )
 
 // +genclient
@@ -57,13 +60,255 @@ type SlackSourceSpec struct {
 	// +optional
 	SigningSecret *ValueFromField `json:"signingSecret,omitempty"`
 
+	// Additional signing secrets accepted to authenticate callbacks, e.g. the
+	// previous secret while it is being rotated. A request is authenticated
+	// if its signature matches any of the accepted secrets.
+	// +optional
+	AdditionalSigningSecrets []ValueFromField `json:"additionalSigningSecrets,omitempty"`
+
 	// AppID identifies the Slack application generating this event.
 	// It helps identifying the App sourcing events when multiple Slack
 	// applications shared an endpoint. See: https://api.slack.com/events-api
 	// +optional
 	AppID *string `json:"appID,omitempty"`
+
+	// Receives events over a WebSocket connection opened by the source
+	// instead of a public HTTP endpoint. Requests are authenticated by the
+	// connection itself, signing secrets and IP allowlists don't apply.
+	// See: https://api.slack.com/apis/connections/socket
+	// +optional
+	SocketMode *SlackSocketMode `json:"socketMode,omitempty"`
+
+	// Slack apps which events are accepted, for sources shared by several
+	// apps, possibly installed in different workspaces. Requests are
+	// authenticated with the signing secret of the app they originate from.
+	// When set, AppID, SigningSecret and AdditionalSigningSecrets are
+	// ignored.
+	// +optional
+	Apps []SlackApp `json:"apps,omitempty"`
+
+	// Restricts the IP addresses requests can be sent from.
+	// See: https://api.slack.com/docs/slack-ip-ranges
+	// +optional
+	IPAllowlist *IPAllowlist `json:"ipAllowlist,omitempty"`
+
+	// Restricts the events received from the Events API which are sent to
+	// the sink. Filtered events are acknowledged without being sent.
+	// +optional
+	Filters *SlackEventFilters `json:"filters,omitempty"`
+
+	// Enriches events received from the Events API with the names of the
+	// users and channels they refer to, resolved through the Slack Web API.
+	// +optional
+	Enrichment *SlackEnrichment `json:"enrichment,omitempty"`
+
+	// Enables the suppression of duplicate deliveries of the same event,
+	// typically caused by Slack retrying requests. Events are identified by
+	// their event_id, which Slack preserves across retries.
+	// See: https://api.slack.com/apis/connections/events-api#retries
+	// +optional
+	Deduplication *SlackDeduplication `json:"deduplication,omitempty"`
+
+	// Enables the asynchronous delivery of events to the sink. When set,
+	// Slack is acknowledged as soon as an event is buffered by the adapter,
+	// regardless of the latency of the sink. Does not apply to interactions
+	// and slash commands in the Reply response mode.
+	// +optional
+	AsyncDelivery *SlackAsyncDelivery `json:"asyncDelivery,omitempty"`
+
+	// Restricts the size and rate of requests.
+	// +optional
+	RequestLimits *RequestLimits `json:"requestLimits,omitempty"`
+
+	// Configures the CloudEvent types of events received from the Events
+	// API.
+	// +optional
+	EventTypes *SlackEventTypes `json:"eventTypes,omitempty"`
+
+	// Configures the responses to interactions with interactive components
+	// and to slash commands, which Slack expects within 3 seconds.
+	// See: https://api.slack.com/interactivity/handling#acknowledgment_response
+	// +optional
+	Interactivity *SlackInteractivity `json:"interactivity,omitempty"`
+
+	// Validates the payload of events against the JSON Schema of the Slack
+	// Events API.
+	// +optional
+	SchemaValidation *SchemaValidation `json:"schemaValidation,omitempty"`
+}
+
+// SlackApp defines a Slack app which events are accepted by the source.
+type SlackApp struct {
+	// ID of the Slack app.
+	AppID string `json:"appID"`
+
+	// Signing secret of the Slack app, which authenticates its callbacks.
+	// See: https://api.slack.com/authentication/verifying-requests-from-slack
+	SigningSecret ValueFromField `json:"signingSecret"`
+
+	// Additional signing secrets accepted to authenticate callbacks of the
+	// Slack app, e.g. the previous secret while it is being rotated.
+	// +optional
+	AdditionalSigningSecrets []ValueFromField `json:"additionalSigningSecrets,omitempty"`
+
+	// Prefix of the CloudEvent types of events originating from the Slack
+	// app, which replaces "com.slack", e.g. "com.example.helpdesk" for
+	// events of type "com.example.helpdesk.events".
+	// +optional
+	EventTypePrefix *string `json:"eventTypePrefix,omitempty"`
+}
+
+// SlackSocketMode defines the connection to Slack in Socket Mode.
+type SlackSocketMode struct {
+	// App-level token with the connections:write scope, used to open
+	// WebSocket connections.
+	AppToken ValueFromField `json:"appToken"`
+}
+
+// SlackEventFilters defines which events received from the Events API are
+// sent to the sink. An event is sent only if it passes all the configured
+// filters.
+type SlackEventFilters struct {
+	// Types of events which are sent, e.g. "message" or "app_mention". All
+	// types are sent if empty.
+	// +optional
+	EventTypes []string `json:"eventTypes,omitempty"`
+
+	// Subtypes of events which are not sent, e.g. "message_changed".
+	// +optional
+	ExcludedSubtypes []string `json:"excludedSubtypes,omitempty"`
+
+	// IDs of the channels which events are sent. Events which aren't
+	// associated with a channel are not sent. Events from all channels are
+	// sent if empty.
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// IDs of the users which events are sent. Events which aren't
+	// associated with a user are not sent. Events from all users are sent if
+	// empty.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Excludes events generated by bots, such as messages posted by bots.
+	// +optional
+	ExcludeBots *bool `json:"excludeBots,omitempty"`
+
+	// Excludes events generated by the Slack app itself, such as messages
+	// posted by the app.
+	// +optional
+	ExcludeSelf *bool `json:"excludeSelf,omitempty"`
 }
 
+// SlackEnrichment defines how events are enriched with metadata about users
+// and channels.
+type SlackEnrichment struct {
+	// Bot token used to call the Slack Web API. Requires the users:read,
+	// users:read.email and channels:read scopes, as well as groups:read for
+	// private channels.
+	// See: https://api.slack.com/authentication/token-types#bot
+	BotToken ValueFromField `json:"botToken"`
+
+	// Duration for which resolved users and channels are cached.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	CacheTTL *tmapis.Duration `json:"cacheTTL,omitempty"`
+
+	// Base URL of the Slack Web API. Defaults to https://slack.com/api/.
+	// +optional
+	APIURL *apis.URL `json:"apiURL,omitempty"`
+}
+
+// SlackDeduplication defines how duplicate deliveries of events are
+// suppressed.
+type SlackDeduplication struct {
+	// Duration for which the IDs of processed events are remembered.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	TTL *tmapis.Duration `json:"ttl,omitempty"`
+}
+
+// SlackAsyncDelivery defines how events are buffered and delivered by the
+// adapter in asynchronous mode.
+type SlackAsyncDelivery struct {
+	// Maximum number of events buffered in memory. Requests are rejected with
+	// the status code 503 (Service Unavailable) while the buffer is full,
+	// which causes Slack to retry them.
+	// +optional
+	QueueSize *int32 `json:"queueSize,omitempty"`
+
+	// Number of events delivered concurrently.
+	// +optional
+	Workers *int32 `json:"workers,omitempty"`
+
+	// Number of times the delivery of an event is retried upon failure.
+	// +optional
+	Retries *int32 `json:"retries,omitempty"`
+
+	// Delay before the first retry, doubled after every subsequent attempt.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	BackoffDelay *tmapis.Duration `json:"backoffDelay,omitempty"`
+
+	// URL which events that could not be delivered after all retries are
+	// sent to. When not set, such events are dropped.
+	// +optional
+	DeadLetterSink *apis.URL `json:"deadLetterSink,omitempty"`
+}
+
+// SlackEventTypes defines the CloudEvent types of events received from the
+// Events API.
+type SlackEventTypes struct {
+	// Determines how CloudEvent types are derived from Slack events.
+	// Defaults to Generic.
+	// +optional
+	Mode *SlackEventTypeMode `json:"mode,omitempty"`
+
+	// Slack event types the app is subscribed to, optionally followed by a
+	// subtype, e.g. "app_mention" or "message.channel_join". In Specific
+	// mode, the corresponding CloudEvent types are declared in the status of
+	// the source.
+	// See: https://api.slack.com/events
+	// +optional
+	Subscriptions []string `json:"subscriptions,omitempty"`
+}
+
+// SlackEventTypeMode determines how the CloudEvent types of events received
+// from the Events API are derived.
+type SlackEventTypeMode string
+
+// Accepted event type modes.
+const (
+	// All events have the type "com.slack.events", the type of the Slack
+	// event is set as the subject.
+	SlackEventTypeGeneric SlackEventTypeMode = "Generic"
+	// Events are typed after the type and subtype of the Slack event, e.g.
+	// "com.slack.events.message.channel_join".
+	SlackEventTypeSpecific SlackEventTypeMode = "Specific"
+)
+
+// SlackInteractivity defines how interactions and slash commands are
+// responded to.
+type SlackInteractivity struct {
+	// Response returned to Slack. Defaults to Ack.
+	// +optional
+	ResponseMode *SlackResponseMode `json:"responseMode,omitempty"`
+}
+
+// SlackResponseMode is the response returned to Slack upon interactions and
+// slash commands.
+type SlackResponseMode string
+
+// Accepted response modes.
+const (
+	// Slack receives an empty acknowledgement once the event is sent.
+	SlackResponseAck SlackResponseMode = "Ack"
+	// Slack receives the data of the event the sink replies with, e.g. a
+	// message or a view response_action. An empty acknowledgement is
+	// returned if the sink doesn't reply in time.
+	SlackResponseReply SlackResponseMode = "Reply"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // SlackSourceList contains a list of event sources.
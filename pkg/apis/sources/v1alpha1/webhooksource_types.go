@@ -17,10 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	tmapis "github.com/triggermesh/knative-sources/pkg/apis"
 )
 
 // +genclient
@@ -67,6 +71,300 @@ type WebhookSourceSpec struct {
 	// Password HTTP clients must set to authenticate with the webhook using HTTP Basic authentication.
 	// +optional
 	BasicAuthPassword *ValueFromField `json:"basicAuthPassword,omitempty"`
+
+	// Additional passwords accepted for HTTP Basic authentication, e.g. the
+	// previous password while it is being rotated. A request is authenticated
+	// if it presents any of the accepted passwords.
+	// +optional
+	AdditionalBasicAuthPasswords []ValueFromField `json:"additionalBasicAuthPasswords,omitempty"`
+
+	// Authentication of HTTP clients using JSON Web Tokens (JWT) passed in
+	// the Authorization header with the Bearer scheme.
+	// Takes precedence over HTTP Basic authentication.
+	// +optional
+	JWTAuth *WebhookJWTAuth `json:"jwtAuth,omitempty"`
+
+	// Authentication of HTTP clients using TLS client certificates, which
+	// must chain to a trusted certificate authority. Applies in addition to
+	// other authentication methods.
+	// +optional
+	ClientCertAuth *WebhookClientCertAuth `json:"clientCertAuth,omitempty"`
+
+	// Enables the asynchronous delivery of events to the sink. When set, HTTP
+	// clients receive a response as soon as the event is accepted by the
+	// webhook, before it is delivered.
+	// +optional
+	AsyncDelivery *WebhookAsyncDelivery `json:"asyncDelivery,omitempty"`
+
+	// Restricts the IP addresses HTTP clients can send requests from.
+	// +optional
+	IPAllowlist *IPAllowlist `json:"ipAllowlist,omitempty"`
+
+	// Allows browsers to send requests to the webhook from web pages served
+	// from other origins, using Cross-Origin Resource Sharing (CORS).
+	// +optional
+	CORS *WebhookCORS `json:"cors,omitempty"`
+
+	// Enables the conversion of form submissions and query strings to JSON
+	// objects, so that the data of all events is consistently encoded in JSON.
+	// +optional
+	BodyDecoding *WebhookBodyDecoding `json:"bodyDecoding,omitempty"`
+
+	// Enables the splitting of batched JSON payloads into individual events.
+	// +optional
+	BatchSplitting *WebhookBatchSplitting `json:"batchSplitting,omitempty"`
+
+	// Enables the suppression of duplicate requests based on an idempotency
+	// key, which is also used as the ID of the corresponding events.
+	// +optional
+	Idempotency *WebhookIdempotency `json:"idempotency,omitempty"`
+
+	// Restricts the size and rate of requests.
+	// +optional
+	RequestLimits *RequestLimits `json:"requestLimits,omitempty"`
+
+	// Validates the data of events against a JSON Schema. Applies to the
+	// decoded payload when body decoding is enabled, and to each individual
+	// event when batch splitting is enabled.
+	// +optional
+	SchemaValidation *CustomSchemaValidation `json:"schemaValidation,omitempty"`
+
+	// Customizes the response returned to HTTP clients which requests are
+	// successfully processed, for providers which expect a specific
+	// acknowledgement.
+	// +optional
+	Response *WebhookResponse `json:"response,omitempty"`
+}
+
+// WebhookJWTAuth defines how JSON Web Tokens presented by HTTP clients are
+// validated.
+type WebhookJWTAuth struct {
+	// Expected value of the 'iss' (issuer) claim. Tokens issued by any other
+	// party are rejected.
+	Issuer string `json:"issuer"`
+
+	// Accepted values of the 'aud' (audience) claim. Tokens are accepted when
+	// they were issued for at least one of these audiences.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+
+	// Accepted signature algorithms. Defaults to RS256.
+	// https://tools.ietf.org/html/rfc7518#section-3.1
+	// +optional
+	Algorithms []string `json:"algorithms,omitempty"`
+
+	// JSON Web Key Set (JWKS) containing the keys used to verify the
+	// signature of tokens.
+	JWKS WebhookJWKS `json:"jwks"`
+
+	// Claims to propagate as CloudEvents extensions, keyed by claim name.
+	// +optional
+	ClaimsToExtensions map[string]string `json:"claimsToExtensions,omitempty"`
+}
+
+// WebhookJWKS is the source of a JSON Web Key Set document.
+type WebhookJWKS struct {
+	// Optional: no more than one of the following may be specified.
+
+	// JWKS document provided either literally or from a Kubernetes Secret.
+	// +optional
+	ValueFromField `json:",inline"`
+
+	// JWKS document from a Kubernetes ConfigMap.
+	// +optional
+	ValueFromConfigMap *corev1.ConfigMapKeySelector `json:"valueFromConfigMap,omitempty"`
+
+	// URL of the JWKS document, e.g. https://example.com/.well-known/jwks.json
+	// +optional
+	URL *apis.URL `json:"url,omitempty"`
+
+	// Duration for which a JWKS document retrieved from a URL is cached.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	CacheDuration *tmapis.Duration `json:"cacheDuration,omitempty"`
+}
+
+// WebhookClientCertAuth defines how TLS client certificates presented by HTTP
+// clients are validated. Webhooks are Knative Services, which only receive
+// requests through the ingress of the cluster. TLS is therefore terminated by
+// that ingress, which forwards client certificates in an HTTP header. Both the
+// Envoy X-Forwarded-Client-Cert format and URL-encoded PEM certificates are
+// supported.
+type WebhookClientCertAuth struct {
+	// PEM-encoded certificates of the authorities client certificates must
+	// chain to.
+	CABundle ValueFromField `json:"caBundle"`
+
+	// Name of the HTTP header in which the ingress forwards client
+	// certificates. Defaults to X-Forwarded-Client-Cert.
+	// The ingress must be configured to overwrite any value of this header
+	// set by HTTP clients.
+	// +optional
+	Header *string `json:"header,omitempty"`
+
+	// IP ranges, in CIDR notation, of the proxies which are trusted to
+	// forward client certificates. The header is ignored in requests
+	// received from other addresses.
+	TrustedProxyCIDRs []string `json:"trustedProxyCIDRs"`
+
+	// Accepted subjects of the certificate, as distinguished names in the
+	// RFC 2253 format (e.g. "CN=client,O=Example").
+	// Any subject is accepted if this list is empty.
+	// +optional
+	AllowedSubjects []string `json:"allowedSubjects,omitempty"`
+
+	// Accepted Subject Alternative Names (DNS names, email addresses, URIs
+	// or IP addresses). Certificates are accepted when they contain at least
+	// one of these names. Any certificate is accepted if this list is empty.
+	// +optional
+	AllowedSANs []string `json:"allowedSANs,omitempty"`
+}
+
+// WebhookAsyncDelivery defines how events are buffered and delivered by the
+// webhook in asynchronous mode.
+type WebhookAsyncDelivery struct {
+	// Maximum number of events buffered in memory. Requests are rejected with
+	// the status code 503 (Service Unavailable) while the buffer is full.
+	// +optional
+	QueueSize *int32 `json:"queueSize,omitempty"`
+
+	// Number of times the delivery of an event is retried upon failure.
+	// +optional
+	Retries *int32 `json:"retries,omitempty"`
+
+	// Delay before the first retry, doubled after every subsequent attempt.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	BackoffDelay *tmapis.Duration `json:"backoffDelay,omitempty"`
+}
+
+// WebhookCORS defines which cross-origin requests browsers are allowed to
+// send to the webhook.
+type WebhookCORS struct {
+	// Origins browsers can send requests from, e.g. "https://example.com".
+	// The value "*" allows all origins.
+	AllowedOrigins []string `json:"allowedOrigins"`
+
+	// HTTP methods browsers can use in requests. Defaults to GET and POST.
+	// +optional
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+
+	// HTTP headers browsers can set on requests. The value "*" allows all
+	// headers. Defaults to Content-Type.
+	// +optional
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+
+	// Duration for which browsers can cache the response to a preflight
+	// request.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	MaxAge *tmapis.Duration `json:"maxAge,omitempty"`
+}
+
+// WebhookBodyDecoding defines how the bodies of HTTP requests are converted
+// to JSON objects.
+//
+// The following payloads are converted:
+//  * application/x-www-form-urlencoded and multipart/form-data request bodies
+//  * query strings of GET requests
+//
+// Each field becomes an attribute of the resulting JSON object. Fields which
+// occur multiple times become arrays.
+type WebhookBodyDecoding struct {
+	// Handling of files contained in multipart/form-data requests.
+	// Defaults to Ignore.
+	// +optional
+	MultipartFiles *WebhookMultipartFilesMode `json:"multipartFiles,omitempty"`
+}
+
+// WebhookMultipartFilesMode is the handling mode of files contained in
+// multipart/form-data requests.
+type WebhookMultipartFilesMode string
+
+// Accepted handling modes of files contained in multipart/form-data requests.
+const (
+	// Files are discarded, only form fields are converted.
+	WebhookMultipartFilesIgnore WebhookMultipartFilesMode = "Ignore"
+	// Files are converted to JSON objects containing their name, media
+	// type and base64-encoded content.
+	WebhookMultipartFilesBase64 WebhookMultipartFilesMode = "Base64"
+)
+
+// WebhookBatchSplitting defines how an array contained in a JSON payload is
+// split into individual events, one per element.
+type WebhookBatchSplitting struct {
+	// Location of the array in the JSON payload, expressed as a
+	// dot-separated list of object keys, e.g. "events" or "data.items".
+	// Defaults to the root of the payload.
+	// +optional
+	Path *string `json:"path,omitempty"`
+
+	// Location of the field of each element which value is used as the ID of
+	// the corresponding event, expressed as a dot-separated list of object
+	// keys, e.g. "id" or "meta.uuid". IDs are generated when this field is
+	// not set, or is missing from an element.
+	// +optional
+	IDField *string `json:"idField,omitempty"`
+
+	// Response returned to HTTP clients when only some of the events
+	// generated from a request are delivered. Defaults to Fail.
+	// +optional
+	PartialFailure *WebhookPartialFailurePolicy `json:"partialFailure,omitempty"`
+}
+
+// WebhookPartialFailurePolicy is the policy applied when only some of the
+// events generated from a single request are delivered.
+type WebhookPartialFailurePolicy string
+
+// Accepted policies for partially delivered batches.
+const (
+	// The request fails with the status code of the first undelivered
+	// event.
+	WebhookPartialFailureFail WebhookPartialFailurePolicy = "Fail"
+	// The request succeeds. Undelivered events are only logged.
+	WebhookPartialFailureIgnore WebhookPartialFailurePolicy = "Ignore"
+	// The request succeeds with the status code 207 (Multi-Status), and the
+	// response contains the delivery status of each individual event.
+	WebhookPartialFailureMultiStatus WebhookPartialFailurePolicy = "MultiStatus"
+)
+
+// WebhookIdempotency defines where the idempotency key of requests is read
+// from, and for how long it is remembered.
+type WebhookIdempotency struct {
+	// Optional: at least one of the following must be specified.
+
+	// Name of the HTTP header containing the idempotency key, e.g.
+	// "Idempotency-Key". Takes precedence over KeyField when both are set,
+	// unless the header is absent or empty.
+	// +optional
+	KeyHeader *string `json:"keyHeader,omitempty"`
+
+	// Location of the idempotency key in JSON payloads, expressed as a
+	// dot-separated list of object keys, e.g. "id" or "meta.delivery_id".
+	// +optional
+	KeyField *string `json:"keyField,omitempty"`
+
+	// Duration for which idempotency keys are remembered.
+	// Expressed as a duration string, which format is documented at https://pkg.go.dev/time#ParseDuration.
+	// +optional
+	TTL *tmapis.Duration `json:"ttl,omitempty"`
+}
+
+// WebhookResponse defines the response returned to HTTP clients which
+// requests are successfully processed.
+type WebhookResponse struct {
+	// Status code of the response. Defaults to 200 (OK), or to 202
+	// (Accepted) when asynchronous delivery is enabled.
+	// +optional
+	StatusCode *int32 `json:"statusCode,omitempty"`
+
+	// HTTP headers to include in the response, e.g. Content-Type.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Static body of the response.
+	// +optional
+	Body *string `json:"body,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -70,13 +70,61 @@ func (*ZendeskSource) IsMultiTenant() bool {
 const (
 	// ZendeskTicketCreatedEventType is generated upon creation of a Ticket.
 	ZendeskTicketCreatedEventType = "com.zendesk.ticket.created"
+	// ZendeskTicketUpdatedEventType is generated upon update of a Ticket.
+	ZendeskTicketUpdatedEventType = "com.zendesk.ticket.updated"
+	// ZendeskTicketSolvedEventType is generated when a Ticket is solved.
+	ZendeskTicketSolvedEventType = "com.zendesk.ticket.solved"
+	// ZendeskTicketReopenedEventType is generated when a solved Ticket is
+	// reopened.
+	ZendeskTicketReopenedEventType = "com.zendesk.ticket.reopened"
+	// ZendeskCommentAddedEventType is generated when a comment is added to
+	// a Ticket.
+	ZendeskCommentAddedEventType = "com.zendesk.ticket.comment.added"
+	// ZendeskAssigneeChangedEventType is generated when the assignee of a
+	// Ticket changes.
+	ZendeskAssigneeChangedEventType = "com.zendesk.ticket.assignee.changed"
+	// ZendeskPriorityChangedEventType is generated when the priority of a
+	// Ticket changes.
+	ZendeskPriorityChangedEventType = "com.zendesk.ticket.priority.changed"
 )
 
+// zendeskEventTypes maps kinds of ticket events to their event type.
+var zendeskEventTypes = map[ZendeskEventKind]string{
+	ZendeskTicketCreated:   ZendeskTicketCreatedEventType,
+	ZendeskTicketUpdated:   ZendeskTicketUpdatedEventType,
+	ZendeskTicketSolved:    ZendeskTicketSolvedEventType,
+	ZendeskTicketReopened:  ZendeskTicketReopenedEventType,
+	ZendeskCommentAdded:    ZendeskCommentAddedEventType,
+	ZendeskAssigneeChanged: ZendeskAssigneeChangedEventType,
+	ZendeskPriorityChanged: ZendeskPriorityChangedEventType,
+}
+
+// ZendeskEventType returns the event type generated for the given kind of
+// ticket event, or an empty string if the kind is unknown.
+func ZendeskEventType(kind ZendeskEventKind) string {
+	return zendeskEventTypes[kind]
+}
+
+// GetEventKinds returns the kinds of ticket events sent by the source.
+func (s *ZendeskSource) GetEventKinds() []ZendeskEventKind {
+	if len(s.Spec.EventKinds) == 0 {
+		return []ZendeskEventKind{ZendeskTicketCreated}
+	}
+	return s.Spec.EventKinds
+}
+
 // GetEventTypes implements EventSource.
-func (*ZendeskSource) GetEventTypes() []string {
-	return []string{
-		ZendeskTicketCreatedEventType,
+func (s *ZendeskSource) GetEventTypes() []string {
+	kinds := s.GetEventKinds()
+
+	types := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		if t := ZendeskEventType(k); t != "" {
+			types = append(types, t)
+		}
 	}
+
+	return types
 }
 
 // Status conditions
@@ -93,6 +141,8 @@ const (
 	ZendeskReasonNoSecret = "MissingSecret"
 	// ZendeskReasonFailedSync is set on a TargetSynced condition when a CRUD API call returns an error.
 	ZendeskReasonFailedSync = "FailedSync"
+	// ZendeskReasonInvalidConditions is set on a TargetSynced condition when the Trigger conditions are invalid.
+	ZendeskReasonInvalidConditions = "InvalidConditions"
 )
 
 // zendeskSourceConditionSet is a set of status conditions for ZendeskSource
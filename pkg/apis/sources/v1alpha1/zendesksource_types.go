@@ -66,12 +66,89 @@ type ZendeskSourceSpec struct {
 	// to the adapter.
 	WebhookPassword ValueFromField `json:"webhookPassword,omitempty"`
 
+	// Additional passwords accepted for basic authentication, e.g. the
+	// previous password while it is being rotated. Only WebhookPassword is
+	// registered with Zendesk, but a request is authenticated if it presents
+	// any of the accepted passwords.
+	// +optional
+	AdditionalWebhookPasswords []ValueFromField `json:"additionalWebhookPasswords,omitempty"`
+
 	// WebhookUsername used for basic authentication for events sent from Zendesk
 	// to the adapter.
 	WebhookUsername string `json:"webhookUsername,omitempty"`
 
 	// Subdomain identifies Zendesk subdomain
 	Subdomain string `json:"subdomain,omitempty"`
+
+	// EventKinds lists the kinds of ticket events sent by the source. Each
+	// kind is provisioned in Zendesk as a separate Trigger. Defaults to
+	// TicketCreated.
+	// +optional
+	EventKinds []ZendeskEventKind `json:"eventKinds,omitempty"`
+
+	// Conditions restricts the tickets which events are sent by the source.
+	// They are added to the conditions of each Trigger provisioned in
+	// Zendesk, which already match the kind of ticket event.
+	// See: https://developer.zendesk.com/api-reference/ticketing/business-rules/conditions/
+	// +optional
+	Conditions *ZendeskTriggerConditions `json:"conditions,omitempty"`
+
+	// RequestLimits restricts the size and rate of requests sent by Zendesk
+	// to the adapter.
+	// +optional
+	RequestLimits *RequestLimits `json:"requestLimits,omitempty"`
+
+	// SchemaValidation validates the data of events against the JSON Schema
+	// of Zendesk tickets.
+	// +optional
+	SchemaValidation *SchemaValidation `json:"schemaValidation,omitempty"`
+}
+
+// ZendeskEventKind is a kind of ticket event sent by Zendesk.
+type ZendeskEventKind string
+
+// Supported kinds of ticket events.
+const (
+	// ZendeskTicketCreated occurs when a ticket is created.
+	ZendeskTicketCreated ZendeskEventKind = "TicketCreated"
+	// ZendeskTicketUpdated occurs when a ticket is updated.
+	ZendeskTicketUpdated ZendeskEventKind = "TicketUpdated"
+	// ZendeskTicketSolved occurs when the status of a ticket changes to
+	// solved.
+	ZendeskTicketSolved ZendeskEventKind = "TicketSolved"
+	// ZendeskTicketReopened occurs when the status of a solved ticket
+	// changes to a status other than closed.
+	ZendeskTicketReopened ZendeskEventKind = "TicketReopened"
+	// ZendeskCommentAdded occurs when a comment is added to a ticket.
+	ZendeskCommentAdded ZendeskEventKind = "CommentAdded"
+	// ZendeskAssigneeChanged occurs when the assignee of a ticket changes.
+	ZendeskAssigneeChanged ZendeskEventKind = "AssigneeChanged"
+	// ZendeskPriorityChanged occurs when the priority of a ticket changes.
+	ZendeskPriorityChanged ZendeskEventKind = "PriorityChanged"
+)
+
+// ZendeskTriggerConditions defines the conditions tickets must meet for
+// events to be sent.
+type ZendeskTriggerConditions struct {
+	// Conditions which must all be met.
+	// +optional
+	All []ZendeskTriggerCondition `json:"all,omitempty"`
+	// Conditions of which at least one must be met.
+	// +optional
+	Any []ZendeskTriggerCondition `json:"any,omitempty"`
+}
+
+// ZendeskTriggerCondition is a condition of a Zendesk Trigger.
+type ZendeskTriggerCondition struct {
+	// Name of the ticket field the condition applies to, e.g. "group_id",
+	// "brand_id" or "current_tags".
+	Field string `json:"field"`
+	// Operator of the condition, e.g. "is" or "includes".
+	Operator string `json:"operator"`
+	// Value the field is compared to. Not required by operators which
+	// don't compare the field to a value, such as "changed".
+	// +optional
+	Value *string `json:"value,omitempty"`
 }
 
 // ZendeskSourceStatus defines the observed state of the event source.
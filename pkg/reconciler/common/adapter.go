@@ -43,12 +43,26 @@ func ComponentName(src kmeta.OwnerRefable) string {
 	return strings.ToLower(src.GetGroupVersionKind().Kind)
 }
 
-// MTAdapterObjectName returns a unique name to apply to all objects related to
-// the given source's multi-tenant adapter (RBAC, Deployment/KnService, ...).
+// MTAdapterObjectName returns a unique name to apply to the given source's
+// multi-tenant adapter (Deployment/KnService), and to the RBAC objects of its
+// adapters (see adapterServiceAccountName).
 func MTAdapterObjectName(src kmeta.OwnerRefable) string {
 	return ComponentName(src) + "-" + componentAdapter
 }
 
+// adapterServiceAccountName returns the name of the ServiceAccount the adapter
+// of the given source runs as.
+// Sources which can be served in either tenancy mode use a distinct
+// ServiceAccount in multi-tenant mode, so that the permissions required to
+// read sources and their credentials from the API are not granted to their
+// single-tenant adapters.
+func adapterServiceAccountName(src v1alpha1.EventSource) string {
+	if _, isMT := src.(*multiTenantSource); isMT {
+		return ComponentName(src) + "-mt-" + componentAdapter
+	}
+	return MTAdapterObjectName(src)
+}
+
 // NewAdapterDeployment is a wrapper around resource.NewDeployment which
 // pre-populates attributes common to all adapters backed by a Deployment.
 func NewAdapterDeployment(src v1alpha1.EventSource, sinkURI *apis.URL, opts ...resource.ObjectOption) *appsv1.Deployment {
@@ -68,6 +82,8 @@ func NewAdapterDeployment(src v1alpha1.EventSource, sinkURI *apis.URL, opts ...r
 			resource.Selector(appInstanceLabel, srcName),
 
 			resource.EnvVar(envSink, sinkURIStr),
+			resource.EnvVar(EnvNamespace, srcNs),
+			resource.EnvVar(EnvName, srcName),
 		}, opts...)...)...,
 	)
 }
@@ -104,7 +120,7 @@ func commonAdapterDeploymentOptions(src v1alpha1.EventSource) []resource.ObjectO
 		resource.PodLabel(appPartOfLabel, partOf),
 		resource.PodLabel(appManagedByLabel, managedBy),
 
-		resource.ServiceAccount(MTAdapterObjectName(src)),
+		resource.ServiceAccount(adapterServiceAccountName(src)),
 
 		resource.EnvVar(envComponent, app),
 	}
@@ -130,6 +146,8 @@ func NewAdapterKnService(src v1alpha1.EventSource, sinkURI *apis.URL, opts ...re
 			resource.PodLabel(appInstanceLabel, srcName),
 
 			resource.EnvVar(envSink, sinkURIStr),
+			resource.EnvVar(EnvNamespace, srcNs),
+			resource.EnvVar(EnvName, srcName),
 		}, opts...)...)...,
 	)
 }
@@ -164,7 +182,7 @@ func commonAdapterKnServiceOptions(src v1alpha1.EventSource) []resource.ObjectOp
 		resource.PodLabel(appPartOfLabel, partOf),
 		resource.PodLabel(appManagedByLabel, managedBy),
 
-		resource.ServiceAccount(MTAdapterObjectName(src)),
+		resource.ServiceAccount(adapterServiceAccountName(src)),
 
 		resource.EnvVar(envComponent, app),
 		resource.EnvVar(envMetricsPrometheusPort, strconv.FormatUint(uint64(metricsPrometheusPort), 10)),
@@ -183,7 +201,7 @@ func newServiceAccount(src v1alpha1.EventSource, owners []kmeta.OwnerRefable) *c
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       src.GetNamespace(),
-			Name:            MTAdapterObjectName(src),
+			Name:            adapterServiceAccountName(src),
 			OwnerReferences: ownerRefs,
 			Labels:          CommonObjectLabels(src),
 		},
@@ -198,7 +216,7 @@ func newRoleBinding(src v1alpha1.EventSource, owner *corev1.ServiceAccount) *rba
 	saGVK := corev1.SchemeGroupVersion.WithKind("ServiceAccount")
 
 	ns := src.GetNamespace()
-	n := MTAdapterObjectName(src)
+	n := adapterServiceAccountName(src)
 
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
@@ -267,3 +285,135 @@ func MaybeAppendValueFromEnvVar(envs []corev1.EnvVar, key string, valueFrom v1al
 
 	return envs
 }
+
+// MakeIndexedValueFromEnvVars returns one environment variable per given value,
+// named after the given prefix followed by an underscore and the index of the
+// value (e.g. PREFIX_0, PREFIX_1, ...), which is the format expected by
+// receive adapters for lists of secrets.
+// Empty values are propagated as well to preserve the sequence of indexes.
+func MakeIndexedValueFromEnvVars(prefix string, vals []v1alpha1.ValueFromField) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	for i, v := range vals {
+		key := prefix + "_" + strconv.Itoa(i)
+
+		envs = MaybeAppendValueFromEnvVar(envs, key, v)
+		if len(envs) == i {
+			envs = append(envs, corev1.EnvVar{Name: key})
+		}
+	}
+
+	return envs
+}
+
+// MakeRequestLimitsEnvs returns the environment variables which propagate the
+// given request limits to a receive adapter. limits may be nil.
+func MakeRequestLimitsEnvs(limits *v1alpha1.RequestLimits) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	if limits == nil {
+		return envs
+	}
+
+	if mbs := limits.MaxBodySize; mbs != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envRequestMaxBodySize,
+			Value: strconv.FormatInt(mbs.Value(), 10),
+		})
+	}
+
+	if mcr := limits.MaxConcurrentRequests; mcr != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envRequestMaxConcurrent,
+			Value: strconv.FormatInt(int64(*mcr), 10),
+		})
+	}
+
+	if rl := limits.RateLimit; rl != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envRequestRateLimit,
+			Value: strconv.FormatInt(int64(rl.RequestsPerSecond), 10),
+		})
+
+		if b := rl.Burst; b != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envRequestRateBurst,
+				Value: strconv.FormatInt(int64(*b), 10),
+			})
+		}
+
+		if k := rl.Key; k != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envRequestRateLimitKey,
+				Value: string(*k),
+			})
+		}
+
+		if len(rl.TrustedProxyCIDRs) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envRequestTrustedProxyCIDRs,
+				Value: strings.Join(rl.TrustedProxyCIDRs, ","),
+			})
+		}
+	}
+
+	return envs
+}
+
+// MakeSchemaValidationEnvs returns the environment variables which enable the
+// validation of events against the built-in schema of a receive adapter. sv
+// may be nil.
+func MakeSchemaValidationEnvs(sv *v1alpha1.SchemaValidation) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	if sv == nil {
+		return envs
+	}
+
+	envs = append(envs, corev1.EnvVar{
+		Name:  envSchemaValidation,
+		Value: strconv.FormatBool(true),
+	})
+
+	return appendSchemaErrorSinkEnv(envs, sv)
+}
+
+// MakeCustomSchemaValidationEnvs returns the environment variables which
+// enable the validation of events against a user-provided schema. sv may be
+// nil.
+func MakeCustomSchemaValidationEnvs(sv *v1alpha1.CustomSchemaValidation) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	if sv == nil {
+		return envs
+	}
+
+	envs = append(envs, corev1.EnvVar{
+		Name: envSchemaDocument,
+		ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &sv.Schema,
+		},
+	})
+
+	if uri := sv.DataSchema; uri != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSchemaURI,
+			Value: uri.String(),
+		})
+	}
+
+	return appendSchemaErrorSinkEnv(envs, &sv.SchemaValidation)
+}
+
+// appendSchemaErrorSinkEnv appends the environment variable which propagates
+// the error sink of the given schema validation settings, if set.
+func appendSchemaErrorSinkEnv(envs []corev1.EnvVar, sv *v1alpha1.SchemaValidation) []corev1.EnvVar {
+	if sink := sv.ErrorSink; sink != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSchemaErrorSink,
+			Value: sink.String(),
+		})
+	}
+
+	return envs
+}
@@ -24,4 +24,16 @@ const (
 	envSink                  = "K_SINK"
 	envComponent             = "K_COMPONENT"
 	envMetricsPrometheusPort = "METRICS_PROMETHEUS_PORT"
+
+	envRequestMaxBodySize       = "REQUEST_MAX_BODY_SIZE"
+	envRequestMaxConcurrent     = "REQUEST_MAX_CONCURRENT"
+	envRequestRateLimit         = "REQUEST_RATE_LIMIT"
+	envRequestRateBurst         = "REQUEST_RATE_BURST"
+	envRequestRateLimitKey      = "REQUEST_RATE_LIMIT_KEY"
+	envRequestTrustedProxyCIDRs = "REQUEST_TRUSTED_PROXY_CIDRS"
+
+	envSchemaValidation = "SCHEMA_VALIDATION"
+	envSchemaDocument   = "SCHEMA_DOCUMENT"
+	envSchemaURI        = "SCHEMA_URI"
+	envSchemaErrorSink  = "SCHEMA_ERROR_SINK"
 )
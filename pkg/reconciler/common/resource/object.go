@@ -51,3 +51,18 @@ func Label(key, val string) ObjectOption {
 		lbls[key] = val
 	}
 }
+
+// Annotation sets the value of an API object's annotation.
+func Annotation(key, val string) ObjectOption {
+	return func(object interface{}) {
+		meta := object.(metav1.Object)
+
+		anns := meta.GetAnnotations()
+
+		if anns == nil {
+			anns = make(map[string]string, 1)
+			meta.SetAnnotations(anns)
+		}
+		anns[key] = val
+	}
+}
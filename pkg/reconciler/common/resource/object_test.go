@@ -54,6 +54,7 @@ func TestMetaObjectOptions(t *testing.T) {
 		Label("test.label/2", "val2"),
 		Controller(makeOwnerRefable()),
 		Label("test.label/1", "val1"),
+		Annotation("test.annotation/1", "val1"),
 	).ObjectMeta
 
 	expectObjMeta := metav1.ObjectMeta{
@@ -66,6 +67,9 @@ func TestMetaObjectOptions(t *testing.T) {
 			"test.label/1": "val1",
 			"test.label/2": "val2",
 		},
+		Annotations: map[string]string{
+			"test.annotation/1": "val1",
+		},
 	}
 
 	if d := cmp.Diff(expectObjMeta, objMeta); d != "" {
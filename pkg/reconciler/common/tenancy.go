@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/reconciler"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/pkg/reconciler/common/event"
+)
+
+// MultiTenantLabel returns the namespace label which selects whether the
+// sources of the given type in that namespace are served by a multi-tenant
+// adapter ("true") or by one adapter per source ("false"). In the absence of
+// this label, the controller's default applies.
+func MultiTenantLabel(typ kmeta.OwnerRefable) string {
+	return v1alpha1.SchemeGroupVersion.Group + "/" + ComponentName(typ) + "-multi-tenant"
+}
+
+// IsMultiTenantNamespace returns whether the sources of the given type in the
+// given namespace should be served by a multi-tenant adapter.
+func IsMultiTenantNamespace(nsLister corelistersv1.NamespaceLister, typ kmeta.OwnerRefable,
+	namespace string, defaultMultiTenant bool) (bool, error) {
+
+	ns, err := nsLister.Get(namespace)
+	switch {
+	case apierrors.IsNotFound(err):
+		return defaultMultiTenant, nil
+	case err != nil:
+		return false, fmt.Errorf("getting namespace from cache: %w", err)
+	}
+
+	label := MultiTenantLabel(typ)
+
+	val, ok := ns.Labels[label]
+	if !ok {
+		return defaultMultiTenant, nil
+	}
+
+	mt, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, reconciler.NewEvent(corev1.EventTypeWarning, ReasonInvalidSpec,
+			"Invalid value %q for label %s of namespace %q", val, label, namespace)
+	}
+
+	return mt, nil
+}
+
+// EnqueueObjectsOnTenancyModeChange returns a Namespace event handler which
+// triggers a resync of all objects in the given informer when the tenancy
+// mode of the sources of the given type changes in a namespace.
+func EnqueueObjectsOnTenancyModeChange(typ kmeta.OwnerRefable, inf cache.SharedInformer,
+	resyncFn filteredGlobalResyncFunc) cache.ResourceEventHandler {
+
+	label := MultiTenantLabel(typ)
+
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, newNs := oldObj.(*corev1.Namespace), newObj.(*corev1.Namespace)
+			if oldNs.Labels[label] != newNs.Labels[label] {
+				resyncFn(isInNamespace(newNs.Name), inf)
+			}
+		},
+	}
+}
+
+// AsMultiTenant returns the given source wrapped in a type which indicates
+// that the source is served by the multi-tenant adapter of its namespace.
+func AsMultiTenant(src v1alpha1.EventSource) v1alpha1.EventSource {
+	return &multiTenantSource{src}
+}
+
+// multiTenantSource is a source served by the multi-tenant adapter of its
+// namespace.
+type multiTenantSource struct {
+	v1alpha1.EventSource
+}
+
+// IsMultiTenant makes v1alpha1.IsMultiTenant report the source as multi-tenant.
+func (*multiTenantSource) IsMultiTenant() bool {
+	return true
+}
+
+// GetObjectKind implements runtime.Object.
+// The wrapper type isn't registered in any scheme, so its kind must always be
+// explicit for references to it, such as the ones of API events, to resolve
+// to the wrapped source.
+func (s *multiTenantSource) GetObjectKind() schema.ObjectKind {
+	gvk := s.GetGroupVersionKind()
+	return &metav1.TypeMeta{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+	}
+}
+
+// EnsureNoStaleAdapter deletes the adapter Service which served the given
+// source in the tenancy mode opposite to the current one, if it exists.
+func (r *GenericServiceReconciler) EnsureNoStaleAdapter(ctx context.Context,
+	src v1alpha1.EventSource, isMultiTenant bool) error {
+
+	var name string
+	var isOwned func(metav1.Object) bool
+
+	if isMultiTenant {
+		name = kmeta.ChildName(ComponentName(src)+"-", src.GetName())
+		isOwned = func(obj metav1.Object) bool {
+			return metav1.IsControlledBy(obj, src)
+		}
+	} else {
+		name = MTAdapterObjectName(src)
+		saName := adapterServiceAccountName(AsMultiTenant(src))
+		isOwned = func(obj metav1.Object) bool {
+			owner := metav1.GetControllerOfNoCopy(obj)
+			return owner != nil && owner.Kind == "ServiceAccount" && owner.Name == saName
+		}
+	}
+
+	adapter, err := r.Lister(src.GetNamespace()).Get(name)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return fmt.Errorf("getting adapter Service from cache: %w", err)
+	}
+
+	if !isOwned(adapter) {
+		return nil
+	}
+
+	err = r.Client(src.GetNamespace()).Delete(ctx, name, metav1.DeleteOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return reconciler.NewEvent(corev1.EventTypeWarning, ReasonFailedAdapterDelete,
+			"Failed to delete stale adapter Service %q: %s", name, err)
+	}
+	event.Normal(ctx, ReasonAdapterDelete, "Deleted stale adapter Service %q", name)
+
+	return nil
+}
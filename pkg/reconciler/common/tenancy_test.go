@@ -0,0 +1,218 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	fakeservingclient "knative.dev/serving/pkg/client/clientset/versioned/fake"
+	servinglistersv1 "knative.dev/serving/pkg/client/listers/serving/v1"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+const tTenancyNs = "testns"
+
+func TestMultiTenantLabel(t *testing.T) {
+	assert.Equal(t, "sources.triggermesh.io/webhooksource-multi-tenant",
+		MultiTenantLabel((*v1alpha1.WebhookSource)(nil)))
+}
+
+func TestIsMultiTenantNamespace(t *testing.T) {
+	typ := (*v1alpha1.WebhookSource)(nil)
+	label := MultiTenantLabel(typ)
+
+	testCases := map[string]struct {
+		nsLabels      map[string]string
+		noNamespace   bool
+		defaultMT     bool
+		expectMT      bool
+		expectInvalid bool
+	}{
+		"Controller default, single-tenant": {
+			defaultMT: false,
+			expectMT:  false,
+		},
+		"Controller default, multi-tenant": {
+			defaultMT: true,
+			expectMT:  true,
+		},
+		"Namespace not in cache": {
+			noNamespace: true,
+			defaultMT:   true,
+			expectMT:    true,
+		},
+		"Namespace opts in": {
+			nsLabels:  map[string]string{label: "true"},
+			defaultMT: false,
+			expectMT:  true,
+		},
+		"Namespace opts out": {
+			nsLabels:  map[string]string{label: "false"},
+			defaultMT: true,
+			expectMT:  false,
+		},
+		"Label of another source type": {
+			nsLabels:  map[string]string{MultiTenantLabel((*v1alpha1.SlackSource)(nil)): "true"},
+			defaultMT: false,
+			expectMT:  false,
+		},
+		"Invalid label value": {
+			nsLabels:      map[string]string{label: "maybe"},
+			expectInvalid: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if !tc.noNamespace {
+				require.NoError(t, indexer.Add(&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   tTenancyNs,
+						Labels: tc.nsLabels,
+					},
+				}))
+			}
+
+			mt, err := IsMultiTenantNamespace(corelistersv1.NewNamespaceLister(indexer), typ, tTenancyNs, tc.defaultMT)
+
+			if tc.expectInvalid {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectMT, mt)
+		})
+	}
+}
+
+func TestAsMultiTenant(t *testing.T) {
+	src := newTenancyTestSource()
+
+	mtSrc := AsMultiTenant(src)
+
+	assert.True(t, v1alpha1.IsMultiTenant(mtSrc))
+	assert.False(t, v1alpha1.IsMultiTenant(src))
+	assert.Equal(t, src.GetGroupVersionKind(), mtSrc.GetObjectKind().GroupVersionKind())
+}
+
+func TestEnsureNoStaleAdapter(t *testing.T) {
+	src := newTenancyTestSource()
+
+	stAdapter := NewAdapterKnService(src, nil)
+
+	mtAdapter := NewMTAdapterKnService(src)
+	OwnByServiceAccount(mtAdapter, newServiceAccount(AsMultiTenant(src), []kmeta.OwnerRefable{src}))
+
+	unrelatedAdapter := stAdapter.DeepCopy()
+	unrelatedAdapter.OwnerReferences = nil
+
+	testCases := map[string]struct {
+		multiTenant  bool
+		adapters     []runtime.Object
+		expectDelete string
+	}{
+		"Multi-tenant, single-tenant adapter exists": {
+			multiTenant:  true,
+			adapters:     []runtime.Object{stAdapter, mtAdapter},
+			expectDelete: stAdapter.Name,
+		},
+		"Multi-tenant, single-tenant adapter does not exist": {
+			multiTenant: true,
+			adapters:    []runtime.Object{mtAdapter},
+		},
+		"Multi-tenant, single-tenant adapter not owned by the source": {
+			multiTenant: true,
+			adapters:    []runtime.Object{unrelatedAdapter},
+		},
+		"Single-tenant, multi-tenant adapter exists": {
+			multiTenant:  false,
+			adapters:     []runtime.Object{stAdapter, mtAdapter},
+			expectDelete: mtAdapter.Name,
+		},
+		"Single-tenant, multi-tenant adapter does not exist": {
+			multiTenant: false,
+			adapters:    []runtime.Object{stAdapter},
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, a := range tc.adapters {
+				require.NoError(t, indexer.Add(a))
+			}
+			cli := fakeservingclient.NewSimpleClientset(tc.adapters...)
+
+			ctx := controller.WithEventRecorder(context.Background(), record.NewFakeRecorder(1))
+			ctx = v1alpha1.WithSource(ctx, src)
+
+			r := &GenericServiceReconciler{
+				Client: cli.ServingV1().Services,
+				Lister: servinglistersv1.NewServiceLister(indexer).Services,
+			}
+
+			err := r.EnsureNoStaleAdapter(ctx, src, tc.multiTenant)
+			require.NoError(t, err)
+
+			var deleted []string
+			for _, a := range cli.Actions() {
+				if a, ok := a.(clientgotesting.DeleteAction); ok {
+					deleted = append(deleted, a.GetName())
+				}
+			}
+
+			if tc.expectDelete == "" {
+				assert.Empty(t, deleted)
+				return
+			}
+			assert.Equal(t, []string{tc.expectDelete}, deleted)
+		})
+	}
+}
+
+// newTenancyTestSource returns a source object for tests about tenancy modes.
+func newTenancyTestSource() *v1alpha1.WebhookSource {
+	src := &v1alpha1.WebhookSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: tTenancyNs,
+			Name:      "test",
+			UID:       "00000000-0000-0000-0000-000000000000",
+		},
+	}
+	src.SetGroupVersionKind(src.GetGroupVersionKind())
+
+	return src
+}
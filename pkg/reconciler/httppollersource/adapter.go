@@ -65,6 +65,7 @@ func (r *Reconciler) BuildAdapter(src v1alpha1.EventSource, sinkURI *apis.URL) *
 		resource.Image(r.adapterCfg.Image),
 
 		resource.EnvVars(makeHTTPPollerEnvs(typedSrc)...),
+		resource.EnvVars(common.MakeCustomSchemaValidationEnvs(typedSrc.Spec.SchemaValidation)...),
 		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
 	)
 }
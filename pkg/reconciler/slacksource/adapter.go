@@ -18,6 +18,8 @@ package slacksource
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -25,6 +27,7 @@ import (
 	"knative.dev/eventing/pkg/reconciler/source"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
@@ -33,8 +36,33 @@ import (
 )
 
 const (
-	envSlackAppID         = "SLACK_APP_ID"
-	envSlackSigningSecret = "SLACK_SIGNING_SECRET"
+	envSlackAppID             = "SLACK_APP_ID"
+	envSlackSigningSecret     = "SLACK_SIGNING_SECRET"
+	envSlackAddSigningSecrets = "SLACK_ADDITIONAL_SIGNING_SECRET"
+	envSlackAppToken          = "SLACK_APP_TOKEN"
+	envSlackAppsPrefix        = "SLACK_APPS_"
+	envSlackAllowedCIDRs      = "SLACK_ALLOWED_CIDRS"
+	envSlackTrustedProxyCIDRs = "SLACK_TRUSTED_PROXY_CIDRS"
+	envSlackEventTypeMode     = "SLACK_EVENT_TYPE_MODE"
+	envSlackResponseMode      = "SLACK_RESPONSE_MODE"
+	envSlackFilterEventTypes  = "SLACK_FILTER_EVENT_TYPES"
+	envSlackFilterExclSubtype = "SLACK_FILTER_EXCLUDED_SUBTYPES"
+	envSlackFilterChannels    = "SLACK_FILTER_CHANNELS"
+	envSlackFilterUsers       = "SLACK_FILTER_USERS"
+	envSlackFilterExclBots    = "SLACK_FILTER_EXCLUDE_BOTS"
+	envSlackFilterExclSelf    = "SLACK_FILTER_EXCLUDE_SELF"
+	envSlackBotToken          = "SLACK_BOT_TOKEN"
+	envSlackEnrichmentAPIURL  = "SLACK_ENRICHMENT_API_URL"
+	envSlackEnrichmentTTL     = "SLACK_ENRICHMENT_CACHE_TTL"
+	envSlackDeduplication     = "SLACK_DEDUPLICATION"
+	envSlackDeduplicationTTL  = "SLACK_DEDUPLICATION_TTL"
+	envSlackAsyncDelivery     = "SLACK_ASYNC_DELIVERY"
+	envSlackAsyncQueueSize    = "SLACK_ASYNC_QUEUE_SIZE"
+	envSlackAsyncWorkers      = "SLACK_ASYNC_WORKERS"
+	envSlackAsyncRetries      = "SLACK_ASYNC_RETRIES"
+	envSlackAsyncBackoffDelay = "SLACK_ASYNC_BACKOFF_DELAY"
+	envSlackAsyncDLS          = "SLACK_ASYNC_DEAD_LETTER_SINK"
+	envSlackMultiTenant       = "SLACK_MULTI_TENANT"
 )
 
 // adapterConfig contains properties used to configure the source's adapter.
@@ -42,6 +70,9 @@ const (
 type adapterConfig struct {
 	// Container image
 	Image string `default:"gcr.io/triggermesh/slacksource-adapter"`
+	// Serve all sources of a namespace from a single adapter, unless
+	// overridden by the namespace's multi-tenancy label.
+	MultiTenant bool `envconfig:"MULTI_TENANT"`
 
 	// Configuration accessor for logging/metrics/tracing
 	configs source.ConfigAccessor
@@ -52,14 +83,41 @@ var _ common.AdapterServiceBuilder = (*Reconciler)(nil)
 
 // BuildAdapter implements common.AdapterDeploymentBuilder.
 func (r *Reconciler) BuildAdapter(src v1alpha1.EventSource, sinkURI *apis.URL) *servingv1.Service {
+	if v1alpha1.IsMultiTenant(src) {
+		// the multi-tenant adapter reads the configuration of each
+		// source from the API, the sink included
+		opts := []resource.ObjectOption{
+			resource.Image(r.adapterCfg.Image),
+
+			resource.EnvVar(envSlackMultiTenant, strconv.FormatBool(true)),
+			resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
+		}
+
+		if r.hasSocketModeSources(src.GetNamespace()) {
+			opts = append(opts, resource.PodAnnotation(autoscaling.MinScaleAnnotationKey, "1"))
+		}
+
+		return common.NewMTAdapterKnService(src, opts...)
+	}
+
 	typedSrc := src.(*v1alpha1.SlackSource)
 
-	return common.NewAdapterKnService(src, sinkURI,
+	opts := []resource.ObjectOption{
 		resource.Image(r.adapterCfg.Image),
 
 		resource.EnvVars(makeSlackEnvs(typedSrc)...),
+		resource.EnvVars(common.MakeRequestLimitsEnvs(typedSrc.Spec.RequestLimits)...),
+		resource.EnvVars(common.MakeSchemaValidationEnvs(typedSrc.Spec.SchemaValidation)...),
 		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
-	)
+	}
+
+	// in Socket Mode, the adapter receives no HTTP traffic and must not be
+	// scaled to zero
+	if typedSrc.Spec.SocketMode != nil {
+		opts = append(opts, resource.PodAnnotation(autoscaling.MinScaleAnnotationKey, "1"))
+	}
+
+	return common.NewAdapterKnService(src, sinkURI, opts...)
 }
 
 // RBACOwners implements common.AdapterDeploymentBuilder.
@@ -77,6 +135,26 @@ func (r *Reconciler) RBACOwners(namespace string) ([]kmeta.OwnerRefable, error)
 	return ownerRefables, nil
 }
 
+// hasSocketModeSources returns whether any SlackSource of the given namespace
+// receives events in Socket Mode, in which case the multi-tenant adapter must
+// not be scaled to zero. The desired state of the adapter is the same
+// regardless of the source being reconciled.
+func (r *Reconciler) hasSocketModeSources(namespace string) bool {
+	srcs, err := r.srcLister(namespace).List(labels.Everything())
+	if err != nil {
+		// listing from the informer's cache doesn't fail with a
+		// selector that matches everything
+		return false
+	}
+
+	for _, src := range srcs {
+		if src.Spec.SocketMode != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func makeSlackEnvs(src *v1alpha1.SlackSource) []corev1.EnvVar {
 	var slackEnvs []corev1.EnvVar
 
@@ -93,5 +171,210 @@ func makeSlackEnvs(src *v1alpha1.SlackSource) []corev1.EnvVar {
 		)
 	}
 
+	slackEnvs = append(slackEnvs, common.MakeIndexedValueFromEnvVars(
+		envSlackAddSigningSecrets, src.Spec.AdditionalSigningSecrets)...)
+
+	slackEnvs = append(slackEnvs, makeAppsEnvs(src.Spec.Apps)...)
+
+	if socketMode := src.Spec.SocketMode; socketMode != nil {
+		slackEnvs = common.MaybeAppendValueFromEnvVar(slackEnvs,
+			envSlackAppToken, socketMode.AppToken,
+		)
+	}
+
+	if ipAllowlist := src.Spec.IPAllowlist; ipAllowlist != nil {
+		slackEnvs = append(slackEnvs, corev1.EnvVar{
+			Name:  envSlackAllowedCIDRs,
+			Value: strings.Join(ipAllowlist.AllowedCIDRs, ","),
+		})
+
+		if len(ipAllowlist.TrustedProxyCIDRs) > 0 {
+			slackEnvs = append(slackEnvs, corev1.EnvVar{
+				Name:  envSlackTrustedProxyCIDRs,
+				Value: strings.Join(ipAllowlist.TrustedProxyCIDRs, ","),
+			})
+		}
+	}
+
+	if et := src.Spec.EventTypes; et != nil && et.Mode != nil {
+		slackEnvs = append(slackEnvs, corev1.EnvVar{
+			Name:  envSlackEventTypeMode,
+			Value: string(*et.Mode),
+		})
+	}
+
+	if inter := src.Spec.Interactivity; inter != nil && inter.ResponseMode != nil {
+		slackEnvs = append(slackEnvs, corev1.EnvVar{
+			Name:  envSlackResponseMode,
+			Value: string(*inter.ResponseMode),
+		})
+	}
+
+	if filters := src.Spec.Filters; filters != nil {
+		slackEnvs = append(slackEnvs, makeFiltersEnvs(filters)...)
+	}
+
+	if enrichment := src.Spec.Enrichment; enrichment != nil {
+		slackEnvs = append(slackEnvs, makeEnrichmentEnvs(enrichment)...)
+	}
+
+	if dedup := src.Spec.Deduplication; dedup != nil {
+		slackEnvs = append(slackEnvs, corev1.EnvVar{
+			Name:  envSlackDeduplication,
+			Value: strconv.FormatBool(true),
+		})
+
+		if ttl := dedup.TTL; ttl != nil {
+			slackEnvs = append(slackEnvs, corev1.EnvVar{
+				Name:  envSlackDeduplicationTTL,
+				Value: ttl.String(),
+			})
+		}
+	}
+
+	if async := src.Spec.AsyncDelivery; async != nil {
+		slackEnvs = append(slackEnvs, makeAsyncDeliveryEnvs(async)...)
+	}
+
 	return slackEnvs
 }
+
+// makeFiltersEnvs returns the environment variables which configure the
+// filtering of events.
+func makeFiltersEnvs(filters *v1alpha1.SlackEventFilters) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	lists := []struct {
+		name string
+		vals []string
+	}{
+		{envSlackFilterEventTypes, filters.EventTypes},
+		{envSlackFilterExclSubtype, filters.ExcludedSubtypes},
+		{envSlackFilterChannels, filters.Channels},
+		{envSlackFilterUsers, filters.Users},
+	}
+	for _, l := range lists {
+		if len(l.vals) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  l.name,
+				Value: strings.Join(l.vals, ","),
+			})
+		}
+	}
+
+	if excl := filters.ExcludeBots; excl != nil && *excl {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSlackFilterExclBots,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	if excl := filters.ExcludeSelf; excl != nil && *excl {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSlackFilterExclSelf,
+			Value: strconv.FormatBool(true),
+		})
+	}
+
+	return envs
+}
+
+// makeEnrichmentEnvs returns the environment variables which configure the
+// enrichment of events.
+func makeEnrichmentEnvs(enrichment *v1alpha1.SlackEnrichment) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	envs = common.MaybeAppendValueFromEnvVar(envs,
+		envSlackBotToken, enrichment.BotToken,
+	)
+
+	if ttl := enrichment.CacheTTL; ttl != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSlackEnrichmentTTL,
+			Value: ttl.String(),
+		})
+	}
+
+	if apiURL := enrichment.APIURL; apiURL != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSlackEnrichmentAPIURL,
+			Value: apiURL.String(),
+		})
+	}
+
+	return envs
+}
+
+// makeAppsEnvs returns the environment variables which describe the given
+// Slack apps, named after envSlackAppsPrefix followed by the index of the app
+// and the name of the attribute (e.g. SLACK_APPS_0_ID).
+func makeAppsEnvs(apps []v1alpha1.SlackApp) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	for i, app := range apps {
+		prefix := envSlackAppsPrefix + strconv.Itoa(i)
+
+		envs = append(envs, corev1.EnvVar{
+			Name:  prefix + "_ID",
+			Value: app.AppID,
+		})
+
+		envs = common.MaybeAppendValueFromEnvVar(envs,
+			prefix+"_SIGNING_SECRET", app.SigningSecret,
+		)
+
+		envs = append(envs, common.MakeIndexedValueFromEnvVars(
+			prefix+"_ADDITIONAL_SIGNING_SECRET", app.AdditionalSigningSecrets)...)
+
+		if p := app.EventTypePrefix; p != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  prefix + "_EVENT_TYPE_PREFIX",
+				Value: *p,
+			})
+		}
+	}
+
+	return envs
+}
+
+// makeAsyncDeliveryEnvs returns the environment variables which configure the
+// asynchronous delivery of events.
+func makeAsyncDeliveryEnvs(async *v1alpha1.SlackAsyncDelivery) []corev1.EnvVar {
+	envs := []corev1.EnvVar{{
+		Name:  envSlackAsyncDelivery,
+		Value: strconv.FormatBool(true),
+	}}
+
+	ints := []struct {
+		name string
+		val  *int32
+	}{
+		{envSlackAsyncQueueSize, async.QueueSize},
+		{envSlackAsyncWorkers, async.Workers},
+		{envSlackAsyncRetries, async.Retries},
+	}
+	for _, i := range ints {
+		if i.val != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  i.name,
+				Value: strconv.FormatInt(int64(*i.val), 10),
+			})
+		}
+	}
+
+	if bd := async.BackoffDelay; bd != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSlackAsyncBackoffDelay,
+			Value: bd.String(),
+		})
+	}
+
+	if dls := async.DeadLetterSink; dls != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envSlackAsyncDLS,
+			Value: dls.String(),
+		})
+	}
+
+	return envs
+}
@@ -19,6 +19,8 @@ package slacksource
 import (
 	"context"
 
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+
 	"knative.dev/pkg/reconciler"
 
 	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
@@ -33,6 +35,7 @@ type Reconciler struct {
 	adapterCfg *adapterConfig
 
 	srcLister func(namespace string) listersv1alpha1.SlackSourceNamespaceLister
+	nsLister  corelistersv1.NamespaceLister
 }
 
 // Check that our Reconciler implements Interface
@@ -43,5 +46,18 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, src *v1alpha1.SlackSourc
 	// inject source into context for usage in reconciliation logic
 	ctx = v1alpha1.WithSource(ctx, src)
 
+	isMultiTenant, err := common.IsMultiTenantNamespace(r.nsLister, src, src.Namespace, r.adapterCfg.MultiTenant)
+	if err != nil {
+		return err
+	}
+
+	if err := r.base.EnsureNoStaleAdapter(ctx, src, isMultiTenant); err != nil {
+		return err
+	}
+
+	if isMultiTenant {
+		ctx = v1alpha1.WithSource(ctx, common.AsMultiTenant(src))
+	}
+
 	return r.base.ReconcileSource(ctx, r)
 }
@@ -52,6 +52,7 @@ func reconcilerCtor(cfg *adapterConfig) Ctor {
 			base:       NewTestServiceReconciler(ctx, ls),
 			adapterCfg: cfg,
 			srcLister:  ls.GetSlackSourceLister().SlackSources,
+			nsLister:   ls.GetNamespaceLister(),
 		}
 
 		return reconcilerv1alpha1.NewReconciler(ctx, logging.FromContext(ctx),
@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slacksource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"knative.dev/eventing/pkg/reconciler/source"
+	"knative.dev/serving/pkg/apis/autoscaling"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+	"github.com/triggermesh/knative-sources/pkg/reconciler/common"
+	. "github.com/triggermesh/knative-sources/pkg/reconciler/testing"
+)
+
+func TestBuildMultiTenantAdapter(t *testing.T) {
+	src := newEventSource()
+	src.Spec.SigningSecret = &v1alpha1.ValueFromField{Value: "secret"}
+
+	socketModeSrc := newEventSource()
+	socketModeSrc.Name += "-socket"
+	socketModeSrc.Spec.SocketMode = &v1alpha1.SlackSocketMode{
+		AppToken: v1alpha1.ValueFromField{Value: "xapp-token"},
+	}
+
+	testCases := map[string]struct {
+		srcs            []runtime.Object
+		expectMinScaled bool
+	}{
+		"HTTP sources only": {
+			srcs: []runtime.Object{src},
+		},
+		"Source in Socket Mode in the namespace": {
+			srcs:            []runtime.Object{src, socketModeSrc},
+			expectMinScaled: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			ls := NewListers(NewScheme(), tc.srcs)
+
+			r := &Reconciler{
+				adapterCfg: &adapterConfig{
+					Image:   "registry/image:tag",
+					configs: &source.EmptyVarsGenerator{},
+				},
+				srcLister: ls.GetSlackSourceLister().SlackSources,
+			}
+
+			svc := r.BuildAdapter(common.AsMultiTenant(src), nil)
+
+			assert.Equal(t, "slacksource-adapter", svc.Name)
+			assert.Equal(t, "slacksource-mt-adapter", svc.Spec.Template.Spec.ServiceAccountName)
+			assert.Empty(t, svc.OwnerReferences, "Ownership is expected to be delegated by the generic reconciler")
+
+			envs := svc.Spec.Template.Spec.Containers[0].Env
+			assert.Contains(t, envs, corev1.EnvVar{Name: envSlackMultiTenant, Value: "true"})
+			for _, e := range envs {
+				assert.NotEqual(t, envSlackSigningSecret, e.Name, "Source settings should not be passed via the environment")
+			}
+
+			minScale, ok := svc.Spec.Template.Annotations[autoscaling.MinScaleAnnotationKey]
+			if tc.expectMinScaled {
+				assert.Equal(t, "1", minScale)
+			} else {
+				assert.False(t, ok, "Adapter should be allowed to scale to zero")
+			}
+		})
+	}
+}
@@ -34,6 +34,15 @@ import (
 func TestControllerConstructor(t *testing.T, ctor injection.ControllerConstructor) {
 	t.Helper()
 
+	TestControllerConstructorWithInformers(t, ctor, 0)
+}
+
+// TestControllerConstructorWithInformers tests that a controller constructor
+// meets our requirements, for controllers which inject the given number of
+// informers in addition to the standard ones.
+func TestControllerConstructorWithInformers(t *testing.T, ctor injection.ControllerConstructor, extraInformers int) {
+	t.Helper()
+
 	defer func() {
 		if r := recover(); r != nil {
 			t.Errorf("Unexpected panic: %v", r)
@@ -43,7 +52,7 @@ func TestControllerConstructor(t *testing.T, ctor injection.ControllerConstructo
 	ctx, informers := rt.SetupFakeContext(t)
 
 	// expected informers: Source, Deployment, ServiceAccount, RoleBinding
-	if expect, got := 4, len(informers); got != expect {
+	if expect, got := 4+extraInformers, len(informers); got != expect {
 		t.Errorf("Expected %d injected informers, got %d", expect, got)
 	}
 
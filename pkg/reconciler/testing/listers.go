@@ -128,6 +128,11 @@ func (l *Listers) GetServiceAccountLister() corelistersv1.ServiceAccountLister {
 	return corelistersv1.NewServiceAccountLister(l.IndexerFor(&corev1.ServiceAccount{}))
 }
 
+// GetNamespaceLister returns a lister for Namespace objects.
+func (l *Listers) GetNamespaceLister() corelistersv1.NamespaceLister {
+	return corelistersv1.NewNamespaceLister(l.IndexerFor(&corev1.Namespace{}))
+}
+
 // GetRoleBindingLister returns a lister for RoleBinding objects
 func (l *Listers) GetRoleBindingLister() rbaclistersv1.RoleBindingLister {
 	return rbaclistersv1.NewRoleBindingLister(l.IndexerFor(&rbacv1.RoleBinding{}))
@@ -17,7 +17,10 @@ limitations under the License.
 package webhooksource
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -33,10 +36,46 @@ import (
 )
 
 const (
-	envWebhookEventType         = "WEBHOOK_EVENT_TYPE"
-	envWebhookEventSource       = "WEBHOOK_EVENT_SOURCE"
-	envWebhookBasicAuthUsername = "WEBHOOK_BASICAUTH_USERNAME"
-	envWebhookBasicAuthPassword = "WEBHOOK_BASICAUTH_PASSWORD"
+	envWebhookEventType             = "WEBHOOK_EVENT_TYPE"
+	envWebhookEventSource           = "WEBHOOK_EVENT_SOURCE"
+	envWebhookBasicAuthUsername     = "WEBHOOK_BASICAUTH_USERNAME"
+	envWebhookBasicAuthPassword     = "WEBHOOK_BASICAUTH_PASSWORD"
+	envWebhookBasicAuthAddPasswords = "WEBHOOK_BASICAUTH_ADDITIONAL_PASSWORD"
+	envWebhookJWTIssuer             = "WEBHOOK_JWT_ISSUER"
+	envWebhookJWTAudiences          = "WEBHOOK_JWT_AUDIENCES"
+	envWebhookJWTAlgorithms         = "WEBHOOK_JWT_ALGORITHMS"
+	envWebhookJWTJWKS               = "WEBHOOK_JWT_JWKS"
+	envWebhookJWTJWKSURL            = "WEBHOOK_JWT_JWKS_URL"
+	envWebhookJWTJWKSCacheDuration  = "WEBHOOK_JWT_JWKS_CACHE_DURATION"
+	envWebhookJWTClaimsToExtensions = "WEBHOOK_JWT_CLAIMS_TO_EXTENSIONS"
+	envWebhookClientCertCABundle    = "WEBHOOK_CLIENT_CERT_CA_BUNDLE"
+	envWebhookClientCertHeader      = "WEBHOOK_CLIENT_CERT_HEADER"
+	envWebhookClientCertProxyCIDRs  = "WEBHOOK_CLIENT_CERT_TRUSTED_PROXY_CIDRS"
+	envWebhookClientCertSubjects    = "WEBHOOK_CLIENT_CERT_ALLOWED_SUBJECTS"
+	envWebhookClientCertSANs        = "WEBHOOK_CLIENT_CERT_ALLOWED_SANS"
+	envWebhookAsyncDelivery         = "WEBHOOK_ASYNC_DELIVERY"
+	envWebhookAsyncQueueSize        = "WEBHOOK_ASYNC_QUEUE_SIZE"
+	envWebhookAsyncRetries          = "WEBHOOK_ASYNC_RETRIES"
+	envWebhookAsyncBackoffDelay     = "WEBHOOK_ASYNC_BACKOFF_DELAY"
+	envWebhookCORSAllowedOrigins    = "WEBHOOK_CORS_ALLOWED_ORIGINS"
+	envWebhookCORSAllowedMethods    = "WEBHOOK_CORS_ALLOWED_METHODS"
+	envWebhookCORSAllowedHeaders    = "WEBHOOK_CORS_ALLOWED_HEADERS"
+	envWebhookCORSMaxAge            = "WEBHOOK_CORS_MAX_AGE"
+	envWebhookAllowedCIDRs          = "WEBHOOK_ALLOWED_CIDRS"
+	envWebhookTrustedProxyCIDRs     = "WEBHOOK_TRUSTED_PROXY_CIDRS"
+	envWebhookBodyDecoding          = "WEBHOOK_BODY_DECODING"
+	envWebhookMultipartFiles        = "WEBHOOK_MULTIPART_FILES"
+	envWebhookBatchSplitting        = "WEBHOOK_BATCH_SPLITTING"
+	envWebhookBatchPath             = "WEBHOOK_BATCH_PATH"
+	envWebhookBatchIDField          = "WEBHOOK_BATCH_ID_FIELD"
+	envWebhookBatchPartialFailure   = "WEBHOOK_BATCH_PARTIAL_FAILURE"
+	envWebhookIdempotencyKeyHeader  = "WEBHOOK_IDEMPOTENCY_KEY_HEADER"
+	envWebhookIdempotencyKeyField   = "WEBHOOK_IDEMPOTENCY_KEY_FIELD"
+	envWebhookIdempotencyTTL        = "WEBHOOK_IDEMPOTENCY_TTL"
+	envWebhookResponseStatusCode    = "WEBHOOK_RESPONSE_STATUS_CODE"
+	envWebhookResponseHeaders       = "WEBHOOK_RESPONSE_HEADERS"
+	envWebhookResponseBody          = "WEBHOOK_RESPONSE_BODY"
+	envWebhookMultiTenant           = "WEBHOOK_MULTI_TENANT"
 )
 
 // adapterConfig contains properties used to configure the adapter.
@@ -44,6 +83,9 @@ const (
 type adapterConfig struct {
 	// Container image
 	Image string `default:"gcr.io/triggermesh/webhook-adapter"`
+	// Serve all sources of a namespace from a single adapter, unless
+	// overridden by the namespace's multi-tenancy label.
+	MultiTenant bool `envconfig:"MULTI_TENANT"`
 
 	// Configuration accessor for logging/metrics/tracing
 	configs source.ConfigAccessor
@@ -54,12 +96,25 @@ var _ common.AdapterServiceBuilder = (*Reconciler)(nil)
 
 // BuildAdapter implements common.AdapterDeploymentBuilder.
 func (r *Reconciler) BuildAdapter(src v1alpha1.EventSource, sinkURI *apis.URL) *servingv1.Service {
+	if v1alpha1.IsMultiTenant(src) {
+		// the multi-tenant adapter reads the configuration of each
+		// source from the API, the sink included
+		return common.NewMTAdapterKnService(src,
+			resource.Image(r.adapterCfg.Image),
+
+			resource.EnvVar(envWebhookMultiTenant, strconv.FormatBool(true)),
+			resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
+		)
+	}
+
 	typedSrc := src.(*v1alpha1.WebhookSource)
 
 	return common.NewAdapterKnService(src, sinkURI,
 		resource.Image(r.adapterCfg.Image),
 
 		resource.EnvVars(makeWebhookEnvs(typedSrc)...),
+		resource.EnvVars(common.MakeRequestLimitsEnvs(typedSrc.Spec.RequestLimits)...),
+		resource.EnvVars(common.MakeCustomSchemaValidationEnvs(typedSrc.Spec.SchemaValidation)...),
 		resource.EnvVars(r.adapterCfg.configs.ToEnvVars()...),
 	)
 }
@@ -101,5 +156,289 @@ func makeWebhookEnvs(src *v1alpha1.WebhookSource) []corev1.EnvVar {
 		)
 	}
 
+	envs = append(envs, common.MakeIndexedValueFromEnvVars(
+		envWebhookBasicAuthAddPasswords, src.Spec.AdditionalBasicAuthPasswords)...)
+
+	if jwtAuth := src.Spec.JWTAuth; jwtAuth != nil {
+		envs = append(envs, makeJWTAuthEnvs(jwtAuth)...)
+	}
+
+	if certAuth := src.Spec.ClientCertAuth; certAuth != nil {
+		envs = append(envs, makeClientCertAuthEnvs(certAuth)...)
+	}
+
+	if async := src.Spec.AsyncDelivery; async != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookAsyncDelivery,
+			Value: strconv.FormatBool(true),
+		})
+
+		if qs := async.QueueSize; qs != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookAsyncQueueSize,
+				Value: strconv.FormatInt(int64(*qs), 10),
+			})
+		}
+
+		if r := async.Retries; r != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookAsyncRetries,
+				Value: strconv.FormatInt(int64(*r), 10),
+			})
+		}
+
+		if bd := async.BackoffDelay; bd != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookAsyncBackoffDelay,
+				Value: bd.String(),
+			})
+		}
+	}
+
+	if ipAllowlist := src.Spec.IPAllowlist; ipAllowlist != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookAllowedCIDRs,
+			Value: strings.Join(ipAllowlist.AllowedCIDRs, ","),
+		})
+
+		if len(ipAllowlist.TrustedProxyCIDRs) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookTrustedProxyCIDRs,
+				Value: strings.Join(ipAllowlist.TrustedProxyCIDRs, ","),
+			})
+		}
+	}
+
+	if cors := src.Spec.CORS; cors != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookCORSAllowedOrigins,
+			Value: strings.Join(cors.AllowedOrigins, ","),
+		})
+
+		if len(cors.AllowedMethods) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookCORSAllowedMethods,
+				Value: strings.Join(cors.AllowedMethods, ","),
+			})
+		}
+
+		if len(cors.AllowedHeaders) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookCORSAllowedHeaders,
+				Value: strings.Join(cors.AllowedHeaders, ","),
+			})
+		}
+
+		if ma := cors.MaxAge; ma != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookCORSMaxAge,
+				Value: ma.String(),
+			})
+		}
+	}
+
+	if bd := src.Spec.BodyDecoding; bd != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookBodyDecoding,
+			Value: strconv.FormatBool(true),
+		})
+
+		if mf := bd.MultipartFiles; mf != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookMultipartFiles,
+				Value: string(*mf),
+			})
+		}
+	}
+
+	if bs := src.Spec.BatchSplitting; bs != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookBatchSplitting,
+			Value: strconv.FormatBool(true),
+		})
+
+		if p := bs.Path; p != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookBatchPath,
+				Value: *p,
+			})
+		}
+
+		if f := bs.IDField; f != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookBatchIDField,
+				Value: *f,
+			})
+		}
+
+		if pf := bs.PartialFailure; pf != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookBatchPartialFailure,
+				Value: string(*pf),
+			})
+		}
+	}
+
+	if idem := src.Spec.Idempotency; idem != nil {
+		if kh := idem.KeyHeader; kh != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookIdempotencyKeyHeader,
+				Value: *kh,
+			})
+		}
+
+		if kf := idem.KeyField; kf != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookIdempotencyKeyField,
+				Value: *kf,
+			})
+		}
+
+		if ttl := idem.TTL; ttl != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookIdempotencyTTL,
+				Value: ttl.String(),
+			})
+		}
+	}
+
+	if resp := src.Spec.Response; resp != nil {
+		if sc := resp.StatusCode; sc != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookResponseStatusCode,
+				Value: strconv.FormatInt(int64(*sc), 10),
+			})
+		}
+
+		if len(resp.Headers) > 0 {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookResponseHeaders,
+				Value: jsonMap(resp.Headers),
+			})
+		}
+
+		if b := resp.Body; b != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookResponseBody,
+				Value: *b,
+			})
+		}
+	}
+
+	return envs
+}
+
+func makeJWTAuthEnvs(jwtAuth *v1alpha1.WebhookJWTAuth) []corev1.EnvVar {
+	envs := []corev1.EnvVar{{
+		Name:  envWebhookJWTIssuer,
+		Value: jwtAuth.Issuer,
+	}}
+
+	if auds := jwtAuth.Audiences; len(auds) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookJWTAudiences,
+			Value: strings.Join(auds, ","),
+		})
+	}
+
+	if algs := jwtAuth.Algorithms; len(algs) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookJWTAlgorithms,
+			Value: strings.Join(algs, ","),
+		})
+	}
+
+	jwks := jwtAuth.JWKS
+
+	switch {
+	case jwks.ValueFromConfigMap != nil:
+		envs = append(envs, corev1.EnvVar{
+			Name: envWebhookJWTJWKS,
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: jwks.ValueFromConfigMap,
+			},
+		})
+
+	case jwks.URL != nil:
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookJWTJWKSURL,
+			Value: jwks.URL.String(),
+		})
+
+		if cd := jwks.CacheDuration; cd != nil {
+			envs = append(envs, corev1.EnvVar{
+				Name:  envWebhookJWTJWKSCacheDuration,
+				Value: cd.String(),
+			})
+		}
+
+	default:
+		envs = common.MaybeAppendValueFromEnvVar(envs,
+			envWebhookJWTJWKS, jwks.ValueFromField,
+		)
+	}
+
+	if c2e := jwtAuth.ClaimsToExtensions; len(c2e) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookJWTClaimsToExtensions,
+			Value: jsonMap(c2e),
+		})
+	}
+
 	return envs
 }
+
+func makeClientCertAuthEnvs(certAuth *v1alpha1.WebhookClientCertAuth) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+
+	envs = common.MaybeAppendValueFromEnvVar(envs,
+		envWebhookClientCertCABundle, certAuth.CABundle,
+	)
+
+	if hdr := certAuth.Header; hdr != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookClientCertHeader,
+			Value: *hdr,
+		})
+	}
+
+	if cidrs := certAuth.TrustedProxyCIDRs; len(cidrs) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookClientCertProxyCIDRs,
+			Value: strings.Join(cidrs, ","),
+		})
+	}
+
+	if subjs := certAuth.AllowedSubjects; len(subjs) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookClientCertSubjects,
+			Value: jsonList(subjs),
+		})
+	}
+
+	if sans := certAuth.AllowedSANs; len(sans) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  envWebhookClientCertSANs,
+			Value: jsonList(sans),
+		})
+	}
+
+	return envs
+}
+
+// jsonMap returns the JSON encoding of the given map. Unlike maps in the
+// native envconfig format, its keys and values may contain commas and colons.
+func jsonMap(m map[string]string) string {
+	// encoding a map of strings can't fail
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+// jsonList returns the JSON encoding of the given list. Unlike lists in the
+// native envconfig format, its elements may contain commas, such as in
+// distinguished names with multiple RDNs.
+func jsonList(l []string) string {
+	// encoding a list of strings can't fail
+	b, _ := json.Marshal(l)
+	return string(b)
+}
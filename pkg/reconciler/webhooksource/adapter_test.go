@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	webhookadapter "github.com/triggermesh/knative-sources/pkg/adapter/webhooksource"
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestClientCertAuthEnvsRoundTrip(t *testing.T) {
+	certAuth := &v1alpha1.WebhookClientCertAuth{
+		AllowedSubjects: []string{"CN=partner,O=Partner", "CN=other"},
+		AllowedSANs:     []string{"spiffe://example.com/partner", "https://example.com/a,b"},
+	}
+
+	src := newEventSource()
+	src.Spec.ClientCertAuth = certAuth
+
+	for _, e := range makeWebhookEnvs(src) {
+		require.NoError(t, os.Setenv(e.Name, e.Value))
+		name := e.Name
+		t.Cleanup(func() { _ = os.Unsetenv(name) })
+	}
+
+	env := webhookadapter.EnvAccessor()
+	require.NoError(t, envconfig.Process("", env))
+
+	// the adapter's env accessor is unexported, its fields aren't
+	envVal := reflect.ValueOf(env).Elem()
+	assert.EqualValues(t, certAuth.AllowedSubjects, envVal.FieldByName("ClientCertAllowedSubjects").Interface())
+	assert.EqualValues(t, certAuth.AllowedSANs, envVal.FieldByName("ClientCertAllowedSANs").Interface())
+}
@@ -21,9 +21,16 @@ import (
 
 	"github.com/kelseyhightower/envconfig"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
 	"knative.dev/eventing/pkg/reconciler/source"
+	namespaceinformerv1 "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	serviceinformerv1 "knative.dev/serving/pkg/client/injection/informers/serving/v1/service"
 
 	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
 	informerv1alpha1 "github.com/triggermesh/knative-sources/pkg/client/generated/injection/informers/sources/v1alpha1/webhooksource"
@@ -48,12 +55,18 @@ func NewController(
 
 	informer := informerv1alpha1.Get(ctx)
 
+	nsInformer := namespaceinformerv1.Get(ctx)
+
 	r := &Reconciler{
 		adapterCfg: adapterCfg,
 		srcLister:  informer.Lister().WebhookSources,
+		nsLister:   nsInformer.Lister(),
 	}
 	impl := reconcilerv1alpha1.NewImpl(ctx, r)
 
+	logger := logging.FromContext(ctx)
+
+	// single-tenant adapters are owned by their source
 	r.base = common.NewGenericServiceReconciler(
 		ctx,
 		typ.GetGroupVersionKind(),
@@ -61,6 +74,23 @@ func NewController(
 		impl.EnqueueControllerOf,
 	)
 
+	enqueueSourcesInNamespaceOf := common.EnqueueObjectsInNamespaceOf(informer.Informer(), impl.FilteredGlobalResync, logger)
+
+	// multi-tenant adapters are owned by the ServiceAccount shared by all
+	// sources of a namespace
+	serviceinformerv1.Get(ctx).Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.ChainFilterFuncs(
+			controller.FilterWithName(common.MTAdapterObjectName(typ)),
+			controller.FilterControllerGVK(corev1.SchemeGroupVersion.WithKind("ServiceAccount")),
+		),
+		Handler: controller.HandleAll(enqueueSourcesInNamespaceOf),
+	})
+
+	// the tenancy mode can be selected per namespace
+	nsInformer.Informer().AddEventHandler(
+		common.EnqueueObjectsOnTenancyModeChange(typ, informer.Informer(), impl.FilteredGlobalResync),
+	)
+
 	informer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
 	return impl
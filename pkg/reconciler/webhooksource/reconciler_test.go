@@ -52,6 +52,7 @@ func reconcilerCtor(cfg *adapterConfig) Ctor {
 			base:       NewTestServiceReconciler(ctx, ls),
 			adapterCfg: cfg,
 			srcLister:  ls.GetWebhookSourceLister().WebhookSources,
+			nsLister:   ls.GetNamespaceLister(),
 		}
 
 		return reconcilerv1alpha1.NewReconciler(ctx, logging.FromContext(ctx),
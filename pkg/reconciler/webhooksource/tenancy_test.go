@@ -0,0 +1,50 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooksource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/eventing/pkg/reconciler/source"
+
+	"github.com/triggermesh/knative-sources/pkg/reconciler/common"
+)
+
+func TestBuildMultiTenantAdapter(t *testing.T) {
+	src := newEventSource()
+
+	r := adapterBuilder(&adapterConfig{
+		Image:   "registry/image:tag",
+		configs: &source.EmptyVarsGenerator{},
+	})
+
+	svc := r.BuildAdapter(common.AsMultiTenant(src), nil)
+
+	assert.Equal(t, "webhooksource-adapter", svc.Name)
+	assert.Equal(t, "webhooksource-mt-adapter", svc.Spec.Template.Spec.ServiceAccountName)
+	assert.Empty(t, svc.OwnerReferences, "Ownership is expected to be delegated by the generic reconciler")
+
+	envs := svc.Spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, envs, corev1.EnvVar{Name: envWebhookMultiTenant, Value: "true"})
+	for _, e := range envs {
+		assert.NotEqual(t, envWebhookEventType, e.Name, "Source settings should not be passed via the environment")
+	}
+}
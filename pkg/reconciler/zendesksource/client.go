@@ -0,0 +1,222 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zendesksource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nukosuke/go-zendesk/zendesk"
+)
+
+const (
+	// listPageSize is the number of items requested per page when listing
+	// Zendesk resources. This is the maximum allowed by the Zendesk API.
+	listPageSize = 100
+	// maxListPages is the maximum number of pages followed when listing
+	// Zendesk resources, which prevents a reconciliation from looping
+	// endlessly over pages returned by a misbehaving API.
+	maxListPages = 100
+
+	// apiRequestTimeout is the maximum duration of requests to the Zendesk API.
+	apiRequestTimeout = 30 * time.Second
+)
+
+// apiClient is a Zendesk client which can list all Targets and Triggers of an
+// account, across pages of results.
+type apiClient struct {
+	*zendesk.Client
+
+	// used to retrieve pages of results the Zendesk client can't request
+	httpClient *http.Client
+	cred       zendesk.Credential
+	// URL of the Zendesk account, which pages of results must belong to
+	accountURL *url.URL
+	// maximum number of pages of results followed when listing resources
+	maxPages int
+}
+
+// zendeskClient returns an initialized Zendesk client.
+func zendeskClient(email, subdomain, apiToken string) (*apiClient, error) {
+	httpClient := &http.Client{Timeout: apiRequestTimeout}
+
+	cred := zendesk.NewAPITokenCredential(email, apiToken)
+	client, err := zendesk.NewClient(httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("creating Zendesk client: %w", err)
+	}
+	if err := client.SetSubdomain(subdomain); err != nil {
+		return nil, fmt.Errorf("setting Zendesk subdomain: %w", err)
+	}
+	client.SetCredential(cred)
+
+	return &apiClient{
+		Client:     client,
+		httpClient: httpClient,
+		cred:       cred,
+		accountURL: &url.URL{
+			Scheme: "https",
+			Host:   subdomain + ".zendesk.com",
+		},
+		maxPages: maxListPages,
+	}, nil
+}
+
+// listTargets returns all the Targets of the Zendesk account.
+//
+// The Zendesk client only requests the first page of Targets, so subsequent
+// pages are retrieved by following the "next_page" URL of each page.
+func (c *apiClient) listTargets(ctx context.Context) ([]zendesk.Target, error) {
+	targets, page, err := c.GetTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for pages := 1; page.HasNext(); pages++ {
+		if pages == c.maxPages {
+			return nil, fmt.Errorf("listing Targets: more than %d pages of results", c.maxPages)
+		}
+
+		var data struct {
+			Targets []zendesk.Target `json:"targets"`
+			zendesk.Page
+		}
+		if err := c.getPage(ctx, *page.NextPage, &data); err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, data.Targets...)
+		page = data.Page
+	}
+
+	return targets, nil
+}
+
+// listTriggers returns all the Triggers of the Zendesk account.
+func (c *apiClient) listTriggers(ctx context.Context) ([]zendesk.Trigger, error) {
+	opts := &zendesk.TriggerListOptions{
+		PageOptions: zendesk.PageOptions{
+			PerPage: listPageSize,
+			Page:    1,
+		},
+	}
+
+	var triggers []zendesk.Trigger
+
+	for {
+		trgs, page, err := c.GetTriggers(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, trgs...)
+
+		if !page.HasNext() {
+			return triggers, nil
+		}
+		if opts.Page == c.maxPages {
+			return nil, fmt.Errorf("listing Triggers: more than %d pages of results", c.maxPages)
+		}
+		opts.Page++
+	}
+}
+
+// getPage retrieves the page of results at the given URL and decodes it into
+// the value pointed to by v.
+// The URL must belong to the Zendesk account, since the request carries the
+// account's credentials.
+func (c *apiClient) getPage(ctx context.Context, pageURL string, v interface{}) error {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return fmt.Errorf("parsing URL of page: %w", err)
+	}
+	if u.Scheme != c.accountURL.Scheme || u.Host != c.accountURL.Host {
+		return fmt.Errorf("URL of page %q doesn't belong to the Zendesk account %q", pageURL, c.accountURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.cred.Email(), c.cred.Secret())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &pageError{
+			status: resp.StatusCode,
+			body:   body,
+		}
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// apiError is implemented by errors which represent Zendesk API responses.
+type apiError interface {
+	error
+	Status() int
+	Body() io.ReadCloser
+}
+
+// Check that errors returned by the Zendesk client and by getPage can be
+// handled alike.
+var (
+	_ apiError = zendesk.Error{}
+	_ apiError = (*pageError)(nil)
+)
+
+// pageError is the error returned when a page of results can't be retrieved.
+type pageError struct {
+	status int
+	body   []byte
+}
+
+// Error implements the error interface.
+func (e *pageError) Error() string {
+	msg := string(e.body)
+	if msg == "" {
+		msg = http.StatusText(e.status)
+	}
+	return fmt.Sprintf("%d: %s", e.status, msg)
+}
+
+// Status returns the HTTP status code of the Zendesk API response.
+func (e *pageError) Status() int {
+	return e.status
+}
+
+// Body returns the body of the Zendesk API response.
+func (e *pageError) Body() io.ReadCloser {
+	return ioutil.NopCloser(bytes.NewReader(e.body))
+}
@@ -0,0 +1,432 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zendesksource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/tools/record"
+
+	"knative.dev/pkg/controller"
+
+	"github.com/nukosuke/go-zendesk/zendesk"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+const (
+	tZendeskEmail = "jane@example.com"
+	tZendeskToken = "t0k3n"
+
+	// number of items per page of results returned by fakeZendeskAPI
+	tPageSize = 2
+)
+
+func TestListTargets(t *testing.T) {
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 5; i++ {
+		api.addTarget("target-" + strconv.Itoa(i))
+	}
+
+	targets, err := api.client().listTargets(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, targets, 5)
+	for i, trg := range targets {
+		assert.Equal(t, "target-"+strconv.Itoa(i), trg.Title)
+	}
+}
+
+func TestListTriggers(t *testing.T) {
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 5; i++ {
+		api.addTrigger("trigger-" + strconv.Itoa(i))
+	}
+
+	triggers, err := api.client().listTriggers(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, triggers, 5)
+	for i, trg := range triggers {
+		assert.Equal(t, "trigger-"+strconv.Itoa(i), trg.Title)
+	}
+}
+
+func TestListTooManyPages(t *testing.T) {
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 5; i++ {
+		api.addTarget("target-" + strconv.Itoa(i))
+		api.addTrigger("trigger-" + strconv.Itoa(i))
+	}
+
+	// 5 items are served in 3 pages
+	client := api.client()
+	client.maxPages = 2
+
+	_, err := client.listTargets(context.Background())
+	assert.EqualError(t, err, "listing Targets: more than 2 pages of results")
+
+	_, err = client.listTriggers(context.Background())
+	assert.EqualError(t, err, "listing Triggers: more than 2 pages of results")
+
+	assert.Equal(t, 4, api.requests, "No page should have been requested beyond the limit")
+
+	client.maxPages = 3
+
+	_, err = client.listTargets(context.Background())
+	assert.NoError(t, err)
+	_, err = client.listTriggers(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestListTargetsDenied(t *testing.T) {
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 5; i++ {
+		api.addTarget("target-" + strconv.Itoa(i))
+	}
+	api.denyPage = 2
+
+	_, err := api.client().listTargets(context.Background())
+	assert.Error(t, err)
+	assert.True(t, isDenied(err), "Error should indicate a denied request")
+}
+
+func TestListTargetsForeignNextPage(t *testing.T) {
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 5; i++ {
+		api.addTarget("target-" + strconv.Itoa(i))
+	}
+	api.nextPageURL = "https://attacker.example.com"
+
+	_, err := api.client().listTargets(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "doesn't belong to the Zendesk account")
+	assert.Equal(t, 1, api.requests, "No request should have been sent outside of the Zendesk account")
+}
+
+func TestEnsureTargetOnLaterPage(t *testing.T) {
+	const title = "io.triggermesh.zendesksource.ns.name"
+
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 4; i++ {
+		api.addTarget("target-" + strconv.Itoa(i))
+	}
+	existing := api.addTarget(title)
+
+	status := &v1alpha1.ZendeskSourceStatus{}
+	desired := desiredTarget(title, existing.TargetURL, "", "")
+
+	target, err := ensureTarget(testContext(), status, api.client(), desired)
+	require.NoError(t, err)
+
+	assert.Equal(t, existing.ID, target.ID)
+	assert.Len(t, api.targets, 5, "No Target should have been created")
+}
+
+func TestEnsureTriggersOnLaterPage(t *testing.T) {
+	const (
+		title    = "io.triggermesh.zendesksource.ns.name"
+		targetID = "42"
+	)
+
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 4; i++ {
+		api.addTrigger("trigger-" + strconv.Itoa(i))
+	}
+	api.addTrigger(triggerTitle(title, v1alpha1.ZendeskTicketCreated))
+	api.addTrigger(triggerTitle(title, v1alpha1.ZendeskTicketSolved))
+
+	status := &v1alpha1.ZendeskSourceStatus{}
+	kinds := []v1alpha1.ZendeskEventKind{v1alpha1.ZendeskTicketCreated}
+
+	err := ensureTriggers(testContext(), status, api.client(), title, targetID, kinds, nil)
+	require.NoError(t, err)
+
+	titles := api.triggerTitles()
+	assert.Len(t, titles, 5, "Only the Trigger of the removed event kind should have been deleted")
+	assert.Contains(t, titles, triggerTitle(title, v1alpha1.ZendeskTicketCreated))
+	assert.NotContains(t, titles, triggerTitle(title, v1alpha1.ZendeskTicketSolved))
+}
+
+func TestEnsureNoTriggerAndTargetOnLaterPage(t *testing.T) {
+	const title = "io.triggermesh.zendesksource.ns.name"
+
+	api := newFakeZendeskAPI(t)
+	for i := 0; i < 4; i++ {
+		api.addTarget("target-" + strconv.Itoa(i))
+		api.addTrigger("trigger-" + strconv.Itoa(i))
+	}
+	api.addTarget(title)
+	api.addTrigger(triggerTitle(title, v1alpha1.ZendeskTicketCreated))
+	api.addTrigger(triggerTitle(title, v1alpha1.ZendeskCommentAdded))
+
+	ctx := testContext()
+	client := api.client()
+
+	require.NoError(t, ensureNoTrigger(ctx, client, title))
+	require.NoError(t, ensureNoTarget(ctx, client, title))
+
+	assert.Len(t, api.targets, 4)
+	assert.NotContains(t, api.triggerTitles(), triggerTitle(title, v1alpha1.ZendeskTicketCreated))
+	assert.NotContains(t, api.triggerTitles(), triggerTitle(title, v1alpha1.ZendeskCommentAdded))
+	assert.Len(t, api.triggers, 4)
+}
+
+// testContext returns a context suitable for calling functions which record
+// API events about a ZendeskSource.
+func testContext() context.Context {
+	ctx := controller.WithEventRecorder(context.Background(), record.NewFakeRecorder(10))
+	return v1alpha1.WithSource(ctx, &v1alpha1.ZendeskSource{})
+}
+
+// fakeZendeskAPI is a fake implementation of the Zendesk API which serves
+// Targets and Triggers in pages of tPageSize items.
+type fakeZendeskAPI struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	lastID   int64
+	targets  []zendesk.Target
+	triggers []zendesk.Trigger
+
+	// page number for which requests are denied, if not zero
+	denyPage int
+	// base URL of the "next_page" links, if different from the server's
+	nextPageURL string
+
+	// number of requests received
+	requests int
+}
+
+// newFakeZendeskAPI starts a fakeZendeskAPI which is stopped at the end of
+// the given test.
+func newFakeZendeskAPI(t *testing.T) *fakeZendeskAPI {
+	api := &fakeZendeskAPI{t: t}
+	api.srv = httptest.NewServer(api)
+	t.Cleanup(api.srv.Close)
+	return api
+}
+
+// client returns an apiClient which sends requests to the fake API.
+func (a *fakeZendeskAPI) client() *apiClient {
+	cred := zendesk.NewAPITokenCredential(tZendeskEmail, tZendeskToken)
+
+	client, err := zendesk.NewClient(a.srv.Client())
+	require.NoError(a.t, err)
+	require.NoError(a.t, client.SetEndpointURL(a.srv.URL+"/api/v2"))
+	client.SetCredential(cred)
+
+	accountURL, err := url.Parse(a.srv.URL)
+	require.NoError(a.t, err)
+
+	return &apiClient{
+		Client:     client,
+		httpClient: a.srv.Client(),
+		cred:       cred,
+		accountURL: accountURL,
+		maxPages:   maxListPages,
+	}
+}
+
+func (a *fakeZendeskAPI) addTarget(title string) zendesk.Target {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lastID++
+	trg := *desiredTarget(title, "https://adapter.example.com", "", "")
+	trg.ID = a.lastID
+	trg.Active = true
+	a.targets = append(a.targets, trg)
+
+	return trg
+}
+
+func (a *fakeZendeskAPI) addTrigger(title string) zendesk.Trigger {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lastID++
+	trg := zendesk.Trigger{
+		ID:     a.lastID,
+		Title:  title,
+		Active: true,
+	}
+	a.triggers = append(a.triggers, trg)
+
+	return trg
+}
+
+func (a *fakeZendeskAPI) triggerTitles() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	titles := make([]string, len(a.triggers))
+	for i, t := range a.triggers {
+		titles[i] = t.Title
+	}
+	return titles
+}
+
+// ServeHTTP implements http.Handler.
+func (a *fakeZendeskAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.requests++
+
+	if user, pass, _ := r.BasicAuth(); user != tZendeskEmail+"/token" || pass != tZendeskToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const (
+		targetsPath  = "/api/v2/targets"
+		triggersPath = "/api/v2/triggers"
+	)
+
+	switch path := strings.TrimSuffix(r.URL.Path, ".json"); {
+	case path == targetsPath && r.Method == http.MethodGet:
+		page, ok := a.page(w, r)
+		if !ok {
+			return
+		}
+		from, to, next := a.pageBounds(r, page, len(a.targets))
+		a.respond(w, map[string]interface{}{
+			"targets":   a.targets[from:to],
+			"next_page": next,
+			"count":     len(a.targets),
+		})
+
+	case path == triggersPath && r.Method == http.MethodGet:
+		page, ok := a.page(w, r)
+		if !ok {
+			return
+		}
+		from, to, next := a.pageBounds(r, page, len(a.triggers))
+		a.respond(w, map[string]interface{}{
+			"triggers":  a.triggers[from:to],
+			"next_page": next,
+			"count":     len(a.triggers),
+		})
+
+	case strings.HasPrefix(path, targetsPath+"/") && r.Method == http.MethodDelete:
+		id := a.id(path)
+		for i, t := range a.targets {
+			if t.ID == id {
+				a.targets = append(a.targets[:i], a.targets[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+
+	case strings.HasPrefix(path, triggersPath+"/") && r.Method == http.MethodDelete:
+		id := a.id(path)
+		for i, t := range a.triggers {
+			if t.ID == id {
+				a.triggers = append(a.triggers[:i], a.triggers[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+
+	case strings.HasPrefix(path, triggersPath+"/") && r.Method == http.MethodPut:
+		var data struct {
+			Trigger zendesk.Trigger `json:"trigger"`
+		}
+		require.NoError(a.t, json.NewDecoder(r.Body).Decode(&data))
+		id := a.id(path)
+		for i, t := range a.triggers {
+			if t.ID == id {
+				a.triggers[i] = data.Trigger
+				a.respond(w, data)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+
+	default:
+		a.t.Errorf("Unexpected request to the Zendesk API: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// page returns the page number requested in r. It responds with an error if
+// the page is denied.
+func (a *fakeZendeskAPI) page(w http.ResponseWriter, r *http.Request) (int, bool) {
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		var err error
+		page, err = strconv.Atoi(p)
+		require.NoError(a.t, err)
+	}
+
+	if page == a.denyPage {
+		w.WriteHeader(http.StatusForbidden)
+		return 0, false
+	}
+
+	return page, true
+}
+
+// pageBounds returns the bounds of the given page within a list of count
+// items, and the URL of the next page, if any.
+func (a *fakeZendeskAPI) pageBounds(r *http.Request, page, count int) (from, to int, next *string) {
+	from = (page - 1) * tPageSize
+	if from > count {
+		from = count
+	}
+	to = from + tPageSize
+	if to > count {
+		to = count
+	}
+
+	if to < count {
+		baseURL := a.srv.URL
+		if a.nextPageURL != "" {
+			baseURL = a.nextPageURL
+		}
+		nextURL := baseURL + r.URL.Path + "?page=" + strconv.Itoa(page+1)
+		next = &nextURL
+	}
+
+	return from, to, next
+}
+
+func (a *fakeZendeskAPI) id(path string) int64 {
+	id, err := strconv.ParseInt(path[strings.LastIndex(path, "/")+1:], 10, 64)
+	require.NoError(a.t, err)
+	return id
+}
+
+func (a *fakeZendeskAPI) respond(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	require.NoError(a.t, json.NewEncoder(w).Encode(v))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zendesksource
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nukosuke/go-zendesk/zendesk"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+// Operators of Trigger conditions, by whether they compare the ticket field
+// to a value.
+// See: https://developer.zendesk.com/api-reference/ticketing/business-rules/conditions/
+var (
+	valueOperators = map[string]bool{
+		"is":                     true,
+		"is_not":                 true,
+		"less_than":              true,
+		"greater_than":           true,
+		"value":                  true,
+		"value_previous":         true,
+		"not_value":              true,
+		"not_value_previous":     true,
+		"includes":               true,
+		"not_includes":           true,
+		"within_previous_n_days": true,
+	}
+
+	valuelessOperators = map[string]bool{
+		"changed":     true,
+		"not_changed": true,
+		"present":     true,
+		"not_present": true,
+	}
+)
+
+// reservedConditionFields are ticket fields which conditions are set by the
+// reconciler to match kinds of ticket events.
+var reservedConditionFields = map[string]bool{
+	"update_type": true,
+}
+
+// validateConditions returns an error if any of the given Trigger conditions
+// is invalid.
+func validateConditions(conds *v1alpha1.ZendeskTriggerConditions) error {
+	if conds == nil {
+		return nil
+	}
+
+	for _, c := range []struct {
+		group string
+		conds []v1alpha1.ZendeskTriggerCondition
+	}{
+		{"all", conds.All},
+		{"any", conds.Any},
+	} {
+		for i, cond := range c.conds {
+			if err := validateCondition(&cond); err != nil { //nolint:scopelint,gosec
+				return fmt.Errorf("condition %s[%d]: %w", c.group, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCondition returns an error if the given Trigger condition is
+// invalid.
+func validateCondition(cond *v1alpha1.ZendeskTriggerCondition) error {
+	switch {
+	case cond.Field == "":
+		return errors.New("field is required")
+	case reservedConditionFields[cond.Field]:
+		return fmt.Errorf("field %q is reserved, it is set according to the kinds of events", cond.Field)
+	case cond.Operator == "":
+		return errors.New("operator is required")
+	}
+
+	switch {
+	case valueOperators[cond.Operator]:
+		if cond.Value == nil {
+			return fmt.Errorf("operator %q requires a value", cond.Operator)
+		}
+	case valuelessOperators[cond.Operator]:
+		if cond.Value != nil {
+			return fmt.Errorf("operator %q doesn't accept a value", cond.Operator)
+		}
+	default:
+		return fmt.Errorf("unsupported operator %q", cond.Operator)
+	}
+
+	return nil
+}
+
+// zendeskConditions converts the given Trigger conditions to their Zendesk
+// API representation.
+func zendeskConditions(conds []v1alpha1.ZendeskTriggerCondition) []zendesk.TriggerCondition {
+	zdConds := make([]zendesk.TriggerCondition, len(conds))
+
+	for i, c := range conds {
+		zdConds[i] = zendesk.TriggerCondition{
+			Field:    c.Field,
+			Operator: c.Operator,
+		}
+		if c.Value != nil {
+			zdConds[i].Value = *c.Value
+		}
+	}
+
+	return zdConds
+}
+
+// conditionsEqual returns whether the given Trigger conditions are equal.
+// Values are compared by their string representation, because Zendesk may
+// return the values of numeric fields, such as IDs, as numbers.
+func conditionsEqual(a, b zendesk.TriggerCondition) bool {
+	return a.Field == b.Field &&
+		a.Operator == b.Operator &&
+		conditionValue(a.Value) == conditionValue(b.Value)
+}
+
+// conditionValue returns the string representation of the value of a
+// Trigger condition.
+func conditionValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		// numbers decoded from JSON, formatted without exponent
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zendesksource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nukosuke/go-zendesk/zendesk"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestValidateConditions(t *testing.T) {
+	val := func(v string) *string { return &v }
+
+	testCases := map[string]struct {
+		conds     *v1alpha1.ZendeskTriggerConditions
+		expectErr string
+	}{
+		"no conditions": {
+			conds: nil,
+		},
+		"valid conditions": {
+			conds: &v1alpha1.ZendeskTriggerConditions{
+				All: []v1alpha1.ZendeskTriggerCondition{
+					{Field: "group_id", Operator: "is", Value: val("360000000001")},
+				},
+				Any: []v1alpha1.ZendeskTriggerCondition{
+					{Field: "current_tags", Operator: "includes", Value: val("vip")},
+					{Field: "priority", Operator: "changed"},
+				},
+			},
+		},
+		"missing field": {
+			conds: &v1alpha1.ZendeskTriggerConditions{
+				All: []v1alpha1.ZendeskTriggerCondition{
+					{Operator: "is", Value: val("360000000001")},
+				},
+			},
+			expectErr: "condition all[0]: field is required",
+		},
+		"reserved field": {
+			conds: &v1alpha1.ZendeskTriggerConditions{
+				All: []v1alpha1.ZendeskTriggerCondition{
+					{Field: "update_type", Operator: "is", Value: val("Change")},
+				},
+			},
+			expectErr: `condition all[0]: field "update_type" is reserved, it is set according to the kinds of events`,
+		},
+		"unsupported operator": {
+			conds: &v1alpha1.ZendeskTriggerConditions{
+				Any: []v1alpha1.ZendeskTriggerCondition{
+					{Field: "brand_id", Operator: "is", Value: val("1")},
+					{Field: "brand_id", Operator: "matches", Value: val("2")},
+				},
+			},
+			expectErr: `condition any[1]: unsupported operator "matches"`,
+		},
+		"missing value": {
+			conds: &v1alpha1.ZendeskTriggerConditions{
+				All: []v1alpha1.ZendeskTriggerCondition{
+					{Field: "brand_id", Operator: "is_not"},
+				},
+			},
+			expectErr: `condition all[0]: operator "is_not" requires a value`,
+		},
+		"unexpected value": {
+			conds: &v1alpha1.ZendeskTriggerConditions{
+				All: []v1alpha1.ZendeskTriggerCondition{
+					{Field: "assignee_id", Operator: "changed", Value: val("1")},
+				},
+			},
+			expectErr: `condition all[0]: operator "changed" doesn't accept a value`,
+		},
+	}
+
+	for name, tc := range testCases {
+		//nolint:scopelint
+		t.Run(name, func(t *testing.T) {
+			err := validateConditions(tc.conds)
+			if tc.expectErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestConditionsEqual(t *testing.T) {
+	desired := zendesk.TriggerCondition{Field: "group_id", Operator: "is", Value: "360000000001"}
+
+	// numeric values are decoded from JSON as float64
+	assert.True(t, conditionsEqual(desired,
+		zendesk.TriggerCondition{Field: "group_id", Operator: "is", Value: float64(360000000001)}))
+	assert.False(t, conditionsEqual(desired,
+		zendesk.TriggerCondition{Field: "group_id", Operator: "is", Value: "360000000002"}))
+	assert.True(t, conditionsEqual(
+		zendesk.TriggerCondition{Field: "priority", Operator: "changed"},
+		zendesk.TriggerCondition{Field: "priority", Operator: "changed", Value: nil}))
+}
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -62,6 +63,11 @@ func (r *Reconciler) ensureZendeskTargetAndTrigger(ctx context.Context) error {
 
 	spec := src.(pkgapis.HasSpec).GetUntypedSpec().(v1alpha1.ZendeskSourceSpec)
 
+	if err := validateConditions(spec.Conditions); err != nil {
+		status.MarkTargetNotSynced(v1alpha1.ZendeskReasonInvalidConditions, err.Error())
+		return controller.NewPermanentError(fmt.Errorf("invalid Trigger conditions: %w", err))
+	}
+
 	sg := secret.NewGetter(r.secretClient(src.GetNamespace()))
 
 	secrets, err := sg.Get(spec.Token, spec.WebhookPassword)
@@ -87,8 +93,8 @@ func (r *Reconciler) ensureZendeskTargetAndTrigger(ctx context.Context) error {
 		return err
 	}
 
-	err = ensureTrigger(ctx, status, client,
-		desiredTrigger(title, strconv.FormatInt(currentTarget.ID, 10)),
+	err = ensureTriggers(ctx, status, client, title, strconv.FormatInt(currentTarget.ID, 10),
+		src.(*v1alpha1.ZendeskSource).GetEventKinds(), spec.Conditions,
 	)
 	if err != nil {
 		return err
@@ -110,34 +116,104 @@ func desiredTarget(title, url, webhookUsername, webhookPassword string) *zendesk
 	}
 }
 
-func desiredTrigger(title, targetID string) *zendesk.Trigger {
+// eventKinds are all the kinds of ticket events a Trigger can be provisioned
+// for.
+var eventKinds = []v1alpha1.ZendeskEventKind{
+	v1alpha1.ZendeskTicketCreated,
+	v1alpha1.ZendeskTicketUpdated,
+	v1alpha1.ZendeskTicketSolved,
+	v1alpha1.ZendeskTicketReopened,
+	v1alpha1.ZendeskCommentAdded,
+	v1alpha1.ZendeskAssigneeChanged,
+	v1alpha1.ZendeskPriorityChanged,
+}
+
+// desiredTrigger returns the Trigger for the given kind of ticket event,
+// restricted by the given user-defined conditions, if any.
+func desiredTrigger(title, targetID string, kind v1alpha1.ZendeskEventKind,
+	conds *v1alpha1.ZendeskTriggerConditions) *zendesk.Trigger {
+
 	trg := &zendesk.Trigger{
-		Title: title,
+		Title: triggerTitle(title, kind),
 		Actions: []zendesk.TriggerAction{{
 			Field: "notification_target",
 			Value: []interface{}{
 				targetID,
-				triggerPayloadJSON,
+				triggerPayload(kind),
 			},
 		}},
 	}
-	trg.Conditions.All = []zendesk.TriggerCondition{{
-		Field:    "update_type",
-		Operator: "is",
-		Value:    "Create",
-	}}
+	trg.Conditions.All = triggerConditions(kind)
 	trg.Conditions.Any = make([]zendesk.TriggerCondition, 0)
 
+	if conds != nil {
+		trg.Conditions.All = append(trg.Conditions.All, zendeskConditions(conds.All)...)
+		trg.Conditions.Any = append(trg.Conditions.Any, zendeskConditions(conds.Any)...)
+	}
+
 	return trg
 }
 
+// triggerConditions returns the conditions under which the Trigger for the
+// given kind of ticket event fires.
+// See: https://developer.zendesk.com/api-reference/ticketing/business-rules/conditions/
+func triggerConditions(kind v1alpha1.ZendeskEventKind) []zendesk.TriggerCondition {
+	if kind == v1alpha1.ZendeskTicketCreated {
+		return []zendesk.TriggerCondition{
+			{Field: "update_type", Operator: "is", Value: "Create"},
+		}
+	}
+
+	conds := []zendesk.TriggerCondition{
+		{Field: "update_type", Operator: "is", Value: "Change"},
+	}
+
+	switch kind {
+	case v1alpha1.ZendeskTicketSolved:
+		conds = append(conds,
+			// "value" means "changed to"
+			zendesk.TriggerCondition{Field: "status", Operator: "value", Value: "solved"},
+		)
+	case v1alpha1.ZendeskTicketReopened:
+		conds = append(conds,
+			// "value_previous" means "changed from"
+			zendesk.TriggerCondition{Field: "status", Operator: "value_previous", Value: "solved"},
+			// solved tickets are eventually closed by Zendesk
+			zendesk.TriggerCondition{Field: "status", Operator: "is_not", Value: "closed"},
+		)
+	case v1alpha1.ZendeskCommentAdded:
+		conds = append(conds,
+			// "not_relevant" means "present (public or private)"
+			zendesk.TriggerCondition{Field: "comment_is_public", Operator: "is", Value: "not_relevant"},
+		)
+	case v1alpha1.ZendeskAssigneeChanged:
+		conds = append(conds,
+			zendesk.TriggerCondition{Field: "assignee_id", Operator: "changed"},
+		)
+	case v1alpha1.ZendeskPriorityChanged:
+		conds = append(conds,
+			zendesk.TriggerCondition{Field: "priority", Operator: "changed"},
+		)
+	}
+
+	return conds
+}
+
+// triggerPayload returns the JSON payload sent by the Trigger for the given
+// kind of ticket event.
+func triggerPayload(kind v1alpha1.ZendeskEventKind) string {
+	var extraFields string
+	if kind == v1alpha1.ZendeskCommentAdded {
+		extraFields = commentPayloadJSON
+	}
+
+	return fmt.Sprintf(triggerPayloadJSON, kind, extraFields)
+}
+
 func ensureTarget(ctx context.Context, status *v1alpha1.ZendeskSourceStatus,
-	client *zendesk.Client, desired *zendesk.Target) (*zendesk.Target, error) {
+	client *apiClient, desired *zendesk.Target) (*zendesk.Target, error) {
 
-	// TODO: It could happen that the target already exists but is in a
-	// different page. We will need to support pagination in a future
-	// release of this source.
-	targets, _, err := client.GetTargets(ctx)
+	targets, err := client.listTargets(ctx)
 	switch {
 	case isDenied(err):
 		return nil, controller.NewPermanentError(formatError(err))
@@ -167,7 +243,7 @@ func ensureTarget(ctx context.Context, status *v1alpha1.ZendeskSourceStatus,
 	return &target, nil
 }
 
-func syncTarget(ctx context.Context, client *zendesk.Client, current, desired *zendesk.Target) (*zendesk.Target, error) {
+func syncTarget(ctx context.Context, client *apiClient, current, desired *zendesk.Target) (*zendesk.Target, error) {
 	// copy fields which are set by the API
 	desired.URL = current.URL
 	desired.ID = current.ID
@@ -194,18 +270,49 @@ func syncTarget(ctx context.Context, client *zendesk.Client, current, desired *z
 	return &target, nil
 }
 
-func ensureTrigger(ctx context.Context, status *v1alpha1.ZendeskSourceStatus,
-	client *zendesk.Client, desired *zendesk.Trigger) error {
+// ensureTriggers ensures that a Trigger exists for each of the given kinds of
+// ticket events, and that no Trigger exists for the other kinds.
+func ensureTriggers(ctx context.Context, status *v1alpha1.ZendeskSourceStatus, client *apiClient,
+	title, targetID string, kinds []v1alpha1.ZendeskEventKind, conds *v1alpha1.ZendeskTriggerConditions) error {
 
-	// TODO: It could happen that the trigger already exists but is in a
-	// different page. We will need to support pagination in a future
-	// release of this source.
-	triggers, _, err := client.GetTriggers(ctx, &zendesk.TriggerListOptions{})
+	triggers, err := client.listTriggers(ctx)
 	if err != nil {
 		status.MarkTargetNotSynced(v1alpha1.ZendeskReasonFailedSync, "Unable to list Triggers")
 		return fmt.Errorf("retrieving Zendesk Triggers: %w", formatError(err))
 	}
 
+	wantKinds := make(map[v1alpha1.ZendeskEventKind]bool, len(kinds))
+	for _, k := range kinds {
+		wantKinds[k] = true
+	}
+
+	for _, kind := range eventKinds {
+		if wantKinds[kind] {
+			desired := desiredTrigger(title, targetID, kind, conds)
+			if err := ensureTrigger(ctx, status, client, triggers, desired); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// the kind was possibly removed from the source's spec
+		t := findTrigger(triggers, triggerTitle(title, kind))
+		if t == nil {
+			continue
+		}
+		if err := client.DeleteTrigger(ctx, t.ID); err != nil {
+			status.MarkTargetNotSynced(v1alpha1.ZendeskReasonFailedSync, "Unable to delete Trigger")
+			return fmt.Errorf("deleting Zendesk Trigger: %w", formatError(err))
+		}
+		event.Normal(ctx, ReasonTargetDeleted, "Zendesk Trigger %q was deleted", t.Title)
+	}
+
+	return nil
+}
+
+func ensureTrigger(ctx context.Context, status *v1alpha1.ZendeskSourceStatus,
+	client *apiClient, triggers []zendesk.Trigger, desired *zendesk.Trigger) error {
+
 	for _, t := range triggers {
 		if t.Title == desired.Title {
 			err := syncTrigger(ctx, client, &t, desired) //nolint:scopelint,gosec
@@ -226,7 +333,7 @@ func ensureTrigger(ctx context.Context, status *v1alpha1.ZendeskSourceStatus,
 	return nil
 }
 
-func syncTrigger(ctx context.Context, client *zendesk.Client, current, desired *zendesk.Trigger) error {
+func syncTrigger(ctx context.Context, client *apiClient, current, desired *zendesk.Trigger) error {
 	// copy fields which are set by the API
 	desired.ID = current.ID
 	desired.Position = current.Position
@@ -234,7 +341,7 @@ func syncTrigger(ctx context.Context, client *zendesk.Client, current, desired *
 	desired.UpdatedAt = current.UpdatedAt
 	desired.Active = true
 
-	if cmp.Equal(current, desired) {
+	if cmp.Equal(current, desired, cmp.Comparer(conditionsEqual)) {
 		return nil
 	}
 
@@ -286,8 +393,8 @@ func (r *Reconciler) ensureNoZendeskTargetAndTrigger(ctx context.Context) error
 	return ensureNoTarget(ctx, client, title)
 }
 
-func ensureNoTrigger(ctx context.Context, client *zendesk.Client, title string) error {
-	triggers, _, err := client.GetTriggers(ctx, &zendesk.TriggerListOptions{})
+func ensureNoTrigger(ctx context.Context, client *apiClient, title string) error {
+	triggers, err := client.listTriggers(ctx)
 	switch {
 	case isDenied(err):
 		// it is unlikely that we recover from auth errors in the
@@ -307,28 +414,36 @@ func ensureNoTrigger(ctx context.Context, client *zendesk.Client, title string)
 			"Error retrieving Zendesk Triggers: %s", formatError(err))
 	}
 
-	var currentTrigger *zendesk.Trigger
-	for _, t := range triggers {
-		if t.Title == title {
-			currentTrigger = &t //nolint:scopelint,exportloopref,gosec
-			break
+	for _, kind := range eventKinds {
+		trgTitle := triggerTitle(title, kind)
+
+		currentTrigger := findTrigger(triggers, trgTitle)
+		if currentTrigger == nil {
+			continue
 		}
-	}
-	if currentTrigger == nil {
-		return nil
-	}
 
-	if err := client.DeleteTrigger(ctx, currentTrigger.ID); err != nil {
-		return reconciler.NewEvent(corev1.EventTypeWarning, ReasonFailedTargetDelete,
-			"Error finalizing Zendesk Trigger %q: %s", title, formatError(err))
+		if err := client.DeleteTrigger(ctx, currentTrigger.ID); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, ReasonFailedTargetDelete,
+				"Error finalizing Zendesk Trigger %q: %s", trgTitle, formatError(err))
+		}
+		event.Normal(ctx, ReasonTargetDeleted, "Zendesk Trigger %q was deleted", trgTitle)
 	}
-	event.Normal(ctx, ReasonTargetDeleted, "Zendesk Trigger %q was deleted", title)
 
 	return nil
 }
 
-func ensureNoTarget(ctx context.Context, client *zendesk.Client, title string) error {
-	targets, _, err := client.GetTargets(ctx)
+// findTrigger returns the Trigger with the given title, if any.
+func findTrigger(triggers []zendesk.Trigger, title string) *zendesk.Trigger {
+	for i := range triggers {
+		if triggers[i].Title == title {
+			return &triggers[i]
+		}
+	}
+	return nil
+}
+
+func ensureNoTarget(ctx context.Context, client *apiClient, title string) error {
+	targets, err := client.listTargets(ctx)
 	switch {
 	case isDenied(err):
 		// it is unlikely that we recover from auth errors in the
@@ -374,26 +489,23 @@ func targetTitle(src metav1.Object) string {
 	return "io.triggermesh.zendesksource." + src.GetNamespace() + "." + src.GetName()
 }
 
-// zendeskClient returns an initialized Zendesk client.
-func zendeskClient(email, subdomain, apiToken string) (*zendesk.Client, error) {
-	cred := zendesk.NewAPITokenCredential(email, apiToken)
-	client, err := zendesk.NewClient(nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating Zendesk client: %w", err)
+// triggerTitle returns the title of the Zendesk Trigger for the given kind of
+// ticket event. The Trigger for created tickets uses the title of the Target,
+// which was the title of the source's single Trigger before other kinds of
+// events were supported.
+func triggerTitle(title string, kind v1alpha1.ZendeskEventKind) string {
+	if kind == v1alpha1.ZendeskTicketCreated {
+		return title
 	}
-	if err := client.SetSubdomain(subdomain); err != nil {
-		return nil, fmt.Errorf("setting Zendesk subdomain: %w", err)
-	}
-	client.SetCredential(cred)
-
-	return client, nil
+	return title + "." + strings.TrimPrefix(v1alpha1.ZendeskEventType(kind), "com.zendesk.")
 }
 
 // isDenied returns whether the given error indicates that a request was denied
 // due to authentication issues.
 func isDenied(err error) bool {
-	if zdErr := (zendesk.Error{}); errors.As(err, &zdErr) {
-		s := zdErr.Status()
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		s := apiErr.Status()
 		return s == http.StatusUnauthorized || s == http.StatusForbidden
 	}
 	return false
@@ -402,16 +514,18 @@ func isDenied(err error) bool {
 // isNotFound returns whether the given error indicates that a Zendesk resource
 // (account, target, trigger) does not exist.
 func isNotFound(err error) bool {
-	if zdErr := (zendesk.Error{}); errors.As(err, &zdErr) {
-		return zdErr.Status() == http.StatusNotFound
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status() == http.StatusNotFound
 	}
 	return false
 }
 
 // formatError formats Zendesk errors.
 func formatError(origErr error) error {
-	if zdErr := (zendesk.Error{}); errors.As(origErr, &zdErr) {
-		rawErrBody, err := ioutil.ReadAll(zdErr.Body())
+	var apiErr apiError
+	if errors.As(origErr, &apiErr) {
+		rawErrBody, err := ioutil.ReadAll(apiErr.Body())
 		if err != nil {
 			return origErr
 		}
@@ -441,13 +555,20 @@ func (b *zendeskError) Error() string {
 	return b.Err.Title + ": " + b.Err.Message
 }
 
+// triggerPayloadJSON is the format of the JSON payload sent by Triggers. It
+// accepts the kind of ticket event and optional extra fields.
+//
+// Placeholders are not escaped by Zendesk, so free-text fields, which may
+// contain quotes or line breaks, are rendered as JSON strings by the "json"
+// Liquid filter instead of being enclosed in quotes.
 const triggerPayloadJSON = `{
+  "event_kind": "%s",%s
   "ticket": {
     "id": {{ticket.id}},
     "external_id": "{{ticket.external_id}}",
-    "title": "{{ticket.title}}",
+    "title": {{ticket.title | json}},
     "url": "{{ticket.url}}",
-    "description": "{{ticket.description}}",
+    "description": {{ticket.description | json}},
     "via": "{{ticket.via}}",
     "status": "{{ticket.status}}",
     "priority": "{{ticket.priority}}",
@@ -471,13 +592,13 @@ const triggerPayloadJSON = `{
       "phone": "{{ticket.requester.phone}}",
       "external_id": "{{ticket.requester.external_id}}",
       "field": "{{ticket.requester_field}}",
-      "details": "{{ticket.requester.details}}"
+      "details": {{ticket.requester.details | json}}
     },
     "organization": {
       "name": "{{ticket.organization.name}}",
       "external_id": "{{ticket.organization.external_id}}",
-      "details": "{{ticket.organization.details}}",
-      "notes": "{{ticket.organization.notes}}"
+      "details": {{ticket.organization.details | json}},
+      "notes": {{ticket.organization.notes | json}}
     },
     "ccs": "{{ticket.ccs}}",
     "cc_names": "{{ticket.cc_names}}",
@@ -492,17 +613,28 @@ const triggerPayloadJSON = `{
     "email": "{{current_user.email}}",
     "organization": {
       "name": "{{current_user.organization.name}}",
-      "notes": "{{current_user.organization.notes}}",
-      "details": "{{current_user.organization.details}}"
+      "notes": {{current_user.organization.notes | json}},
+      "details": {{current_user.organization.details | json}}
     },
     "external_id": "{{current_user.external_id}}",
     "phone": "{{current_user.phone}}",
-    "details": "{{current_user.details}}",
-    "notes": "{{current_user.notes}}",
+    "details": {{current_user.details | json}},
+    "notes": {{current_user.notes | json}},
     "language": "{{current_user.language}}"
   },
   "satisfaction": {
     "current_rating": "{{satisfaction.current_rating}}",
-    "current_comment": "{{satisfaction.current_comment}}"
+    "current_comment": {{satisfaction.current_comment | json}}
   }
 }`
+
+// commentPayloadJSON contains the fields of the JSON payload which describe
+// the latest comment of a ticket.
+const commentPayloadJSON = `
+  "comment": {
+    "id": {{ticket.latest_comment.id}},
+    "value": {{ticket.latest_comment.value | json}},
+    "author_name": "{{ticket.latest_comment.author.name}}",
+    "is_public": "{{ticket.latest_comment.is_public}}",
+    "created_at": "{{ticket.latest_comment.created_at}}"
+  },`
@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zendesksource
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nukosuke/go-zendesk/zendesk"
+
+	"github.com/triggermesh/knative-sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestDesiredTrigger(t *testing.T) {
+	const (
+		title    = "io.triggermesh.zendesksource.ns.name"
+		targetID = "42"
+	)
+
+	testCases := map[v1alpha1.ZendeskEventKind]struct {
+		expectTitle      string
+		expectConditions []zendesk.TriggerCondition
+	}{
+		v1alpha1.ZendeskTicketCreated: {
+			expectTitle: title,
+			expectConditions: []zendesk.TriggerCondition{
+				{Field: "update_type", Operator: "is", Value: "Create"},
+			},
+		},
+		v1alpha1.ZendeskTicketSolved: {
+			expectTitle: title + ".ticket.solved",
+			expectConditions: []zendesk.TriggerCondition{
+				{Field: "update_type", Operator: "is", Value: "Change"},
+				{Field: "status", Operator: "value", Value: "solved"},
+			},
+		},
+		v1alpha1.ZendeskCommentAdded: {
+			expectTitle: title + ".ticket.comment.added",
+			expectConditions: []zendesk.TriggerCondition{
+				{Field: "update_type", Operator: "is", Value: "Change"},
+				{Field: "comment_is_public", Operator: "is", Value: "not_relevant"},
+			},
+		},
+		v1alpha1.ZendeskAssigneeChanged: {
+			expectTitle: title + ".ticket.assignee.changed",
+			expectConditions: []zendesk.TriggerCondition{
+				{Field: "update_type", Operator: "is", Value: "Change"},
+				{Field: "assignee_id", Operator: "changed"},
+			},
+		},
+	}
+
+	for kind, tc := range testCases {
+		//nolint:scopelint
+		t.Run(string(kind), func(t *testing.T) {
+			trg := desiredTrigger(title, targetID, kind, nil)
+
+			assert.Equal(t, tc.expectTitle, trg.Title)
+			assert.Equal(t, tc.expectConditions, trg.Conditions.All)
+			assert.Empty(t, trg.Conditions.Any)
+
+			require.Len(t, trg.Actions, 1)
+			actionVal := trg.Actions[0].Value.([]interface{})
+			require.Len(t, actionVal, 2)
+			assert.Equal(t, targetID, actionVal[0])
+
+			payload := actionVal[1].(string)
+			assert.Contains(t, payload, `"event_kind": "`+string(kind)+`"`)
+			if kind == v1alpha1.ZendeskCommentAdded {
+				assert.Contains(t, payload, `"comment": {`)
+				assert.Contains(t, payload, "{{ticket.latest_comment.value | json}}", "Free-text field should be escaped")
+			} else {
+				assert.NotContains(t, payload, `"comment": {`)
+			}
+			for _, f := range []string{"ticket.title", "ticket.description", "satisfaction.current_comment"} {
+				assert.Contains(t, payload, "{{"+f+" | json}}", "Free-text field should be escaped")
+			}
+			assert.True(t, json.Valid(renderPlaceholders(payload)),
+				"Payload should be valid JSON when free-text fields contain special characters")
+		})
+	}
+}
+
+func TestDesiredTriggerWithConditions(t *testing.T) {
+	group := "360000000001"
+	tag := "vip"
+
+	conds := &v1alpha1.ZendeskTriggerConditions{
+		All: []v1alpha1.ZendeskTriggerCondition{
+			{Field: "group_id", Operator: "is", Value: &group},
+		},
+		Any: []v1alpha1.ZendeskTriggerCondition{
+			{Field: "current_tags", Operator: "includes", Value: &tag},
+			{Field: "priority", Operator: "changed"},
+		},
+	}
+
+	trg := desiredTrigger("title", "42", v1alpha1.ZendeskTicketUpdated, conds)
+
+	expectAll := []zendesk.TriggerCondition{
+		{Field: "update_type", Operator: "is", Value: "Change"},
+		{Field: "group_id", Operator: "is", Value: group},
+	}
+	expectAny := []zendesk.TriggerCondition{
+		{Field: "current_tags", Operator: "includes", Value: tag},
+		{Field: "priority", Operator: "changed"},
+	}
+
+	assert.Equal(t, expectAll, trg.Conditions.All)
+	assert.Equal(t, expectAny, trg.Conditions.Any)
+}
+
+// placeholderRegexp matches Zendesk placeholders, with an optional "json"
+// filter.
+var placeholderRegexp = regexp.MustCompile(`{{[a-zA-Z_.]+( \| json)?}}`)
+
+// renderPlaceholders mimics the rendering of the placeholders of the given
+// payload by Zendesk. Free-text values contain characters which must be
+// escaped in JSON strings.
+func renderPlaceholders(payload string) []byte {
+	return placeholderRegexp.ReplaceAllFunc([]byte(payload), func(p []byte) []byte {
+		if placeholderRegexp.FindSubmatch(p)[1] == nil {
+			return []byte("1")
+		}
+		val, _ := json.Marshal("some \"quoted\"\ntext")
+		return val
+	})
+}
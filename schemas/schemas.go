@@ -0,0 +1,400 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schemas exposes the JSON Schema documents of the events emitted by
+// sources with a built-in schema. The documents are kept identical to the
+// JSON files of this directory, which are the source of truth.
+package schemas
+
+// Base URI of the published JSON Schema documents.
+const baseURI = "https://raw.githubusercontent.com/triggermesh/knative-sources/main/schemas/"
+
+// URIs of the JSON Schema documents, suitable for the dataschema attribute of
+// CloudEvents.
+const (
+	// Slack events carry the inner "event" object of the Events API wrapper.
+	SlackEventsURI = baseURI + "com.slack.events.json#/properties/event"
+	// The document at the former URI of the schema of ticket creation
+	// events, com.zendesk.ticket.created.json, is kept as a copy for the
+	// consumers of events emitted by earlier versions.
+	ZendeskTicketURI = baseURI + "com.zendesk.ticket.json"
+)
+
+// SlackEvents is the schema of the Slack Events API wrapper.
+const SlackEvents = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Standard event wrapper for the Events API",
+  "description": "Adapted from auto-generated content",
+  "type": "object",
+  "additionalProperties": true,
+  "required": [
+    "token",
+    "team_id",
+    "api_app_id",
+    "event",
+    "type",
+    "event_id",
+    "event_time"
+  ],
+  "properties": {
+    "token": {
+      "title": "A verification token to validate the event originated from Slack",
+      "type": "string"
+    },
+    "team_id": {
+      "title": "The unique identifier of the workspace where the event occurred",
+      "type": "string",
+      "examples": [
+        "T1H9RESGL"
+      ]
+    },
+    "api_app_id": {
+      "title": "The unique identifier your installed Slack application.",
+      "description": " Use this to distinguish which app the event belongs to if you use multiple apps with the same Request URL.",
+      "type": "string",
+      "examples": [
+        "A2H9RFS1A"
+      ]
+    },
+    "event": {
+      "title": "The actual event, an object, that happened. You'll find the most variance in properties beneath this node.",
+      "type": "object",
+      "additionalProperties": true,
+      "required": [
+        "type",
+        "event_ts"
+      ],
+      "properties": {
+        "type": {
+          "title": "The specific name of the event",
+          "type": "string"
+        },
+        "event_ts": {
+          "title": "When the event was dispatched",
+          "type": "string"
+        }
+      },
+      "examples": [
+        {
+          "type": "message",
+          "user": "U061F7AUR",
+          "text": "How many cats did we herd yesterday?",
+          "ts": "1525215129.000001",
+          "channel": "D0PNCRP9N",
+          "event_ts": "1525215129.000001",
+          "channel_type": "app_home"
+        }
+      ]
+    },
+    "type": {
+      "title": "Indicates which kind of event dispatch this is, usually ` + "`" + `event_callback` + "`" + `",
+      "type": "string",
+      "examples": [
+        "event_callback"
+      ]
+    },
+    "event_id": {
+      "title": "A unique identifier for this specific event, globally unique across all workspaces.",
+      "type": "string",
+      "examples": [
+        "Ev0PV52K25"
+      ]
+    },
+    "event_time": {
+      "title": "The epoch timestamp in seconds indicating when this event was dispatched.",
+      "type": "integer",
+      "examples": [
+        1525215129
+      ]
+    },
+    "authed_users": {
+      "title": "An array of string-based User IDs. Each member of the collection represents a user that has installed your application/bot and indicates the described event would be visible to those users.",
+      "type": "array",
+      "minItems": 1,
+      "uniqueItems": true,
+      "items": {
+        "type": "string"
+      }
+    }
+  },
+  "examples": [
+    {
+      "token": "XXYYZZ",
+      "team_id": "TXXXXXXXX",
+      "api_app_id": "AXXXXXXXXX",
+      "event": {
+        "type": "resources_added",
+        "resources": [
+          {
+            "resource": {
+              "type": "im",
+              "grant": {
+                "type": "specific",
+                "resource_id": "DXXXXXXXX"
+              }
+            },
+            "scopes": [
+              "chat:write:user",
+              "im:read",
+              "im:history",
+              "commands"
+            ]
+          }
+        ]
+      },
+      "type": "event_callback",
+      "authed_teams": [],
+      "event_id": "EvXXXXXXXX",
+      "event_time": 1234567890
+    },
+    {
+      "token": "XXYYZZ",
+      "team_id": "TXXXXXXXX",
+      "api_app_id": "AXXXXXXXXX",
+      "event": {
+        "type": "reaction_added",
+        "user": "U024BE7LH",
+        "reaction": "thumbsup",
+        "item_user": "U0G9QF9C6",
+        "item": {
+          "type": "message",
+          "channel": "C0G9QF9GZ",
+          "ts": "1360782400.498405"
+        },
+        "event_ts": "1360782804.083113"
+      },
+      "type": "event_callback",
+      "authed_teams": [],
+      "event_id": "EvXXXXXXXX",
+      "event_time": 1234567890
+    }
+  ]
+}
+`
+
+// ZendeskTicket is the schema of the data of Zendesk ticket events, common to
+// all kinds of ticket events.
+const ZendeskTicket = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "event_kind": {
+      "type": "string"
+    },
+    "ticket": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "integer"
+        },
+        "external_id": {
+          "type": "string"
+        },
+        "title": {
+          "type": "string"
+        },
+        "url": {
+          "type": "string",
+          "format": "uri"
+        },
+        "description": {
+          "type": "string"
+        },
+        "via": {
+          "type": "string"
+        },
+        "status": {
+          "type": "string"
+        },
+        "priority": {
+          "type": "string"
+        },
+        "ticket_type": {
+          "type": "string"
+        },
+        "group_name": {
+          "type": "string"
+        },
+        "brand_name": {
+          "type": "string"
+        },
+        "due_date": {
+          "type": "string",
+          "anyOf": [
+            {
+              "format": "date"
+            },
+            {
+              "maxLength": 0
+            }
+          ]
+        },
+        "account": {
+          "type": "string"
+        },
+        "assignee": {
+          "email": {
+            "type": "string",
+            "format": "email"
+          },
+          "name": {
+            "type": "string"
+          },
+          "first_name": {
+            "type": "string"
+          },
+          "last_name": {
+            "type": "string"
+          }
+        },
+        "requester": {
+          "name": {
+            "type": "string"
+          },
+          "first_name": {
+            "type": "string"
+          },
+          "last_name": {
+            "type": "string"
+          },
+          "email": {
+            "type": "string"
+          },
+          "language": {
+            "type": "string"
+          },
+          "phone": {
+            "type": "string"
+          },
+          "external_id": {
+            "type": "string"
+          },
+          "field": {
+            "type": "string"
+          },
+          "details": {
+            "type": "string"
+          }
+        },
+        "organization": {
+          "name": {
+            "type": "string"
+          },
+          "external_id": {
+            "type": "string"
+          },
+          "details": {
+            "type": "string"
+          },
+          "notes": {
+            "type": "string"
+          }
+        },
+        "ccs": {
+          "type": "string"
+        },
+        "cc_names": {
+          "type": "string"
+        },
+        "tags": {
+          "type": "string"
+        },
+        "current_holiday_name": {
+          "type": "string"
+        },
+        "ticket_field_id": {
+          "type": "string"
+        },
+        "ticket_field_option_title_id": {
+          "type": "string"
+        }
+      }
+    },
+    "comment": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "integer"
+        },
+        "value": {
+          "type": "string"
+        },
+        "author_name": {
+          "type": "string"
+        },
+        "is_public": {
+          "type": "string"
+        },
+        "created_at": {
+          "type": "string"
+        }
+      }
+    },
+    "current_user": {
+      "type": "object",
+      "properties": {
+        "name": {
+          "type": "string"
+        },
+        "first_name": {
+          "type": "string"
+        },
+        "email": {
+          "type": "string"
+        },
+        "organization": {
+          "name": {
+            "type": "string"
+          },
+          "notes": {
+            "type": "string"
+          },
+          "details": {
+            "type": "string"
+          }
+        },
+        "external_id": {
+          "type": "string"
+        },
+        "phone": {
+          "type": "string"
+        },
+        "details": {
+          "type": "string"
+        },
+        "notes": {
+          "type": "string"
+        },
+        "language": {
+          "type": "string"
+        }
+      }
+    },
+    "satisfaction": {
+      "type": "object",
+      "properties": {
+        "current_rating": {
+          "type": "string"
+        },
+        "current_comment": {
+          "type": "string"
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,41 @@
+/*
+Copyright (c) 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemas
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemasInSync(t *testing.T) {
+	tc := map[string]string{
+		"com.slack.events.json":           SlackEvents,
+		"com.zendesk.ticket.json":         ZendeskTicket,
+		"com.zendesk.ticket.created.json": ZendeskTicket,
+	}
+
+	for file, doc := range tc {
+		t.Run(file, func(t *testing.T) {
+			b, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			assert.Equal(t, string(b), doc, "Schema is out of sync with "+file)
+		})
+	}
+}